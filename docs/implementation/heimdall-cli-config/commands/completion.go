@@ -0,0 +1,254 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"heimdall-cli/config"
+)
+
+// CompletionCmd generates shell completion scripts
+var CompletionCmd = &cobra.Command{
+	Use:   "completion <bash|zsh|fish>",
+	Short: "Generate shell completion scripts",
+	Long: `Generate a completion script for bash, zsh or fish.
+
+Fish:
+  heimdall-cli completion fish > ~/.config/fish/completions/heimdall-cli.fish
+
+Bash:
+  heimdall-cli completion bash > ~/.local/share/bash-completion/completions/heimdall-cli
+
+Zsh:
+  heimdall-cli completion zsh > "${fpath[1]}/_heimdall-cli"`,
+	Args:                  cobra.ExactArgs(1),
+	ValidArgs:             []string{"bash", "zsh", "fish"},
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root := cmd.Root()
+		out := cmd.OutOrStdout()
+
+		switch args[0] {
+		case "bash":
+			return root.GenBashCompletionV2(out, true)
+		case "zsh":
+			return root.GenZshCompletion(out)
+		case "fish":
+			return root.GenFishCompletion(out, true)
+		default:
+			return fmt.Errorf("unsupported shell: %s (use bash, zsh or fish)", args[0])
+		}
+	},
+}
+
+// completeConfigPaths suggests dotted schema paths for the first argument
+func completeConfigPaths(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return matchPaths(toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeResetPaths suggests dotted schema paths for every argument
+func completeResetPaths(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return matchPaths(toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSetArgs suggests a path, then values valid for that path
+func completeSetArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return matchPaths(toComplete), cobra.ShellCompDirectiveNoFileComp
+	case 1:
+		return completeValues(args[0], toComplete), cobra.ShellCompDirectiveNoFileComp
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeLockedPaths suggests the paths currently locked in the config
+func completeLockedPaths(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cfg := loadConfigQuietly()
+	if cfg == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return filterPrefix(cfg.Metadata.UserLocked, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProfiles suggests profile names
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	store := config.NewProfileStore(config.GetProfilesDir())
+	return filterPrefix(store.Names(), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeUserProfiles suggests user-defined profile names
+func completeUserProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	store := config.NewProfileStore(config.GetProfilesDir())
+	names := make([]string, 0)
+	for _, name := range store.Names() {
+		if !config.IsBuiltinProfile(name) {
+			names = append(names, name)
+		}
+	}
+	return filterPrefix(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeMonitors suggests the outputs configured in monitors
+func completeMonitors(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg := loadConfigQuietly()
+	if cfg == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := cfg.Monitors.MonitorNames()
+	if len(names) == 0 {
+		names = cfg.Wallpaper.Monitors
+	}
+	return filterPrefix(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeBackupIDs suggests backup identifiers, newest first
+func completeBackupIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	manager, err := config.NewConfigManager(NewLogger())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	backups, err := manager.ListBackups()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ids := make([]string, 0, len(backups))
+	for _, b := range backups {
+		if strings.HasPrefix(b.ID, toComplete) {
+			ids = append(ids, fmt.Sprintf("%s\t%s", b.ID, b.ModTime.Format("2006-01-02 15:04:05")))
+		}
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// completeVersions suggests schema versions known to the migrator
+func completeVersions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	migrator := config.NewVersionMigrator(config.GetBackupDir(), NewLogger())
+	return filterPrefix(migrator.GetAvailableVersions(), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// matchPaths returns schema leaves and sections starting with prefix
+func matchPaths(prefix string) []string {
+	candidates := append(config.SchemaSections(), config.SchemaPaths()...)
+	sort.Strings(candidates)
+	return filterPrefix(candidates, prefix)
+}
+
+// completeValues suggests values for a schema path
+func completeValues(path, toComplete string) []string {
+	field, ok := config.LookupField(path)
+	if !ok {
+		return nil
+	}
+
+	if len(field.Enum) > 0 {
+		return filterPrefix(field.Enum, toComplete)
+	}
+
+	if field.Kind == reflect.Bool {
+		return filterPrefix([]string{"true", "false"}, toComplete)
+	}
+
+	if _, ok := config.ToolAlternatives[path]; ok {
+		doctor := config.NewDoctor(config.OSProbe{})
+		return filterPrefix(doctor.InstalledAlternatives(path), toComplete)
+	}
+
+	if path == "wallpaper.path" || strings.HasPrefix(path, "monitors.") && strings.HasSuffix(path, ".wallpaper") {
+		return wallpaperFiles(toComplete)
+	}
+
+	if path == "appearance.colorScheme" {
+		return filterPrefix(config.NewSchemeRegistry(config.GetSchemesDir()).Names(), toComplete)
+	}
+
+	return nil
+}
+
+// wallpaperFiles lists image files under the configured wallpaper directory
+func wallpaperFiles(toComplete string) []string {
+	cfg := loadConfigQuietly()
+	if cfg == nil || cfg.Wallpaper.Directory == "" {
+		return nil
+	}
+
+	dir := config.ExpandHome(cfg.Wallpaper.Directory)
+	files := make([]string, 0)
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".png", ".jpg", ".jpeg", ".webp", ".gif", ".bmp":
+			if strings.HasPrefix(path, toComplete) {
+				files = append(files, path)
+			}
+		}
+		return nil
+	})
+	return files
+}
+
+// loadConfigQuietly loads the config for completions, ignoring errors
+func loadConfigQuietly() *config.ShellConfig {
+	manager, err := config.NewConfigManager(NewLogger())
+	if err != nil {
+		return nil
+	}
+	cfg, err := manager.Load()
+	if err != nil {
+		return nil
+	}
+	return cfg
+}
+
+// filterPrefix keeps the values that start with prefix
+func filterPrefix(values []string, prefix string) []string {
+	matches := make([]string, 0, len(values))
+	for _, v := range values {
+		if strings.HasPrefix(v, prefix) {
+			matches = append(matches, v)
+		}
+	}
+	return matches
+}
+
+func init() {
+	getCmd.ValidArgsFunction = completeConfigPaths
+	setCmd.ValidArgsFunction = completeSetArgs
+	lockCmd.ValidArgsFunction = completeConfigPaths
+	unlockCmd.ValidArgsFunction = completeLockedPaths
+	initCmd.ValidArgsFunction = completeProfiles
+	restoreCmd.ValidArgsFunction = completeBackupIDs
+	migrateCmd.ValidArgsFunction = completeVersions
+	for _, c := range []*cobra.Command{exportCmd, importCmd} {
+		c.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(config.Formats, cobra.ShellCompDirectiveNoFileComp))
+	}
+	setCmd.RegisterFlagCompletionFunc("layer", cobra.FixedCompletions(
+		[]string{config.LayerBase, config.LayerFragment, config.LayerHost}, cobra.ShellCompDirectiveNoFileComp))
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"heimdall-cli/config"
+)
+
+func TestMatchPaths(t *testing.T) {
+	got := matchPaths("bar.pa")
+	want := []string{"bar.padding", "bar.padding.bottom", "bar.padding.left", "bar.padding.right", "bar.padding.top"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matchPaths(bar.pa) = %v, want %v", got, want)
+	}
+
+	sections := matchPaths("appearance.col")
+	if i := sort.SearchStrings(sections, "appearance.colors"); len(sections) == 0 || sections[0] != "appearance.colorScheme" || i == len(sections) || sections[i] != "appearance.colors" {
+		t.Errorf("matchPaths(appearance.col) = %v, want leaves and sections", sections)
+	}
+	if !sort.StringsAreSorted(sections) {
+		t.Errorf("suggestions are not sorted: %v", sections)
+	}
+	if got := matchPaths("nope"); len(got) != 0 {
+		t.Errorf("matchPaths(nope) = %v, want none", got)
+	}
+}
+
+func TestCompleteValues(t *testing.T) {
+	t.Setenv("HEIMDALL_SCHEMES_DIR", t.TempDir())
+
+	tests := []struct {
+		path, prefix string
+		want         []string
+	}{
+		{"bar.position", "", config.FieldEnums["bar.position"]},
+		{"bar.position", "t", []string{"top"}},
+		{"monitors.DP-1.transform", "flipped-", []string{"flipped-90", "flipped-180", "flipped-270"}},
+		{"bar.blur", "", []string{"true", "false"}},
+		{"bar.height", "", nil},
+		{"no.such.path", "", nil},
+	}
+	for _, tt := range tests {
+		got := completeValues(tt.path, tt.prefix)
+		if len(got) == 0 && len(tt.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("completeValues(%s, %q) = %v, want %v", tt.path, tt.prefix, got, tt.want)
+		}
+	}
+
+	schemes := completeValues("appearance.colorScheme", "catppuccin-")
+	if len(schemes) == 0 {
+		t.Fatal("no built-in schemes suggested")
+	}
+	for _, name := range schemes {
+		if !strings.HasPrefix(name, "catppuccin-") {
+			t.Errorf("suggested %s for prefix catppuccin-", name)
+		}
+	}
+}
+
+func TestCompleteSetArgs(t *testing.T) {
+	paths, _ := completeSetArgs(nil, nil, "bar.posi")
+	if !reflect.DeepEqual(paths, []string{"bar.position"}) {
+		t.Errorf("first argument = %v", paths)
+	}
+	values, _ := completeSetArgs(nil, []string{"wallpaper.fillMode"}, "co")
+	if !reflect.DeepEqual(values, []string{"contain", "cover"}) {
+		t.Errorf("second argument = %v", values)
+	}
+	if extra, _ := completeSetArgs(nil, []string{"bar.position", "top"}, ""); len(extra) != 0 {
+		t.Errorf("third argument = %v, want none", extra)
+	}
+}
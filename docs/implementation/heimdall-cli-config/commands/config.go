@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -24,7 +26,8 @@ var initCmd = &cobra.Command{
 	Use:   "init [profile]",
 	Short: "Initialize a new configuration",
 	Long: `Initialize a new shell configuration with default values.
-You can optionally specify a profile (default, minimal, gaming, productivity, development).`,
+You can optionally specify a built-in profile (default, minimal, gaming,
+productivity, development) or a user profile from ~/.config/heimdall/profiles/.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		profile := "default"
@@ -48,7 +51,10 @@ You can optionally specify a profile (default, minimal, gaming, productivity, de
 		}
 
 		// Get profile configuration
-		cfg := config.GetProfileConfig(profile)
+		cfg, err := config.LoadProfile(profile)
+		if err != nil {
+			return err
+		}
 
 		// Save configuration
 		if err := manager.Save(cfg); err != nil {
@@ -64,7 +70,12 @@ You can optionally specify a profile (default, minimal, gaming, productivity, de
 var validateCmd = &cobra.Command{
 	Use:   "validate",
 	Short: "Validate the current configuration",
-	Long:  `Validate the shell configuration for errors and warnings.`,
+	Long: `Validate the shell configuration for errors and warnings.
+
+Colors are checked for WCAG contrast at the level appearance.contrast asks
+for (aa by default, aaa or off). --fix applies the fixes that can be made
+automatically, such as nudging a color's lightness until it is readable,
+and saves the result; locked paths are left alone.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logger := NewLogger()
 		manager, err := config.NewConfigManager(logger)
@@ -78,6 +89,28 @@ var validateCmd = &cobra.Command{
 			return fmt.Errorf("failed to load configuration: %w", err)
 		}
 
+		// Apply automatic fixes first so only the remaining issues are shown
+		if fix, _ := cmd.Flags().GetBool("fix"); fix {
+			changes, locked, err := config.ApplyFixes(cfg, manager.Validate)
+			if err != nil {
+				return fmt.Errorf("failed to apply fixes: %w", err)
+			}
+			if len(changes) > 0 {
+				if err := manager.Save(cfg); err != nil {
+					return fmt.Errorf("failed to save configuration: %w", err)
+				}
+				for _, change := range changes {
+					fmt.Printf("✓ Fixed %s\n", change)
+				}
+			}
+			for _, path := range locked {
+				fmt.Printf("🔒 %s is locked and was not fixed\n", path)
+			}
+			if len(changes) > 0 || len(locked) > 0 {
+				fmt.Println()
+			}
+		}
+
 		// Validate configuration
 		errors := manager.Validate(cfg)
 
@@ -209,7 +242,11 @@ var getCmd = &cobra.Command{
 	Use:   "get <path>",
 	Short: "Get a configuration value",
 	Long: `Get a specific configuration value by its path.
-Example: heimdall-cli config get system.shell`,
+Example: heimdall-cli config get system.shell
+
+With --explain, every layer (shell.json, shell.d/*.json, hosts/<hostname>.json,
+HEIMDALL__* variables and --set flags) that sets the path is listed, and the
+effective one is marked.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logger := NewLogger()
@@ -236,6 +273,10 @@ Example: heimdall-cli config get system.shell`,
 			return fmt.Errorf("path not found: %s", args[0])
 		}
 
+		if explain, _ := cmd.Flags().GetBool("explain"); explain {
+			return explainValue(manager, args[0], value)
+		}
+
 		// Output format
 		outputJSON, _ := cmd.Flags().GetBool("json")
 		if outputJSON {
@@ -257,7 +298,10 @@ var setCmd = &cobra.Command{
 	Use:   "set <path> <value>",
 	Short: "Set a configuration value",
 	Long: `Set a specific configuration value by its path.
-Example: heimdall-cli config set system.shell zsh`,
+Example: heimdall-cli config set system.shell zsh
+
+Use --layer host to write hosts/<hostname>.json, or --layer fragment
+--fragment <name> to write shell.d/<name>.json, instead of shell.json.`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logger := NewLogger()
@@ -284,6 +328,41 @@ Example: heimdall-cli config set system.shell zsh`,
 			// If JSON parsing fails, treat as string
 			value = args[1]
 		}
+		if field, ok := config.LookupField(args[0]); ok && field.Kind == reflect.String {
+			// Keep string fields as typed, so 90 or true stay strings
+			value = args[1]
+		}
+
+		// Overlay layers hold only the values written to them
+		layer, _ := cmd.Flags().GetString("layer")
+		fragment, _ := cmd.Flags().GetString("fragment")
+		if layer != config.LayerBase {
+			values := map[string]interface{}{args[0]: value}
+
+			// A scheme name brings its colors into the same layer
+			if name, ok := value.(string); ok && args[0] == "appearance.colorScheme" {
+				scheme, err := config.NewSchemeRegistry(config.GetSchemesDir()).Get(name)
+				if err != nil {
+					return fmt.Errorf("%w (see heimdall-cli config scheme list)", err)
+				}
+				values = config.SchemeValues(scheme)
+			}
+
+			paths := make([]string, 0, len(values))
+			for path := range values {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+
+			file := ""
+			for _, path := range paths {
+				if file, err = manager.SetLayerValue(layer, fragment, path, values[path]); err != nil {
+					return fmt.Errorf("failed to set value: %w", err)
+				}
+			}
+			fmt.Printf("✓ Set %s = %v in %s\n", args[0], value, file)
+			return nil
+		}
 
 		// Set value by path
 		if err := setValueByPath(configMap, args[0], value); err != nil {
@@ -295,6 +374,13 @@ Example: heimdall-cli config set system.shell zsh`,
 			return fmt.Errorf("failed to update configuration: %w", err)
 		}
 
+		// A scheme name fills the palette it stands for
+		if args[0] == "appearance.colorScheme" {
+			if err := applySchemeSetting(cfg); err != nil {
+				return err
+			}
+		}
+
 		// Save configuration
 		if err := manager.Save(cfg); err != nil {
 			return fmt.Errorf("failed to save configuration: %w", err)
@@ -302,6 +388,13 @@ Example: heimdall-cli config set system.shell zsh`,
 
 		fmt.Printf("✓ Set %s = %v\n", args[0], value)
 
+		// Warn when a higher layer still wins
+		if values, err := manager.Explain(args[0]); err == nil && len(values) > 0 {
+			if top := values[len(values)-1]; top.Layer.Kind != config.LayerBase {
+				fmt.Printf("⚠ %s is overridden by %s (%s = %s)\n", args[0], top.Layer.Name, args[0], config.FormatValue(top.Value))
+			}
+		}
+
 		return nil
 	},
 }
@@ -380,9 +473,19 @@ var unlockCmd = &cobra.Command{
 var exportCmd = &cobra.Command{
 	Use:   "export [file]",
 	Short: "Export configuration to a file",
-	Long:  `Export the current configuration to a file or stdout.`,
-	Args:  cobra.MaximumNArgs(1),
+	Long: `Export the current configuration to a file or stdout.
+The export merges shell.json, shell.d fragments and the host overlay. Use
+--resolved to also include environment and --set overrides.
+
+The format follows the file extension (.json, .yaml/.yml, .toml) unless
+--format is given; stdout defaults to JSON. Keys match shell.json in every
+format. Colors are written as hex, with references such as @primary/80%
+resolved; --raw-colors keeps them as written in shell.json.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		resolved, _ := cmd.Flags().GetBool("resolved")
+		rawColors, _ := cmd.Flags().GetBool("raw-colors")
+
 		logger := NewLogger()
 		manager, err := config.NewConfigManager(logger)
 		if err != nil {
@@ -390,13 +493,27 @@ var exportCmd = &cobra.Command{
 		}
 
 		// Load configuration
-		cfg, err := manager.Load()
+		var cfg *config.ShellConfig
+		if resolved {
+			cfg, err = manager.Load()
+		} else {
+			cfg, err = manager.LoadFiles()
+		}
 		if err != nil {
 			return fmt.Errorf("failed to load configuration: %w", err)
 		}
+		if !rawColors {
+			if err := config.ResolveColors(cfg); err != nil {
+				return fmt.Errorf("failed to resolve colors: %w", err)
+			}
+		}
 
 		// Marshal configuration
-		data, err := json.MarshalIndent(cfg, "", "  ")
+		format, err := fileFormat(cmd, args)
+		if err != nil {
+			return err
+		}
+		data, err := config.MarshalConfig(cfg, format)
 		if err != nil {
 			return fmt.Errorf("failed to marshal configuration: %w", err)
 		}
@@ -409,7 +526,7 @@ var exportCmd = &cobra.Command{
 			}
 			fmt.Printf("✓ Configuration exported to %s\n", outputFile)
 		} else {
-			fmt.Println(string(data))
+			fmt.Println(strings.TrimRight(string(data), "\n"))
 		}
 
 		return nil
@@ -418,10 +535,27 @@ var exportCmd = &cobra.Command{
 
 // importCmd imports a configuration
 var importCmd = &cobra.Command{
-	Use:   "import <file>",
+	Use:   "import <file|url|->",
 	Short: "Import configuration from a file",
-	Long:  `Import configuration from a JSON file.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Import configuration from a JSON, YAML or TOML file.
+The source may be a path, "-" for stdin, or a file://, http:// or https://
+URL. The format follows the extension (.json, .yaml/.yml, .toml) unless
+--format is given. Sources larger than --max-size are rejected.
+
+Use --sha256 to pin the content's digest, or --signature with --public-key
+to check a detached Ed25519 signature (raw or base64; the key as PEM or
+base64). Imports never run commands.custom entries or render reload hooks,
+and ones that are new or changed need confirmation (or --allow-commands).
+
+By default the file replaces the whole configuration. With --merge it may be
+partial (just a color scheme or bar layout) and is merged into the current
+configuration; --paths limits the merge to some sections:
+  heimdall-cli config import colors.yaml --merge
+  heimdall-cli config import shared.json --merge --paths appearance.colors,bar
+
+Locked paths are never changed by a merge. Files from an older schema
+version are migrated to the current one first.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logger := NewLogger()
 		manager, err := config.NewConfigManager(logger)
@@ -429,15 +563,44 @@ var importCmd = &cobra.Command{
 			return fmt.Errorf("failed to create config manager: %w", err)
 		}
 
-		// Read input file
-		data, err := os.ReadFile(args[0])
+		// Read and verify the input
+		fetcher := config.NewSourceFetcher()
+		data, err := readImportSource(cmd, fetcher, args[0])
 		if err != nil {
-			return fmt.Errorf("failed to read file: %w", err)
+			return err
 		}
 
 		// Parse configuration
-		cfg := &config.ShellConfig{}
-		if err := json.Unmarshal(data, cfg); err != nil {
+		format, err := fileFormat(cmd, args)
+		if err != nil {
+			return err
+		}
+		incoming, err := config.DecodeMap(data, format)
+		if err != nil {
+			return fmt.Errorf("failed to parse configuration: %w", err)
+		}
+
+		// Bring older files up to the current schema; the live config is
+		// backed up when it is saved
+		if version, _ := incoming["version"].(string); version != "" && version != config.CurrentSchemaVersion {
+			migrator := config.NewVersionMigrator(config.GetBackupDir(), logger)
+			if incoming, err = migrator.MigratePartial(incoming); err != nil {
+				return fmt.Errorf("failed to migrate import file from version %s: %w", version, err)
+			}
+			fmt.Printf("Migrated %s from version %s to %s\n", args[0], version, config.CurrentSchemaVersion)
+		}
+
+		// Custom commands and reload hooks can run anything, so new ones need consent
+		if err := confirmCommands(cmd, currentConfigMap(manager), incoming, args[0], args[0] != "-"); err != nil {
+			return err
+		}
+
+		if merge, _ := cmd.Flags().GetBool("merge"); merge {
+			return mergeImport(cmd, manager, incoming, args[0])
+		}
+
+		cfg, err := config.MapToConfig(incoming)
+		if err != nil {
 			return fmt.Errorf("failed to parse configuration: %w", err)
 		}
 
@@ -530,52 +693,29 @@ func setValueByPath(data map[string]interface{}, path string, value interface{})
 	return nil
 }
 
-// NewLogger creates a simple logger implementation
-func NewLogger() config.Logger {
-	return &SimpleLogger{}
-}
-
-// SimpleLogger is a simple logger implementation
-type SimpleLogger struct{}
-
-func (l *SimpleLogger) Debug(msg string, fields ...config.Field) {
-	if os.Getenv("DEBUG") != "" {
-		fmt.Printf("[DEBUG] %s", msg)
-		for _, f := range fields {
-			fmt.Printf(" %s=%v", f.Key, f.Value)
-		}
-		fmt.Println()
-	}
-}
-
-func (l *SimpleLogger) Info(msg string, fields ...config.Field) {
-	fmt.Printf("[INFO] %s", msg)
-	for _, f := range fields {
-		fmt.Printf(" %s=%v", f.Key, f.Value)
-	}
-	fmt.Println()
-}
-
-func (l *SimpleLogger) Warn(msg string, fields ...config.Field) {
-	fmt.Printf("[WARN] %s", msg)
-	for _, f := range fields {
-		fmt.Printf(" %s=%v", f.Key, f.Value)
-	}
-	fmt.Println()
-}
-
-func (l *SimpleLogger) Error(msg string, fields ...config.Field) {
-	fmt.Printf("[ERROR] %s", msg)
-	for _, f := range fields {
-		fmt.Printf(" %s=%v", f.Key, f.Value)
-	}
-	fmt.Println()
-}
-
 func init() {
 	// Add flags
 	initCmd.Flags().BoolP("force", "f", false, "Force overwrite existing configuration")
+	validateCmd.Flags().Bool("fix", false, "Apply automatic fixes and save the configuration")
 	getCmd.Flags().BoolP("json", "j", false, "Output in JSON format")
+	getCmd.Flags().Bool("explain", false, "Show which config layer sets the value")
+	exportCmd.Flags().String("format", "", "Output format: json, yaml or toml (default: from file extension)")
+	importCmd.Flags().String("sha256", "", "Expected SHA-256 digest of the source (hex)")
+	importCmd.Flags().String("signature", "", "Detached Ed25519 signature file or URL")
+	importCmd.Flags().String("public-key", "", "Ed25519 public key file for --signature")
+	importCmd.Flags().Int64("max-size", config.DefaultImportLimit, "Maximum source size in bytes")
+	importCmd.Flags().Bool("allow-commands", false, "Accept new or changed custom commands and reload hooks without asking")
+	importCmd.Flags().Bool("merge", false, "Merge the file into the current configuration")
+	importCmd.Flags().StringSlice("paths", nil, "With --merge, only import these paths or sections")
+	importCmd.Flags().String("strategy", "deep", "With --merge: deep (incoming values win) or shallow (keep existing values, only add absent keys)")
+	importCmd.Flags().Bool("migrate", false, "Migrate an older import file to the current schema first")
+	importCmd.Flags().MarkDeprecated("migrate", "older files are migrated automatically")
+	importCmd.Flags().Bool("dry-run", false, "With --merge, only show the changes")
+	importCmd.Flags().String("format", "", "Input format: json, yaml or toml (default: from file extension)")
+	exportCmd.Flags().Bool("resolved", false, "Include environment and --set overrides")
+	exportCmd.Flags().Bool("raw-colors", false, "Keep color syntax and references as written instead of hex")
+	setCmd.Flags().String("layer", config.LayerBase, "Layer to write: base, fragment or host")
+	setCmd.Flags().String("fragment", "", "Fragment file in shell.d for --layer fragment")
 
 	// Add subcommands
 	ConfigCmd.AddCommand(initCmd)
@@ -589,3 +729,38 @@ func init() {
 	ConfigCmd.AddCommand(exportCmd)
 	ConfigCmd.AddCommand(importCmd)
 }
+
+// fileFormat returns the --format flag, or the format of the file argument
+func fileFormat(cmd *cobra.Command, args []string) (string, error) {
+	if name, _ := cmd.Flags().GetString("format"); name != "" {
+		return config.ParseFormat(name)
+	}
+	if len(args) > 0 {
+		return config.SourceFormat(args[0]), nil
+	}
+	return config.FormatJSON, nil
+}
+
+// explainValue prints the layers that set a path, effective layer last
+func explainValue(manager *config.ConfigManager, path string, value interface{}) error {
+	values, err := manager.Explain(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config layers: %w", err)
+	}
+
+	fmt.Printf("%s = %s\n", path, config.FormatValue(value))
+	if len(values) == 0 {
+		fmt.Println("  not set in any layer (schema default)")
+		return nil
+	}
+
+	for i := len(values) - 1; i >= 0; i-- {
+		state := "overridden"
+		if i == len(values)-1 {
+			state = "effective"
+		}
+		lv := values[i]
+		fmt.Printf("  %-10s %-9s %s = %s\n", state, lv.Layer.Kind, lv.Layer.Name, config.FormatValue(lv.Value))
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"heimdall-cli/config"
+)
+
+// doctorCmd checks that the programs and files named by the config exist
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that configured tools, themes and files exist",
+	Long: `Check the environment against the configuration.
+Each system tool is resolved against $PATH (polkitAgent by absolute path),
+icon and cursor themes are looked up in the XDG icon directories, the font
+family is searched in the fontconfig font directories and wallpaper paths
+are checked on disk. Problems are reported as warnings with suggested fixes.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger := NewLogger()
+		manager, err := config.NewConfigManager(logger)
+		if err != nil {
+			return fmt.Errorf("failed to create config manager: %w", err)
+		}
+
+		// Load configuration
+		cfg, err := manager.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+
+		// Run environment checks
+		doctor := config.NewDoctor(config.OSProbe{})
+		warnings := doctor.Check(cfg)
+
+		if len(warnings) == 0 {
+			fmt.Println("✓ All configured tools, themes and files were found")
+			return nil
+		}
+
+		fmt.Printf("Found %d environment issues:\n\n", len(warnings))
+		for _, w := range warnings {
+			fmt.Printf("⚠ %s: %s\n", w.Path, w.Message)
+			if w.Fix != nil && w.Fix.Description != "" {
+				fmt.Printf("  → %s\n", w.Fix.Description)
+				if w.Fix.Command != "" {
+					fmt.Printf("  → Run: %s\n", w.Fix.Command)
+				}
+			}
+		}
+
+		strict, _ := cmd.Flags().GetBool("strict")
+		if strict {
+			return fmt.Errorf("doctor found %d issues", len(warnings))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	doctorCmd.Flags().Bool("strict", false, "Exit with an error when any issue is found")
+
+	ConfigCmd.AddCommand(doctorCmd)
+}
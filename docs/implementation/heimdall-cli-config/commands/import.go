@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"heimdall-cli/config"
+)
+
+// mergeImport merges a decoded import file into the live configuration
+func mergeImport(cmd *cobra.Command, manager *config.ConfigManager, incoming map[string]interface{}, source string) error {
+	paths, _ := cmd.Flags().GetStringSlice("paths")
+	strategyName, _ := cmd.Flags().GetString("strategy")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	var strategy config.InjectionStrategy
+	switch strategyName {
+	case "deep":
+		strategy = config.MergeDeep
+	case "shallow":
+		strategy = config.MergeShallow
+	default:
+		return fmt.Errorf("unknown merge strategy: %s (use deep or shallow)", strategyName)
+	}
+
+	// Load configuration
+	cfg, err := manager.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	injector := config.NewPropertyInjector()
+	plan, err := injector.PlanMerge(cfg, incoming, paths, strategy)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range plan.Locked {
+		fmt.Printf("🔒 %s is locked, skipping\n", path)
+	}
+
+	if len(plan.Changes) == 0 {
+		fmt.Printf("✓ Nothing to import: %s matches the current configuration\n", source)
+		return nil
+	}
+
+	fmt.Printf("Merging %d values from %s:\n", len(plan.Changes), source)
+	for _, change := range plan.Changes {
+		fmt.Printf("  %s\n", change)
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	if err := manager.Save(plan.Config); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	fmt.Printf("✓ Merged %d values from %s\n", len(plan.Changes), source)
+	return nil
+}
+
+// readImportSource fetches an import source and checks its digest or signature
+func readImportSource(cmd *cobra.Command, fetcher config.Fetcher, source string) ([]byte, error) {
+	digest, _ := cmd.Flags().GetString("sha256")
+	signature, _ := cmd.Flags().GetString("signature")
+	publicKey, _ := cmd.Flags().GetString("public-key")
+	limit, _ := cmd.Flags().GetInt64("max-size")
+
+	if signature != "" && publicKey == "" {
+		return nil, fmt.Errorf("--signature requires --public-key")
+	}
+
+	data, err := fetcher.Fetch(source, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if digest != "" {
+		if err := config.VerifySHA256(data, digest); err != nil {
+			return nil, err
+		}
+		fmt.Println("✓ SHA-256 digest verified")
+	}
+
+	if signature != "" {
+		sig, err := fetcher.Fetch(signature, 4096)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signature: %w", err)
+		}
+		key, err := os.ReadFile(publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read public key: %w", err)
+		}
+		if err := config.VerifySignature(data, sig, key); err != nil {
+			return nil, err
+		}
+		fmt.Println("✓ Signature verified")
+	}
+
+	return data, nil
+}
+
+// confirmCommands asks before accepting custom commands and render reload
+// hooks that are new or differ from the current ones, since both run
+// arbitrary shell commands. Declined commands keep their current value in
+// incoming. Without a terminal to ask on (canAsk false), only --allow-commands
+// accepts them.
+func confirmCommands(cmd *cobra.Command, current, incoming map[string]interface{}, source string, canAsk bool) error {
+	paths := config.ChangedCommands(current, incoming)
+	if len(paths) == 0 {
+		return nil
+	}
+
+	commands := config.RunnableCommands(incoming)
+	fmt.Printf("%s defines %d new or changed commands:\n", source, len(paths))
+	for _, path := range paths {
+		fmt.Printf("  %s: %s\n", path, config.DescribeCommand(commands[path]))
+	}
+
+	allow, _ := cmd.Flags().GetBool("allow-commands")
+	if !allow {
+		if canAsk {
+			allow = confirm("Accept these commands?")
+		} else {
+			fmt.Println("Cannot ask for confirmation here; use --allow-commands")
+		}
+	}
+	if allow {
+		return nil
+	}
+
+	config.RestoreCommands(current, incoming, paths)
+	fmt.Println("Keeping the current commands")
+	return nil
+}
+
+// currentConfigMap loads the live configuration as a map, or an empty map
+// when there is none yet
+func currentConfigMap(manager *config.ConfigManager) map[string]interface{} {
+	if cfg, err := manager.Load(); err == nil {
+		if cfgMap, err := config.ConfigToMap(cfg); err == nil {
+			return cfgMap
+		}
+	}
+	return make(map[string]interface{})
+}
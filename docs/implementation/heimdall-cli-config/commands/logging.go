@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"heimdall-cli/config"
+	"heimdall-cli/logging"
+)
+
+// activeLogger is the process-wide logger, replaced by ConfigureLogging
+var activeLogger config.Logger = newDefaultLogger()
+
+// NewLogger returns the logger configured by the root command flags
+func NewLogger() config.Logger {
+	return activeLogger
+}
+
+// SetLogger replaces the process-wide logger, e.g. with a logging.Recorder in tests
+func SetLogger(logger config.Logger) {
+	activeLogger = logger
+}
+
+// ConfigureLogging builds the process-wide logger from flag values.
+// Logs always go to stderr so command output on stdout stays clean;
+// a non-empty file also writes them to a rotating log file.
+func ConfigureLogging(level, format, file string) error {
+	lvl, err := logging.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	fmtOpt, err := logging.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+
+	logger, err := logging.New(logging.Options{
+		Level:  lvl,
+		Format: fmtOpt,
+		File:   file,
+	})
+	if err != nil {
+		return err
+	}
+
+	activeLogger = logger
+	return nil
+}
+
+// newDefaultLogger logs info and above as text on stderr
+func newDefaultLogger() config.Logger {
+	logger, _ := logging.New(logging.Options{Level: logging.LevelInfo})
+	return logger
+}
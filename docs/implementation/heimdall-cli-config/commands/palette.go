@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"heimdall-cli/config"
+)
+
+// SchemeCmd groups commands that generate color schemes
+var SchemeCmd = &cobra.Command{
+	Use:   "scheme",
+	Short: "Generate color schemes",
+	Long: `Generate color schemes from other sources. Generated schemes are saved to
+the scheme registry; list, show, apply and import them with
+heimdall-cli config scheme.`,
+}
+
+// schemeFromWallpaperCmd builds a scheme from a wallpaper's dominant colors
+var schemeFromWallpaperCmd = &cobra.Command{
+	Use:   "from-wallpaper [path]",
+	Short: "Generate a color scheme from the wallpaper",
+	Long: `Extract the dominant colors of a PNG or JPEG image with median cut and
+assign them to the color roles, then save the result as a scheme and apply
+it to appearance.colors.
+
+Without a path the image comes from wallpaper.path, or the first image of
+wallpaper.directory in slideshow mode. Unless --theme is given, the variant
+follows the theme schedule, or appearance.theme (dark unless it is light).
+The same image always gives the same palette.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		size, _ := cmd.Flags().GetInt("colors")
+		name, _ := cmd.Flags().GetString("name")
+		theme, _ := cmd.Flags().GetString("theme")
+
+		logger := NewLogger()
+		manager, err := config.NewConfigManager(logger)
+		if err != nil {
+			return fmt.Errorf("failed to create config manager: %w", err)
+		}
+
+		// Load configuration, unless --json has everything it needs
+		var cfg *config.ShellConfig
+		if !asJSON || len(args) == 0 || theme == "" {
+			if cfg, err = manager.Load(); err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+		}
+
+		path := ""
+		if len(args) > 0 {
+			path = config.ExpandHome(args[0])
+		} else if path, err = config.CurrentWallpaper(cfg); err != nil {
+			return err
+		}
+		if theme == "" {
+			theme = config.PaletteVariant(cfg, time.Now())
+		}
+
+		img, err := config.LoadPaletteImage(path)
+		if err != nil {
+			return err
+		}
+		swatches := config.ExtractSwatches(img, size)
+		scheme, err := config.SchemeFromSwatches(swatches, theme)
+		if err != nil {
+			return fmt.Errorf("failed to build palette: %w", err)
+		}
+		scheme.Name = name
+		scheme.Description = "Generated from " + filepath.Base(path)
+
+		if asJSON {
+			data, err := json.MarshalIndent(map[string]interface{}{
+				"swatches": swatches,
+				"scheme":   scheme,
+			}, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal palette: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Dominant colors of %s:\n", path)
+		for _, s := range swatches {
+			fmt.Printf("  %s %5.1f%%\n", s.Color, s.Population*100)
+		}
+		fmt.Println()
+
+		changes, locked, err := config.ApplyScheme(cfg, scheme)
+		if err != nil {
+			return fmt.Errorf("failed to apply scheme: %w", err)
+		}
+		printSchemeChanges(changes, locked)
+
+		if dryRun {
+			return nil
+		}
+
+		registry := config.NewSchemeRegistry(config.GetSchemesDir())
+		schemePath, err := registry.Save(scheme)
+		if err != nil {
+			return fmt.Errorf("failed to save scheme: %w", err)
+		}
+		if len(changes) > 0 {
+			if err := manager.Save(cfg); err != nil {
+				return fmt.Errorf("failed to save configuration: %w", err)
+			}
+		}
+		fmt.Printf("✓ Saved %s scheme '%s' to %s and applied it\n", scheme.Variant, scheme.Name, schemePath)
+		return nil
+	},
+}
+
+func init() {
+	schemeFromWallpaperCmd.Flags().Int("colors", config.DefaultPaletteSize, "Number of dominant colors to extract")
+	schemeFromWallpaperCmd.Flags().String("name", config.WallpaperSchemeName, "Name to save the scheme under")
+	schemeFromWallpaperCmd.Flags().String("theme", "", "Palette variant: dark or light (default: appearance.theme)")
+	schemeFromWallpaperCmd.Flags().Bool("dry-run", false, "Only show the palette and the changes")
+	schemeFromWallpaperCmd.Flags().Bool("json", false, "Print the swatches and scheme as JSON without saving")
+	schemeFromWallpaperCmd.RegisterFlagCompletionFunc("theme", cobra.FixedCompletions(config.SchemeVariants, cobra.ShellCompDirectiveNoFileComp))
+
+	SchemeCmd.AddCommand(schemeFromWallpaperCmd)
+}
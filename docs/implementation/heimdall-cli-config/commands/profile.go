@@ -0,0 +1,445 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"heimdall-cli/config"
+)
+
+// profileCmd groups profile management commands
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage configuration profiles",
+	Long: `Manage built-in and user-defined configuration profiles.
+User profiles are partial overlays stored in ~/.config/heimdall/profiles/<name>.json.
+Each one extends another profile, and the chain must end at a built-in profile
+(default, minimal, gaming, productivity, development).`,
+}
+
+// profileListCmd lists the available profiles
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available profiles",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := config.NewProfileStore(config.GetProfilesDir())
+		profiles, err := store.List()
+		if err != nil {
+			return err
+		}
+
+		// Mark the profile the current config uses
+		current := ""
+		if cfg := loadConfigQuietly(); cfg != nil {
+			current = cfg.Metadata.Profile
+		}
+
+		for _, p := range profiles {
+			marker := " "
+			if p.Name == current {
+				marker = "*"
+			}
+			if p.Builtin {
+				fmt.Printf("%s %-16s built-in\n", marker, p.Name)
+				continue
+			}
+			line := fmt.Sprintf("%s %-16s extends %s", marker, p.Name, p.Extends)
+			if p.Description != "" {
+				line += " — " + p.Description
+			}
+			fmt.Println(line)
+		}
+		return nil
+	},
+}
+
+// profileShowCmd prints a profile
+var profileShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show a profile's resolved configuration",
+	Long: `Show the full configuration a profile resolves to.
+Use --overlay to print only the values a user profile sets.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := config.NewProfileStore(config.GetProfilesDir())
+		overlayOnly, _ := cmd.Flags().GetBool("overlay")
+
+		var value interface{}
+		if overlayOnly {
+			if config.IsBuiltinProfile(args[0]) {
+				return fmt.Errorf("%s is a built-in profile and has no overlay", args[0])
+			}
+			profile, err := store.Load(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to load profile %q: %w", args[0], err)
+			}
+			value = profile.Overlay
+		} else {
+			resolved, err := store.Resolve(args[0])
+			if err != nil {
+				return err
+			}
+			value = resolved
+		}
+
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format output: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	},
+}
+
+// profileCreateCmd creates a user profile
+var profileCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a user profile",
+	Long: `Create a user profile extending another profile.
+With --from-current, the profile records every value of the current
+configuration that differs from the base profile.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		extends, _ := cmd.Flags().GetString("extends")
+		description, _ := cmd.Flags().GetString("description")
+		fromCurrent, _ := cmd.Flags().GetBool("from-current")
+		force, _ := cmd.Flags().GetBool("force")
+
+		store := config.NewProfileStore(config.GetProfilesDir())
+		if store.Exists(args[0]) && !force {
+			return fmt.Errorf("profile %q already exists. Use --force to overwrite", args[0])
+		}
+
+		profile := &config.UserProfile{
+			Name:        args[0],
+			Extends:     extends,
+			Description: description,
+			Overlay:     make(map[string]interface{}),
+		}
+
+		if fromCurrent {
+			logger := NewLogger()
+			manager, err := config.NewConfigManager(logger)
+			if err != nil {
+				return fmt.Errorf("failed to create config manager: %w", err)
+			}
+
+			// Load configuration
+			cfg, err := manager.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+
+			overlay, err := store.OverlayFromConfig(cfg, extends)
+			if err != nil {
+				return err
+			}
+			profile.Overlay = overlay
+		}
+
+		if err := store.Save(profile); err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ Created profile '%s' extending '%s'\n", profile.Name, profile.Extends)
+		return nil
+	},
+}
+
+// profileDeleteCmd deletes a user profile
+var profileDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a user profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, _ := cmd.Flags().GetBool("force")
+
+		store := config.NewProfileStore(config.GetProfilesDir())
+		if err := store.Delete(args[0], force); err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ Deleted profile '%s'\n", args[0])
+		return nil
+	},
+}
+
+// profileUseCmd switches the config to another profile
+var profileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Switch to another profile, keeping personal overrides",
+	Long: `Switch the configuration to another profile.
+Values you changed relative to the current profile are re-applied on top of
+the new profile, and locked paths keep their value. Everything else takes the
+new profile's defaults. The previous configuration is backed up first.
+Custom commands and render reload hooks the profile adds or changes need
+confirmation (or --allow-commands).`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		logger := NewLogger()
+		manager, err := config.NewConfigManager(logger)
+		if err != nil {
+			return fmt.Errorf("failed to create config manager: %w", err)
+		}
+
+		// Load configuration
+		cfg, err := manager.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+
+		store := config.NewProfileStore(config.GetProfilesDir())
+		plan, err := store.PlanSwitch(cfg, args[0])
+		if err != nil {
+			return err
+		}
+
+		printProfileSwitch(plan)
+		if dryRun {
+			return nil
+		}
+		if err := confirmProfileCommands(cmd, cfg, plan, true); err != nil {
+			return err
+		}
+
+		if err := manager.Save(plan.Config); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+
+		if backups, err := manager.ListBackups(); err == nil && len(backups) > 0 {
+			fmt.Printf("✓ Backed up previous configuration as %s\n", backups[0].ID)
+		}
+		fmt.Printf("✓ Switched to profile '%s'\n", plan.To)
+		return nil
+	},
+}
+
+// profileAutoCmd selects a profile from profiles.auto rules
+var profileAutoCmd = &cobra.Command{
+	Use:   "auto",
+	Short: "Switch profiles automatically from context rules",
+	Long: `Evaluate the profiles.auto rules and switch to the selected profile.
+Rules are checked in order and the first one whose conditions all hold wins:
+
+  "profiles": {
+    "auto": {
+      "interval": 60,
+      "fallback": "default",
+      "rules": [
+        {"name": "remote", "profile": "minimal", "when": {"env": {"SSH_CONNECTION": ""}}},
+        {"name": "docked", "profile": "gaming", "when": {"power": "ac", "externalMonitor": true}},
+        {"name": "mobile", "profile": "productivity", "when": {"power": "battery"}},
+        {"name": "night", "profile": "minimal", "when": {"time": "22:00-07:00"}}
+      ]
+    }
+  }
+
+Conditions: hostname (globs), power (ac|battery), monitors (output name
+globs that must all be connected), externalMonitor, time (HH:MM-HH:MM) and
+env (a value of "" means set, "!" means unset, anything else is a glob).
+
+Switching keeps personal overrides like 'config profile use'. With --watch
+the rules are re-evaluated every interval and a switch only happens when the
+selected profile changes, so a manual 'profile use' is not undone until the
+context changes. Custom commands and render reload hooks a profile adds or
+changes are only taken with --allow-commands.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		watch, _ := cmd.Flags().GetBool("watch")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		interval, _ := cmd.Flags().GetDuration("interval")
+
+		logger := NewLogger()
+		manager, err := config.NewConfigManager(logger)
+		if err != nil {
+			return fmt.Errorf("failed to create config manager: %w", err)
+		}
+
+		detector := config.NewContextDetector(config.OSProbe{}, "/")
+		store := config.NewProfileStore(config.GetProfilesDir())
+
+		if !watch {
+			_, err := applyAutoProfile(cmd, manager, detector, store, "", dryRun)
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		last := ""
+		for {
+			selected, err := applyAutoProfile(cmd, manager, detector, store, last, dryRun)
+			if err != nil {
+				logger.Error("Automatic profile selection failed", config.Field{Key: "error", Value: err})
+			} else {
+				last = selected
+			}
+
+			wait := interval
+			if wait <= 0 {
+				wait = time.Minute
+				if cfg, err := manager.Load(); err == nil && cfg.Profiles.Auto.Interval > 0 {
+					wait = time.Duration(cfg.Profiles.Auto.Interval) * time.Second
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(wait):
+			}
+		}
+	},
+}
+
+// applyAutoProfile evaluates the rules once and switches when the selected
+// profile differs from both the active profile and previous (the last
+// selection in watch mode). It returns the selected profile.
+func applyAutoProfile(cmd *cobra.Command, manager *config.ConfigManager, detector *config.ContextDetector, store *config.ProfileStore, previous string, dryRun bool) (string, error) {
+	// Load configuration
+	cfg, err := manager.Load()
+	if err != nil {
+		return "", fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	auto := &cfg.Profiles.Auto
+	ctx := detector.Detect(auto.Rules)
+	profile, rule, ok := config.SelectProfile(auto, ctx)
+	if dryRun {
+		fmt.Printf("Context: %s\n", ctx)
+	}
+	if !ok {
+		if previous == "" {
+			fmt.Println("No profile rule matched and no fallback is set")
+		}
+		return "", nil
+	}
+	if profile == previous {
+		return profile, nil
+	}
+	if profile == cfg.Metadata.Profile {
+		fmt.Printf("✓ Profile '%s' already active (%s)\n", profile, rule)
+		return profile, nil
+	}
+
+	plan, err := store.PlanSwitch(cfg, profile)
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Printf("Rule '%s' selected profile '%s'\n", rule, profile)
+	printProfileSwitch(plan)
+	if dryRun {
+		return profile, nil
+	}
+	if err := confirmProfileCommands(cmd, cfg, plan, false); err != nil {
+		return "", err
+	}
+
+	if err := manager.Save(plan.Config); err != nil {
+		return "", fmt.Errorf("failed to save configuration: %w", err)
+	}
+	fmt.Printf("✓ Switched to profile '%s'\n", profile)
+	return profile, nil
+}
+
+// confirmProfileCommands asks before a switch brings in custom commands or
+// reload hooks that differ from the current ones; declined ones keep their
+// current value
+func confirmProfileCommands(cmd *cobra.Command, cfg *config.ShellConfig, plan *config.ProfileSwitch, canAsk bool) error {
+	current, err := config.ConfigToMap(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to convert config to map: %w", err)
+	}
+	incoming, err := config.ConfigToMap(plan.Config)
+	if err != nil {
+		return fmt.Errorf("failed to convert config to map: %w", err)
+	}
+	if len(config.ChangedCommands(current, incoming)) == 0 {
+		return nil
+	}
+
+	if err := confirmCommands(cmd, current, incoming, fmt.Sprintf("Profile '%s'", plan.To), canAsk); err != nil {
+		return err
+	}
+	updated, err := config.MapToConfig(incoming)
+	if err != nil {
+		return fmt.Errorf("failed to convert map to config: %w", err)
+	}
+	updated.Extra = plan.Config.Extra
+	plan.Config = updated
+	return nil
+}
+
+// printProfileSwitch summarizes what a profile switch changes and keeps
+func printProfileSwitch(plan *config.ProfileSwitch) {
+	if plan.Warning != "" {
+		fmt.Printf("⚠ %s\n", plan.Warning)
+	}
+	fmt.Printf("Switching profile '%s' → '%s'\n", plan.From, plan.To)
+
+	if len(plan.Changed) > 0 {
+		fmt.Printf("\nChanged by profile (%d):\n", len(plan.Changed))
+		for _, change := range plan.Changed {
+			fmt.Printf("  %s\n", change)
+		}
+	}
+	if len(plan.Kept) > 0 {
+		fmt.Printf("\nKept personal overrides (%d):\n", len(plan.Kept))
+		for _, change := range plan.Kept {
+			fmt.Printf("  %s = %s (profile: %s)\n", change.Path, config.FormatValue(change.New), config.FormatValue(change.Old))
+		}
+	}
+	if len(plan.Locked) > 0 {
+		fmt.Printf("\nKept locked values (%d):\n", len(plan.Locked))
+		for _, change := range plan.Locked {
+			fmt.Printf("  🔒 %s = %s (profile: %s)\n", change.Path, config.FormatValue(change.New), config.FormatValue(change.Old))
+		}
+	}
+	if len(plan.Changed)+len(plan.Kept)+len(plan.Locked) == 0 {
+		fmt.Println("No values differ between the profiles")
+	}
+	fmt.Println()
+}
+
+func init() {
+	profileShowCmd.Flags().Bool("overlay", false, "Show only the values the user profile sets")
+	profileCreateCmd.Flags().String("extends", "default", "Profile to extend")
+	profileCreateCmd.Flags().String("description", "", "Short description of the profile")
+	profileCreateCmd.Flags().Bool("from-current", false, "Record the current config's differences from the base profile")
+	profileCreateCmd.Flags().BoolP("force", "f", false, "Overwrite an existing profile")
+	profileDeleteCmd.Flags().BoolP("force", "f", false, "Delete even if other profiles extend it")
+	profileUseCmd.Flags().Bool("dry-run", false, "Only show the changes")
+	profileUseCmd.Flags().Bool("allow-commands", false, "Accept new or changed custom commands and reload hooks without asking")
+	profileAutoCmd.Flags().Bool("once", false, "Evaluate the rules once and exit (default)")
+	profileAutoCmd.Flags().Bool("watch", false, "Keep re-evaluating the rules")
+	profileAutoCmd.Flags().Duration("interval", 0, "Time between evaluations with --watch (default: profiles.auto.interval)")
+	profileAutoCmd.Flags().Bool("dry-run", false, "Show the detected context and selection without switching")
+	profileAutoCmd.Flags().Bool("allow-commands", false, "Accept new or changed custom commands and reload hooks from the selected profile")
+	profileAutoCmd.MarkFlagsMutuallyExclusive("once", "watch")
+
+	profileShowCmd.ValidArgsFunction = completeProfiles
+	profileUseCmd.ValidArgsFunction = completeProfiles
+	profileDeleteCmd.ValidArgsFunction = completeUserProfiles
+	profileCreateCmd.RegisterFlagCompletionFunc("extends", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeProfiles(cmd, nil, toComplete)
+	})
+
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileShowCmd)
+	profileCmd.AddCommand(profileCreateCmd)
+	profileCmd.AddCommand(profileDeleteCmd)
+	profileCmd.AddCommand(profileUseCmd)
+	profileCmd.AddCommand(profileAutoCmd)
+
+	ConfigCmd.AddCommand(profileCmd)
+}
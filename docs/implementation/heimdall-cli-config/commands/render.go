@@ -0,0 +1,359 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"heimdall-cli/config"
+)
+
+// RenderCmd renders theme templates and hosts the Quickshell and Hyprland generators
+var RenderCmd = &cobra.Command{
+	Use:   "render [target...]",
+	Short: "Generate configuration files for other programs",
+	Long: `Generate configuration files for the programs heimdall drives from
+the shell configuration. Generated files should not be edited by hand; change
+shell.json and render again.
+
+With target names, or with none to render every enabled entry of
+render.targets, theme templates are executed with the palette
+(appearance.colors and accentColor), system.font, transparency, border width
+and border radius. Built-in templates exist for kitty, foot, fuzzel, rofi,
+dunst, gtk and btop; text/template files in ~/.config/heimdall/templates
+(<name>.tmpl) add new templates or replace built-in ones.
+
+A target's reload command runs after its file changes:
+  "render": {"targets": {
+    "kitty": {"enabled": true},
+    "gtk4":  {"enabled": true, "template": "gtk", "output": "~/.config/gtk-4.0/heimdall.css"}
+  }}`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadRenderConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		renderer := config.NewTemplateRenderer(config.GetTemplatesDir())
+		targets, err := renderer.Targets(&cfg.Render, args)
+		if err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			fmt.Println("No render targets are enabled")
+			fmt.Println("→ Enable one with: heimdall-cli config set render.targets.kitty.enabled true")
+			return nil
+		}
+
+		data := config.NewThemeData(cfg)
+		check, _ := cmd.Flags().GetBool("check")
+		stdout, _ := cmd.Flags().GetBool("stdout")
+		noReload, _ := cmd.Flags().GetBool("no-reload")
+		var runner config.HookRunner = config.ShellHookRunner{}
+
+		if !stdout && !check {
+			return writeTargets(renderer, targets, data, runner, !noReload)
+		}
+
+		stale := 0
+		for _, target := range targets {
+			rendered, err := renderer.Render(target.Template, data)
+			if err != nil {
+				return fmt.Errorf("target %s: %w", target.Name, err)
+			}
+			if err := writeRendered(cmd, target.Output, rendered); err != nil {
+				stale++
+			}
+		}
+
+		if stale > 0 {
+			return fmt.Errorf("%d rendered files are out of date", stale)
+		}
+		return nil
+	},
+}
+
+// writeTargets renders each target, writes the files that changed and runs
+// their reload commands when reload is set
+func writeTargets(renderer *config.TemplateRenderer, targets []config.ThemeTarget, data *config.ThemeData, runner config.HookRunner, reload bool) error {
+	for _, target := range targets {
+		rendered, err := renderer.Render(target.Template, data)
+		if err != nil {
+			return fmt.Errorf("target %s: %w", target.Name, err)
+		}
+
+		drift, err := config.RenderDrift(target.Output, rendered)
+		if err != nil {
+			return err
+		}
+		if len(drift) == 0 {
+			fmt.Printf("✓ %s is up to date (%s)\n", target.Name, target.Output)
+			continue
+		}
+
+		if err := config.WriteRenderedFile(target.Output, rendered); err != nil {
+			return fmt.Errorf("failed to write %s: %w", target.Output, err)
+		}
+		fmt.Printf("✓ Rendered %s → %s\n", target.Name, target.Output)
+
+		if target.Reload != "" && reload {
+			if err := runner.Run(target.Reload); err != nil {
+				fmt.Printf("⚠ Reload of %s failed: %v\n", target.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// renderTemplatesCmd lists templates and configured targets
+var renderTemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List render templates and targets",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadRenderConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		renderer := config.NewTemplateRenderer(config.GetTemplatesDir())
+		templates, err := renderer.Templates()
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("Templates:")
+		for _, t := range templates {
+			source := "built-in"
+			if !t.Builtin {
+				source = t.Path
+			}
+			fmt.Printf("  %-10s %s\n", t.Name, source)
+		}
+
+		names := make([]string, 0, len(cfg.Render.Targets))
+		for name := range cfg.Render.Targets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		fmt.Println("\nTargets:")
+		if len(names) == 0 {
+			fmt.Println("  (none configured)")
+		}
+		for _, name := range names {
+			state := "enabled"
+			if !cfg.Render.Targets[name].Enabled {
+				state = "disabled"
+			}
+			target, err := renderer.Resolve(name, cfg.Render.Targets[name])
+			if err != nil {
+				fmt.Printf("  ⚠ %-10s %s\n", name, err)
+				continue
+			}
+			fmt.Printf("  %-10s %-8s %s → %s\n", name, state, target.Template, target.Output)
+			if target.Reload != "" {
+				fmt.Printf("  %-10s reload: %s\n", "", target.Reload)
+			}
+		}
+
+		return nil
+	},
+}
+
+// renderQuickshellCmd writes Quickshell's default.json
+var renderQuickshellCmd = &cobra.Command{
+	Use:   "quickshell",
+	Short: "Generate Quickshell's config/default.json",
+	Long: `Generate Quickshell's config/default.json from the shell configuration.
+
+Only the sections heimdall manages are written; everything else in an
+existing default.json is kept:
+  appearance.animationSpeed  → appearance.anim.durations
+  system.font                → appearance.font.family.mono/sans, appearance.font.size
+  appearance.borderRadius    → appearance.rounding
+  bar.padding, bar.spacing   → appearance.padding, appearance.spacing
+  appearance.transparency    → appearance.transparency
+  bar.height                 → bar.sizes.innerHeight
+
+The default output is $XDG_CONFIG_HOME/quickshell/config/default.json
+(override with HEIMDALL_QUICKSHELL_CONFIG or --output).`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		if output == "" {
+			output = config.GetQuickshellConfigPath()
+		}
+		output = config.ExpandHome(output)
+
+		cfg, err := loadRenderConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		existing, err := os.ReadFile(output)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read %s: %w", output, err)
+		}
+
+		fresh, _ := cmd.Flags().GetBool("fresh")
+		if fresh {
+			existing = nil
+		}
+
+		data, err := config.RenderQuickshell(cfg, existing)
+		if err != nil {
+			return fmt.Errorf("failed to render Quickshell config: %w", err)
+		}
+
+		return writeRendered(cmd, output, data)
+	},
+}
+
+// renderHyprlandCmd writes the heimdall.conf fragment for Hyprland
+var renderHyprlandCmd = &cobra.Command{
+	Use:   "hyprland",
+	Short: "Generate a Hyprland config fragment (heimdall.conf)",
+	Long: `Generate a Hyprland config fragment from the shell configuration.
+
+The fragment holds general (border size and colors), decoration (rounding,
+blur, shadows) and animations blocks, plus a bind line for every custom
+command with a shortcut. Shortcuts are written as modifiers and an XKB key
+joined by "+", e.g. "Super+Return" or "Ctrl+Alt+T"; unknown names are errors.
+
+The default output is $XDG_CONFIG_HOME/hypr/heimdall.conf (override with
+HEIMDALL_HYPRLAND_CONFIG or --output). Source it from hyprland.conf:
+  source = ~/.config/hypr/heimdall.conf`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		if output == "" {
+			output = config.GetHyprlandConfigPath()
+		}
+		output = config.ExpandHome(output)
+
+		cfg, err := loadRenderConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		data, err := config.RenderHyprland(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to render Hyprland config: %w", err)
+		}
+
+		return writeRendered(cmd, output, data)
+	},
+}
+
+// loadRenderConfig loads the configuration to render, or a profile when
+// --profile is given, with its colors resolved to hex
+func loadRenderConfig(cmd *cobra.Command) (*config.ShellConfig, error) {
+	var cfg *config.ShellConfig
+	profile, _ := cmd.Flags().GetString("profile")
+	if profile != "" {
+		var err error
+		if cfg, err = config.LoadProfile(profile); err != nil {
+			return nil, fmt.Errorf("failed to load profile: %w", err)
+		}
+	} else {
+		logger := NewLogger()
+		manager, err := config.NewConfigManager(logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create config manager: %w", err)
+		}
+
+		// Load configuration
+		if cfg, err = manager.Load(); err != nil {
+			return nil, fmt.Errorf("failed to load configuration: %w", err)
+		}
+	}
+
+	// Generated files only understand plain hex colors
+	if err := config.ResolveColors(cfg); err != nil {
+		return nil, fmt.Errorf("failed to resolve colors: %w", err)
+	}
+	return cfg, nil
+}
+
+// writeRendered prints, checks or writes rendered output according to the
+// --stdout and --check flags
+func writeRendered(cmd *cobra.Command, output string, data []byte) error {
+	if stdout, _ := cmd.Flags().GetBool("stdout"); stdout {
+		fmt.Print(string(data))
+		return nil
+	}
+
+	if check, _ := cmd.Flags().GetBool("check"); check {
+		drift, err := config.RenderDrift(output, data)
+		if err != nil {
+			return err
+		}
+		if len(drift) == 0 {
+			fmt.Printf("✓ %s is up to date\n", output)
+			return nil
+		}
+		fmt.Printf("⚠ %s differs from the rendered output:\n", output)
+		for _, line := range drift {
+			fmt.Printf("  %s\n", line)
+		}
+		return fmt.Errorf("%s is out of date", output)
+	}
+
+	if err := config.WriteRenderedFile(output, data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	fmt.Printf("✓ Rendered %s\n", output)
+	return nil
+}
+
+// addRenderFlags registers the flags shared by render subcommands
+func addRenderFlags(cmd *cobra.Command) {
+	cmd.Flags().String("profile", "", "Render a profile instead of the current configuration")
+	cmd.Flags().Bool("stdout", false, "Print the rendered file instead of writing it")
+	cmd.Flags().Bool("check", false, "Report drift from the file on disk without writing it")
+	cmd.RegisterFlagCompletionFunc("profile", completeProfiles)
+}
+
+// completeRenderTargets suggests template and configured target names
+func completeRenderTargets(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	if templates, err := config.NewTemplateRenderer(config.GetTemplatesDir()).Templates(); err == nil {
+		for _, t := range templates {
+			seen[t.Name] = true
+			names = append(names, t.Name)
+		}
+	}
+	if manager, err := config.NewConfigManager(NewLogger()); err == nil {
+		if cfg, err := manager.Load(); err == nil {
+			for name := range cfg.Render.Targets {
+				if !seen[name] {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	addRenderFlags(RenderCmd)
+	RenderCmd.Flags().Bool("no-reload", false, "Do not run reload commands after writing")
+	RenderCmd.ValidArgsFunction = completeRenderTargets
+	renderTemplatesCmd.Flags().String("profile", "", "Read targets from a profile instead of the current configuration")
+	renderTemplatesCmd.RegisterFlagCompletionFunc("profile", completeProfiles)
+
+	for _, c := range []*cobra.Command{renderQuickshellCmd, renderHyprlandCmd} {
+		addRenderFlags(c)
+		c.Flags().StringP("output", "o", "", "Output file")
+	}
+	renderQuickshellCmd.Flags().Bool("fresh", false, "Ignore the existing file instead of merging into it")
+
+	RenderCmd.AddCommand(renderQuickshellCmd)
+	RenderCmd.AddCommand(renderHyprlandCmd)
+	RenderCmd.AddCommand(renderTemplatesCmd)
+}
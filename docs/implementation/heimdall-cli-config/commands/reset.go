@@ -0,0 +1,139 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"heimdall-cli/config"
+)
+
+// resetCmd restores paths to their profile defaults
+var resetCmd = &cobra.Command{
+	Use:   "reset <path|glob>...",
+	Short: "Reset paths or sections to their profile defaults",
+	Long: `Reset configuration paths to the values of a profile.
+Paths may name a single value, a whole section or use globs:
+  heimdall-cli config reset bar.height
+  heimdall-cli config reset appearance.colors "bar.*.top"
+  heimdall-cli config reset --all --profile minimal
+
+Values come from the config's current profile unless --profile is given.
+Locked paths are skipped unless --force is set. A preview of the changes is
+shown and confirmed before anything is written.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		force, _ := cmd.Flags().GetBool("force")
+		yes, _ := cmd.Flags().GetBool("yes")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		profile, _ := cmd.Flags().GetString("profile")
+
+		if !all && len(args) == 0 {
+			return fmt.Errorf("specify paths to reset or use --all")
+		}
+		if all && len(args) > 0 {
+			return fmt.Errorf("--all cannot be combined with explicit paths")
+		}
+
+		logger := NewLogger()
+		manager, err := config.NewConfigManager(logger)
+		if err != nil {
+			return fmt.Errorf("failed to create config manager: %w", err)
+		}
+
+		// Load configuration
+		cfg, err := manager.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+
+		// Resolve the paths to reset
+		paths := config.SchemaPaths()
+		if !all {
+			paths, err = config.MatchPaths(args)
+			if err != nil {
+				return err
+			}
+		}
+
+		if profile == "" {
+			profile = cfg.Metadata.Profile
+		}
+		if profile == "" {
+			profile = "default"
+		}
+		source, err := config.LoadProfile(profile)
+		if err != nil {
+			return err
+		}
+
+		// Plan and preview the changes
+		injector := config.NewPropertyInjector()
+		plan, err := injector.PlanReset(cfg, source, paths, force)
+		if err != nil {
+			return fmt.Errorf("failed to plan reset: %w", err)
+		}
+
+		for _, path := range plan.Locked {
+			fmt.Printf("🔒 %s is locked, skipping (use --force to reset it)\n", path)
+		}
+
+		if len(plan.Changes) == 0 {
+			fmt.Printf("✓ Nothing to reset: values already match profile '%s'\n", profile)
+			return nil
+		}
+
+		fmt.Printf("Resetting %d values to profile '%s':\n", len(plan.Changes), profile)
+		for _, change := range plan.Changes {
+			fmt.Printf("  - %s: %s\n", change.Path, config.FormatValue(change.Old))
+			fmt.Printf("  + %s: %s\n", change.Path, config.FormatValue(change.New))
+		}
+
+		if dryRun {
+			return nil
+		}
+		if !yes && !confirm("Apply these changes?") {
+			fmt.Println("Aborted")
+			return nil
+		}
+
+		// Apply and save
+		if err := injector.ApplyChanges(cfg, plan.Changes); err != nil {
+			return err
+		}
+		if err := manager.Save(cfg); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+
+		fmt.Printf("✓ Reset %d values\n", len(plan.Changes))
+		return nil
+	},
+}
+
+// confirm asks a yes/no question on stdin, defaulting to no
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func init() {
+	resetCmd.Flags().Bool("all", false, "Reset every path")
+	resetCmd.Flags().String("profile", "", "Profile to take values from (default: the config's profile)")
+	resetCmd.Flags().BoolP("force", "f", false, "Also reset locked paths")
+	resetCmd.Flags().BoolP("yes", "y", false, "Apply without asking for confirmation")
+	resetCmd.Flags().Bool("dry-run", false, "Only show the changes")
+
+	resetCmd.ValidArgsFunction = completeResetPaths
+	resetCmd.RegisterFlagCompletionFunc("profile", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeProfiles(cmd, nil, toComplete)
+	})
+
+	ConfigCmd.AddCommand(resetCmd)
+}
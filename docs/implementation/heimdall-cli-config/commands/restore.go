@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"heimdall-cli/config"
+)
+
+// restoreCmd restores the configuration from a backup
+var restoreCmd = &cobra.Command{
+	Use:   "restore [backup-id]",
+	Short: "Restore the configuration from a backup",
+	Long: `Restore the configuration from a backup in ~/.config/heimdall/backups/.
+Without arguments, lists the available backups, newest first.
+The current configuration is backed up before it is replaced.
+Backups cover shell.json only; shell.d fragments and host files are left
+as they are.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger := NewLogger()
+		manager, err := config.NewConfigManager(logger)
+		if err != nil {
+			return fmt.Errorf("failed to create config manager: %w", err)
+		}
+
+		if len(args) == 0 {
+			backups, err := manager.ListBackups()
+			if err != nil {
+				return fmt.Errorf("failed to list backups: %w", err)
+			}
+			if len(backups) == 0 {
+				fmt.Println("No backups found")
+				return nil
+			}
+			for _, b := range backups {
+				fmt.Printf("%s  %s  %d bytes\n", b.ModTime.Format("2006-01-02 15:04:05"), b.ID, b.Size)
+			}
+			return nil
+		}
+
+		if err := manager.RestoreBackup(args[0]); err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ Configuration restored from %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	ConfigCmd.AddCommand(restoreCmd)
+}
@@ -0,0 +1,281 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"heimdall-cli/config"
+)
+
+// schemeCmd groups color scheme commands
+var schemeCmd = &cobra.Command{
+	Use:   "scheme",
+	Short: "Manage color schemes",
+	Long: `Manage the color schemes appearance.colorScheme refers to.
+Applying a scheme fills appearance.colors, appearance.accentColor and
+bar.background/foreground. User schemes are stored in
+~/.config/heimdall/schemes/<name>.json and replace built-in schemes of the
+same name.`,
+}
+
+// schemeListCmd lists the available schemes
+var schemeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available color schemes",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		registry := config.NewSchemeRegistry(config.GetSchemesDir())
+		schemes, err := registry.List()
+		if err != nil {
+			return err
+		}
+
+		// Mark the scheme the current config uses
+		current := ""
+		if cfg := loadConfigQuietly(); cfg != nil {
+			current = cfg.Appearance.ColorScheme
+		}
+
+		for _, s := range schemes {
+			marker := " "
+			if s.Name == current {
+				marker = "*"
+			}
+			source := "built-in"
+			if !s.Builtin {
+				source = "user"
+			}
+			line := fmt.Sprintf("%s %-22s %-5s %-8s %s", marker, s.Name, s.Variant, source, s.Description)
+			fmt.Println(strings.TrimRight(line, " "))
+		}
+		return nil
+	},
+}
+
+// schemeShowCmd prints a scheme's palette
+var schemeShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show a color scheme's palette",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		registry := config.NewSchemeRegistry(config.GetSchemesDir())
+		scheme, err := registry.Get(args[0])
+		if err != nil {
+			return err
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			data, err := json.MarshalIndent(scheme, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal scheme: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Scheme: %s (%s)\n", scheme.Name, scheme.Variant)
+		if scheme.Description != "" {
+			fmt.Printf("Description: %s\n", scheme.Description)
+		}
+		if scheme.Path != "" {
+			fmt.Printf("File: %s\n", scheme.Path)
+		}
+		fmt.Println()
+		fmt.Printf("  %-11s %s\n", "accent", scheme.Accent)
+
+		roles := scheme.Colors.Roles()
+		names := make([]string, 0, len(roles))
+		for role := range roles {
+			names = append(names, role)
+		}
+		sort.Strings(names)
+		for _, role := range names {
+			fmt.Printf("  %-11s %s\n", role, roles[role])
+		}
+		return nil
+	},
+}
+
+// schemeApplyCmd applies a scheme to the configuration
+var schemeApplyCmd = &cobra.Command{
+	Use:   "apply <name>",
+	Short: "Apply a color scheme",
+	Long: `Apply a color scheme: set appearance.colorScheme and fill
+appearance.colors, appearance.accentColor and bar.background/foreground.
+Locked paths keep their value.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		registry := config.NewSchemeRegistry(config.GetSchemesDir())
+		scheme, err := registry.Get(args[0])
+		if err != nil {
+			return err
+		}
+
+		logger := NewLogger()
+		manager, err := config.NewConfigManager(logger)
+		if err != nil {
+			return fmt.Errorf("failed to create config manager: %w", err)
+		}
+
+		// Load configuration
+		cfg, err := manager.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+
+		changes, locked, err := config.ApplyScheme(cfg, scheme)
+		if err != nil {
+			return fmt.Errorf("failed to apply scheme: %w", err)
+		}
+		printSchemeChanges(changes, locked)
+
+		if dryRun || len(changes) == 0 {
+			return nil
+		}
+
+		if err := manager.Save(cfg); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+		fmt.Printf("✓ Applied color scheme '%s'\n", scheme.Name)
+		return nil
+	},
+}
+
+// schemeImportCmd imports Base16/Base24, kitty and alacritty color schemes
+var schemeImportCmd = &cobra.Command{
+	Use:   "import <file|url|->",
+	Short: "Import a Base16/Base24, kitty or alacritty color scheme",
+	Long: `Import a color scheme into the scheme registry.
+
+Base16 and Base24 YAML (.yaml/.yml), kitty color files (.conf) and alacritty
+TOML themes (.toml) are mapped onto the ten appearance.colors roles:
+
+  role        Base16/Base24   kitty / alacritty
+  background  base00          background
+  foreground  base05          foreground
+  surface     base02          color0 / normal.black
+  border      base03          color8 / bright.black
+  error       base08          color1 / normal.red
+  warning     base0A          color3 / normal.yellow
+  success     base0B          color2 / normal.green
+  info        base0C          color6 / normal.cyan
+  primary     base0D          color4 / normal.blue
+  secondary   base0E          color5 / normal.magenta
+
+Light terminal themes take the surface from color7 / normal.white instead.
+The accent is the primary color, except for kitty files that set
+active_border_color. The variant comes from the file when it says, otherwise
+from the background's luminance.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		source := args[0]
+		format, _ := cmd.Flags().GetString("format")
+		if format == "" {
+			detected, err := config.DetectSchemeFormat(source)
+			if err != nil {
+				return err
+			}
+			format = detected
+		}
+
+		data, err := config.NewSourceFetcher().Fetch(source, config.DefaultImportLimit)
+		if err != nil {
+			return err
+		}
+
+		scheme, err := config.ParseSchemeFile(data, format, source)
+		if err != nil {
+			return fmt.Errorf("failed to import scheme: %w", err)
+		}
+		if name, _ := cmd.Flags().GetString("name"); name != "" {
+			scheme.Name = name
+		}
+		if scheme.Name == "" {
+			return fmt.Errorf("cannot derive a scheme name from %s (use --name)", source)
+		}
+
+		if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+			out, err := json.MarshalIndent(scheme, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal scheme: %w", err)
+			}
+			fmt.Println(string(out))
+			return nil
+		}
+
+		registry := config.NewSchemeRegistry(config.GetSchemesDir())
+		force, _ := cmd.Flags().GetBool("force")
+		if registry.Exists(scheme.Name) && !force {
+			return fmt.Errorf("scheme '%s' already exists (use --force to replace it, or --name)", scheme.Name)
+		}
+
+		path, err := registry.Save(scheme)
+		if err != nil {
+			return fmt.Errorf("failed to save scheme: %w", err)
+		}
+		fmt.Printf("✓ Imported scheme '%s' (%s) to %s\n", scheme.Name, scheme.Variant, path)
+		fmt.Printf("→ Apply it with: heimdall-cli config scheme apply %s\n", scheme.Name)
+		return nil
+	},
+}
+
+// applySchemeSetting fills the scheme colors after `config set
+// appearance.colorScheme` changed the name in cfg
+func applySchemeSetting(cfg *config.ShellConfig) error {
+	registry := config.NewSchemeRegistry(config.GetSchemesDir())
+	scheme, err := registry.Get(cfg.Appearance.ColorScheme)
+	if err != nil {
+		return fmt.Errorf("%w (see heimdall-cli config scheme list)", err)
+	}
+
+	changes, locked, err := config.ApplyScheme(cfg, scheme)
+	if err != nil {
+		return fmt.Errorf("failed to apply scheme: %w", err)
+	}
+	printSchemeChanges(changes, locked)
+	return nil
+}
+
+// printSchemeChanges lists the values a scheme changes and the locked ones it skips
+func printSchemeChanges(changes []config.PathChange, locked []string) {
+	if len(changes) == 0 {
+		fmt.Println("No values change")
+	}
+	for _, change := range changes {
+		fmt.Printf("  %s\n", change)
+	}
+	for _, path := range locked {
+		fmt.Printf("  🔒 %s is locked and keeps its value\n", path)
+	}
+}
+
+// completeSchemes suggests color scheme names
+func completeSchemes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return config.NewSchemeRegistry(config.GetSchemesDir()).Names(), cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	schemeShowCmd.Flags().Bool("json", false, "Print the scheme as JSON")
+	schemeApplyCmd.Flags().Bool("dry-run", false, "Only show the changes")
+	schemeImportCmd.Flags().String("format", "", "Scheme format: base16, kitty or alacritty (default: from the extension)")
+	schemeImportCmd.Flags().String("name", "", "Scheme name (default: from the file)")
+	schemeImportCmd.Flags().BoolP("force", "f", false, "Replace an existing scheme")
+	schemeImportCmd.Flags().Bool("dry-run", false, "Print the mapped scheme without saving it")
+	schemeImportCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(config.SchemeFormats, cobra.ShellCompDirectiveNoFileComp))
+	schemeShowCmd.ValidArgsFunction = completeSchemes
+	schemeApplyCmd.ValidArgsFunction = completeSchemes
+
+	schemeCmd.AddCommand(schemeListCmd)
+	schemeCmd.AddCommand(schemeShowCmd)
+	schemeCmd.AddCommand(schemeApplyCmd)
+	schemeCmd.AddCommand(schemeImportCmd)
+	ConfigCmd.AddCommand(schemeCmd)
+}
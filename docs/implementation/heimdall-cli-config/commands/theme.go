@@ -0,0 +1,286 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"heimdall-cli/config"
+)
+
+// ThemeCmd switches between the light and dark palettes
+var ThemeCmd = &cobra.Command{
+	Use:   "theme",
+	Short: "Switch between the light and dark palettes",
+	Long: `Switch appearance.theme between light and dark. Each variant has its own
+palette under appearance.palettes: a color scheme plus optional colors and
+accent laid over it.
+
+  "appearance": {
+    "palettes": {
+      "light": {"colorScheme": "catppuccin-latte"},
+      "dark":  {"colorScheme": "catppuccin-mocha", "colors": {"primary": "#f5c2e7"}}
+    },
+    "schedule": {"mode": "sun", "latitude": 51.5, "longitude": -0.13}
+  }
+
+A switch saves shell.json, then renders the enabled render targets and runs
+their reload commands.`,
+}
+
+// themeSetCmd switches to a variant by hand
+var themeSetCmd = &cobra.Command{
+	Use:       "set <light|dark>",
+	Short:     "Switch to the light or dark palette",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: config.SchemeVariants,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		noRender, _ := cmd.Flags().GetBool("no-render")
+
+		logger := NewLogger()
+		manager, err := config.NewConfigManager(logger)
+		if err != nil {
+			return fmt.Errorf("failed to create config manager: %w", err)
+		}
+
+		// Load configuration
+		cfg, err := manager.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+
+		return switchTheme(manager, cfg, args[0], dryRun, !noRender)
+	},
+}
+
+// themeAutoCmd follows appearance.schedule
+var themeAutoCmd = &cobra.Command{
+	Use:   "auto",
+	Short: "Switch the theme by time of day",
+	Long: `Select light or dark from appearance.schedule and switch to it.
+
+Modes:
+  fixed  light from schedule.light until schedule.dark (HH:MM, local time)
+  sun    light from sunrise until sunset at schedule.latitude/longitude
+  off    no scheduling
+
+Sunrise and sunset are calculated offline from the coordinates; see
+'heimdall-cli theme sun'. Polar days stay light and polar nights dark.
+
+With --watch the schedule is re-evaluated every interval and at each
+switch time, and a switch only happens when the scheduled theme changes, so
+a manual 'theme set' is not undone until the next sunrise or sunset.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		watch, _ := cmd.Flags().GetBool("watch")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		noRender, _ := cmd.Flags().GetBool("no-render")
+		interval, _ := cmd.Flags().GetDuration("interval")
+
+		logger := NewLogger()
+		manager, err := config.NewConfigManager(logger)
+		if err != nil {
+			return fmt.Errorf("failed to create config manager: %w", err)
+		}
+
+		if !watch {
+			_, _, err := applyScheduledTheme(manager, "", dryRun, !noRender)
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		last := ""
+		for {
+			selected, next, err := applyScheduledTheme(manager, last, dryRun, !noRender)
+			if err != nil {
+				logger.Error("Scheduled theme switch failed", config.Field{Key: "error", Value: err})
+			} else {
+				last = selected
+			}
+
+			wait := interval
+			if wait <= 0 {
+				wait = time.Minute
+			}
+			if !next.IsZero() {
+				// Wake up just after the switch time rather than up to an interval late
+				until := time.Until(next) + time.Second
+				if until < time.Second {
+					// A switch time already past must not turn this into a busy loop
+					until = time.Second
+				}
+				if until < wait {
+					wait = until
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(wait):
+			}
+		}
+	},
+}
+
+// applyScheduledTheme evaluates the schedule once and switches when the
+// scheduled theme differs from both the active theme and previous (the last
+// selection in watch mode). It returns the selection and the next switch time.
+func applyScheduledTheme(manager *config.ConfigManager, previous string, dryRun, render bool) (string, time.Time, error) {
+	// Load configuration
+	cfg, err := manager.Load()
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	schedule := cfg.Appearance.Schedule
+	theme, next, err := config.ScheduledTheme(schedule, time.Now())
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if theme == previous {
+		return theme, next, nil
+	}
+
+	until := ""
+	if !next.IsZero() {
+		until = fmt.Sprintf(" until %s", next.Format("Jan 2 15:04"))
+	}
+	if theme == cfg.Appearance.Theme {
+		fmt.Printf("✓ Theme '%s' already active (%s schedule%s)\n", theme, schedule.Mode, until)
+		return theme, next, nil
+	}
+
+	fmt.Printf("Schedule (%s) selected theme '%s'%s\n", schedule.Mode, theme, until)
+	if err := switchTheme(manager, cfg, theme, dryRun, render); err != nil {
+		return "", time.Time{}, err
+	}
+	return theme, next, nil
+}
+
+// switchTheme applies a variant's palette, saves it and re-renders the
+// enabled targets so their programs reload
+func switchTheme(manager *config.ConfigManager, cfg *config.ShellConfig, theme string, dryRun, render bool) error {
+	changes, locked, err := config.SwitchTheme(cfg, theme)
+	if err != nil {
+		return err
+	}
+	printSchemeChanges(changes, locked)
+	if dryRun || len(changes) == 0 {
+		return nil
+	}
+
+	if err := manager.Save(cfg); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+	fmt.Printf("✓ Switched to theme '%s'\n", theme)
+
+	if !render {
+		return nil
+	}
+	resolved, err := config.CloneConfig(cfg)
+	if err != nil {
+		return err
+	}
+	// Generated files only understand plain hex colors
+	if err := config.ResolveColors(resolved); err != nil {
+		return fmt.Errorf("failed to resolve colors: %w", err)
+	}
+	renderer := config.NewTemplateRenderer(config.GetTemplatesDir())
+	targets, err := renderer.Targets(&resolved.Render, nil)
+	if err != nil {
+		return err
+	}
+	return writeTargets(renderer, targets, config.NewThemeData(resolved), config.ShellHookRunner{}, true)
+}
+
+// themeSunCmd prints the sunrise and sunset the sun schedule uses
+var themeSunCmd = &cobra.Command{
+	Use:   "sun",
+	Short: "Show sunrise and sunset for the configured location",
+	Long: `Calculate sunrise and sunset offline with NOAA's solar position
+equations, for appearance.schedule.latitude/longitude or --latitude and
+--longitude (degrees, north and east positive). Times are local unless
+--utc is given.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dateFlag, _ := cmd.Flags().GetString("date")
+		utc, _ := cmd.Flags().GetBool("utc")
+
+		date := time.Now()
+		if dateFlag != "" {
+			parsed, err := time.ParseInLocation("2006-01-02", dateFlag, time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid date %q (use YYYY-MM-DD)", dateFlag)
+			}
+			date = parsed
+		}
+		if utc {
+			date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+		}
+
+		var schedule config.ThemeSchedule
+		if !cmd.Flags().Changed("latitude") || !cmd.Flags().Changed("longitude") {
+			logger := NewLogger()
+			manager, err := config.NewConfigManager(logger)
+			if err != nil {
+				return fmt.Errorf("failed to create config manager: %w", err)
+			}
+
+			// Load configuration
+			cfg, err := manager.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			schedule = cfg.Appearance.Schedule
+		}
+		if cmd.Flags().Changed("latitude") {
+			schedule.Latitude, _ = cmd.Flags().GetFloat64("latitude")
+		}
+		if cmd.Flags().Changed("longitude") {
+			schedule.Longitude, _ = cmd.Flags().GetFloat64("longitude")
+		}
+		if !schedule.HasLocation() {
+			return fmt.Errorf("no location set (use --latitude/--longitude or set appearance.schedule.latitude and longitude)")
+		}
+
+		times := config.CalculateSunTimes(date, schedule.Latitude, schedule.Longitude)
+		fmt.Printf("%s at %.4f, %.4f\n", date.Format("2006-01-02"), schedule.Latitude, schedule.Longitude)
+		switch {
+		case times.PolarDay:
+			fmt.Println("  The sun does not set (polar day)")
+		case times.PolarNight:
+			fmt.Println("  The sun does not rise (polar night)")
+		default:
+			fmt.Printf("  Sunrise: %s\n", times.Sunrise.Format("2006-01-02 15:04 MST"))
+			fmt.Printf("  Sunset:  %s\n", times.Sunset.Format("2006-01-02 15:04 MST"))
+		}
+		return nil
+	},
+}
+
+func init() {
+	themeSetCmd.Flags().Bool("dry-run", false, "Only show the changes")
+	themeSetCmd.Flags().Bool("no-render", false, "Do not render targets or run reload commands")
+	themeAutoCmd.Flags().Bool("once", false, "Evaluate the schedule once and exit (default)")
+	themeAutoCmd.Flags().Bool("watch", false, "Keep following the schedule")
+	themeAutoCmd.Flags().Duration("interval", 0, "Longest time between evaluations with --watch (default 1m)")
+	themeAutoCmd.Flags().Bool("dry-run", false, "Show the selection and changes without switching")
+	themeAutoCmd.Flags().Bool("no-render", false, "Do not render targets or run reload commands")
+	themeAutoCmd.MarkFlagsMutuallyExclusive("once", "watch")
+	themeSunCmd.Flags().String("date", "", "Date to calculate (YYYY-MM-DD, default: today)")
+	themeSunCmd.Flags().Float64("latitude", 0, "Latitude in degrees (default: appearance.schedule.latitude)")
+	themeSunCmd.Flags().Float64("longitude", 0, "Longitude in degrees (default: appearance.schedule.longitude)")
+	themeSunCmd.Flags().Bool("utc", false, "Print times in UTC")
+
+	ThemeCmd.AddCommand(themeSetCmd)
+	ThemeCmd.AddCommand(themeAutoCmd)
+	ThemeCmd.AddCommand(themeSunCmd)
+}
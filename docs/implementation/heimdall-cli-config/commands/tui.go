@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"heimdall-cli/config"
+	"heimdall-cli/tui"
+)
+
+// tuiCmd opens the interactive configuration browser
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse and edit the configuration interactively",
+	Long: `Open a terminal tree browser over the configuration schema.
+Values are validated as you edit them. Enum fields cycle with ←/→ or open a
+picker with enter, and colors are shown as swatches. Locked paths are marked
+with L and values that differ from the profile default with *.
+
+Keys: s save, r revert the selected path, R revert everything,
+d reset the selected path to its profile default, q quit.
+
+Use --script to drive the browser headless from a key script, e.g.
+  echo "right down enter type:zsh enter s" > keys.txt
+  heimdall-cli config tui --script keys.txt`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger := NewLogger()
+		manager, err := config.NewConfigManager(logger)
+		if err != nil {
+			return fmt.Errorf("failed to create config manager: %w", err)
+		}
+
+		// Load configuration
+		cfg, err := manager.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+
+		browser, err := tui.NewBrowser(cfg, manager, cmd.OutOrStdout())
+		if err != nil {
+			return fmt.Errorf("failed to start browser: %w", err)
+		}
+
+		// Headless mode replays a key script
+		scriptPath, _ := cmd.Flags().GetString("script")
+		if scriptPath != "" {
+			script, err := os.ReadFile(scriptPath)
+			if err != nil {
+				return fmt.Errorf("failed to read script: %w", err)
+			}
+			input, err := tui.ParseScript(string(script))
+			if err != nil {
+				return err
+			}
+			return browser.Run(input)
+		}
+
+		fd := int(os.Stdin.Fd())
+		if !term.IsTerminal(fd) {
+			return fmt.Errorf("config tui requires an interactive terminal (use --script for headless runs)")
+		}
+		if _, height, err := term.GetSize(fd); err == nil {
+			browser.SetHeight(height)
+		}
+
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("failed to enter raw mode: %w", err)
+		}
+		defer term.Restore(fd, state)
+
+		return browser.Run(tui.NewTerminalInput(os.Stdin))
+	},
+}
+
+func init() {
+	tuiCmd.Flags().String("script", "", "Replay key presses from a script file instead of the terminal")
+
+	ConfigCmd.AddCommand(tuiCmd)
+}
@@ -0,0 +1,645 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"heimdall-cli/config"
+)
+
+// WallpaperCmd manages the wallpaper library and slideshow
+var WallpaperCmd = &cobra.Command{
+	Use:   "wallpaper",
+	Short: "Index, set and rotate wallpapers",
+	Long: `Manage the images in wallpaper.directory and show them with swww or
+hyprpaper (wallpaper.backend, auto picks whichever is installed).
+
+The index caches each image's format, size and aspect ratio, read from the
+file headers, and is rebuilt when the directory changes. Playback state, the
+image and rotation order of every monitor, is kept in
+$XDG_STATE_HOME/heimdall/wallpaper.json so next, prev and the daemon carry on
+where they left off. The slideshow runs on the outputs in monitors that have
+no wallpaper of their own; with none configured one rotation drives every
+output.`,
+}
+
+// wallpaperIndexCmd rescans the wallpaper directory
+var wallpaperIndexCmd = &cobra.Command{
+	Use:   "index [directory]",
+	Short: "Scan the wallpaper directory",
+	Long: `Read the format and dimensions of every PNG, JPEG, GIF and WebP image in
+the directory (default: wallpaper.directory) from its header and save the
+index. Files that cannot be read are reported and left out.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		dir := ""
+		if len(args) > 0 {
+			dir = args[0]
+		} else {
+			cfg, err := loadWallpaperConfig()
+			if err != nil {
+				return err
+			}
+			dir = cfg.Wallpaper.Directory
+		}
+		if dir == "" {
+			return fmt.Errorf("no wallpaper directory (set wallpaper.directory or pass one)")
+		}
+
+		index, err := config.IndexWallpapers(dir)
+		if err != nil {
+			return err
+		}
+		if asJSON {
+			return printJSON(index)
+		}
+
+		indexPath := config.GetWallpaperIndexPath()
+		if err := index.Save(indexPath); err != nil {
+			return fmt.Errorf("failed to save wallpaper index: %w", err)
+		}
+		skipped := make([]string, 0, len(index.Skipped))
+		for path := range index.Skipped {
+			skipped = append(skipped, path)
+		}
+		sort.Strings(skipped)
+		for _, path := range skipped {
+			fmt.Printf("⚠ Skipped %s: %s\n", path, index.Skipped[path])
+		}
+		fmt.Printf("✓ Indexed %d images in %s → %s\n", len(index.Images), index.Directory, indexPath)
+		return nil
+	},
+}
+
+// wallpaperListCmd lists the indexed images
+var wallpaperListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the wallpaper library",
+	Long: `List the indexed images with their size, aspect ratio and the monitors
+showing them. The index is rebuilt first when the directory has changed.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		cfg, err := loadWallpaperConfig()
+		if err != nil {
+			return err
+		}
+		index, err := config.WallpaperLibrary(cfg.Wallpaper.Directory, config.GetWallpaperIndexPath())
+		if err != nil {
+			return err
+		}
+		if asJSON {
+			return printJSON(index.Images)
+		}
+
+		state, err := config.LoadWallpaperState(config.GetWallpaperStatePath())
+		if err != nil {
+			return err
+		}
+		shownOn := make(map[string][]string)
+		for monitor, playback := range state.Monitors {
+			shownOn[playback.Current] = append(shownOn[playback.Current], monitorLabel(monitor))
+		}
+
+		fmt.Printf("%d images in %s:\n", len(index.Images), index.Directory)
+		for _, image := range index.Images {
+			marker := " "
+			suffix := ""
+			if monitors := shownOn[image.Path]; len(monitors) > 0 {
+				sort.Strings(monitors)
+				marker = "*"
+				suffix = "  ← " + strings.Join(monitors, ", ")
+			}
+			fmt.Printf("%s %-32s %5d×%-5d %-6s %-9s %s%s\n", marker, filepath.Base(image.Path),
+				image.Width, image.Height, image.Aspect, image.Orientation(), image.Format, suffix)
+		}
+		return nil
+	},
+}
+
+// wallpaperSetCmd shows one image
+var wallpaperSetCmd = &cobra.Command{
+	Use:   "set <file>",
+	Short: "Show an image",
+	Long: `Show an image on one monitor (--monitor) or on every slideshow monitor.
+Without --monitor in static mode wallpaper.path is saved too. In slideshow
+mode the rotation continues from the image when it is in the library.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		monitor, _ := cmd.Flags().GetString("monitor")
+
+		logger := NewLogger()
+		manager, err := config.NewConfigManager(logger)
+		if err != nil {
+			return fmt.Errorf("failed to create config manager: %w", err)
+		}
+
+		// Load configuration
+		cfg, err := manager.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+
+		path, err := filepath.Abs(config.ExpandHome(args[0]))
+		if err != nil {
+			return err
+		}
+		if _, err := config.ReadWallpaperInfo(path); err != nil {
+			return fmt.Errorf("cannot use %s: %w", path, err)
+		}
+
+		setter, err := wallpaperSetter(cfg, dryRun)
+		if err != nil {
+			return err
+		}
+		monitors := config.SlideshowMonitors(cfg)
+		if monitor != "" {
+			monitors = []string{monitor}
+		}
+		for _, m := range monitors {
+			if err := setter.Set(m, displayedWallpaper(cfg, m, path), cfg.Wallpaper.FillMode); err != nil {
+				return fmt.Errorf("failed to set wallpaper: %w", err)
+			}
+		}
+		if dryRun {
+			return nil
+		}
+
+		// The library is optional here: any readable image can be shown
+		slideshow, _ := openSlideshow(cfg)
+		if slideshow == nil {
+			slideshow = &config.Slideshow{}
+		}
+		state, err := config.LoadWallpaperState(config.GetWallpaperStatePath())
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, m := range monitors {
+			slideshow.Show(state.Monitor(m), path, now)
+		}
+		if err := state.Save(config.GetWallpaperStatePath()); err != nil {
+			return fmt.Errorf("failed to save wallpaper state: %w", err)
+		}
+
+		if monitor == "" && cfg.Wallpaper.Mode != "slideshow" && cfg.Wallpaper.Path != path {
+			cfg.Wallpaper.Path = path
+			if err := manager.Save(cfg); err != nil {
+				return fmt.Errorf("failed to save configuration: %w", err)
+			}
+		}
+		labels := make([]string, len(monitors))
+		for i, m := range monitors {
+			labels[i] = monitorLabel(m)
+		}
+		fmt.Printf("✓ Set %s on %s\n", path, strings.Join(labels, ", "))
+		return nil
+	},
+}
+
+// wallpaperNextCmd and wallpaperPrevCmd step the slideshow by hand
+var wallpaperNextCmd = &cobra.Command{
+	Use:   "next",
+	Short: "Show the next image of the slideshow",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return stepWallpaper(cmd, 1)
+	},
+}
+
+var wallpaperPrevCmd = &cobra.Command{
+	Use:   "prev",
+	Short: "Show the previous image of the slideshow",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return stepWallpaper(cmd, -1)
+	},
+}
+
+// stepWallpaper moves every slideshow monitor, or --monitor, delta images
+func stepWallpaper(cmd *cobra.Command, delta int) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	monitor, _ := cmd.Flags().GetString("monitor")
+
+	cfg, err := loadWallpaperConfig()
+	if err != nil {
+		return err
+	}
+	slideshow, err := openSlideshow(cfg)
+	if err != nil {
+		return err
+	}
+	setter, err := wallpaperSetter(cfg, dryRun)
+	if err != nil {
+		return err
+	}
+	state, err := config.LoadWallpaperState(config.GetWallpaperStatePath())
+	if err != nil {
+		return err
+	}
+
+	monitors := config.SlideshowMonitors(cfg)
+	if monitor != "" {
+		monitors = []string{monitor}
+	}
+	now := time.Now()
+	for _, m := range monitors {
+		path, err := slideshow.Step(state.Monitor(m), delta, now)
+		if err != nil {
+			return err
+		}
+		if err := setter.Set(m, displayedWallpaper(cfg, m, path), cfg.Wallpaper.FillMode); err != nil {
+			return fmt.Errorf("failed to set wallpaper: %w", err)
+		}
+		if !dryRun {
+			fmt.Printf("✓ %s → %s\n", monitorLabel(m), path)
+		}
+	}
+	if dryRun {
+		return nil
+	}
+	if err := state.Save(config.GetWallpaperStatePath()); err != nil {
+		return fmt.Errorf("failed to save wallpaper state: %w", err)
+	}
+	return nil
+}
+
+// wallpaperDaemonCmd runs the slideshow
+var wallpaperDaemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Rotate wallpapers on every monitor",
+	Long: `Run the slideshow: restore each monitor's image, then move each one to
+its next image every wallpaper.interval seconds. Monitors start on
+different images and, with wallpaper.random, each has its own shuffled
+order that is reshuffled after every pass. Outputs with a
+monitors.<output>.wallpaper show that image instead.
+
+The configuration and state are re-read on every check, so 'wallpaper next'
+or 'wallpaper set' restart that monitor's timer and a new interval or
+directory takes effect without a restart. Stop with Ctrl+C or SIGTERM.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		interval, _ := cmd.Flags().GetDuration("interval")
+
+		logger := NewLogger()
+		manager, err := config.NewConfigManager(logger)
+		if err != nil {
+			return fmt.Errorf("failed to create config manager: %w", err)
+		}
+
+		// Load configuration
+		cfg, err := manager.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		if cfg.Wallpaper.Mode != "slideshow" {
+			return fmt.Errorf("wallpaper.mode is %q (set it to slideshow)", cfg.Wallpaper.Mode)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		// A dry run never writes the state file, so keep it in memory
+		var state *config.WallpaperState
+		started := make(map[string]bool)
+		for {
+			wait, err := rotateWallpapers(manager, &state, started, interval, dryRun)
+			if err != nil {
+				logger.Error("Wallpaper rotation failed", config.Field{Key: "error", Value: err})
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(wait):
+			}
+		}
+	},
+}
+
+// rotateWallpapers starts monitors not yet in started and steps the ones
+// whose interval has passed. It returns how long to sleep before the next
+// check.
+func rotateWallpapers(manager *config.ConfigManager, state **config.WallpaperState, started map[string]bool, interval time.Duration, dryRun bool) (time.Duration, error) {
+	const maxWait = time.Minute
+
+	// Load configuration
+	cfg, err := manager.Load()
+	if err != nil {
+		return maxWait, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if cfg.Wallpaper.Mode != "slideshow" {
+		return maxWait, nil
+	}
+	if interval <= 0 {
+		interval = time.Duration(cfg.Wallpaper.Interval) * time.Second
+	}
+	if interval <= 0 {
+		return maxWait, fmt.Errorf("wallpaper.interval must be positive")
+	}
+
+	slideshow, err := openSlideshow(cfg)
+	if err != nil {
+		return maxWait, err
+	}
+	setter, err := wallpaperSetter(cfg, dryRun)
+	if err != nil {
+		return maxWait, err
+	}
+	if *state == nil || !dryRun {
+		if *state, err = config.LoadWallpaperState(config.GetWallpaperStatePath()); err != nil {
+			return maxWait, err
+		}
+	}
+
+	now := time.Now()
+	wait := maxWait
+	changed := false
+
+	// Outputs with a wallpaper of their own are set once, and again when it changes
+	pinned := config.PinnedWallpapers(cfg)
+	for _, m := range cfg.Monitors.MonitorNames() {
+		path, ok := pinned[m]
+		if !ok {
+			continue
+		}
+		playback := (*state).Monitor(m)
+		if started[m] && playback.Current == path {
+			continue
+		}
+		if err := setter.Set(m, displayedWallpaper(cfg, m, path), cfg.Wallpaper.FillMode); err != nil {
+			return maxWait, fmt.Errorf("failed to set wallpaper on %s: %w", m, err)
+		}
+		if !dryRun {
+			fmt.Printf("✓ %s → %s\n", m, path)
+		}
+		slideshow.Show(playback, path, now)
+		started[m] = true
+		changed = true
+	}
+
+	for i, m := range config.SlideshowMonitors(cfg) {
+		playback := (*state).Monitor(m)
+
+		var path string
+		switch {
+		case !started[m]:
+			path, err = slideshow.Start(playback, i, now)
+		case !now.Before(playback.Changed.Add(interval)):
+			path, err = slideshow.Step(playback, 1, now)
+		}
+		if err != nil {
+			return maxWait, err
+		}
+		if path != "" {
+			if err := setter.Set(m, displayedWallpaper(cfg, m, path), cfg.Wallpaper.FillMode); err != nil {
+				return maxWait, fmt.Errorf("failed to set wallpaper on %s: %w", m, err)
+			}
+			if !dryRun {
+				fmt.Printf("✓ %s → %s\n", monitorLabel(m), path)
+			}
+			started[m] = true
+			changed = true
+		}
+
+		if until := playback.Changed.Add(interval).Sub(now); until < wait {
+			wait = until
+		}
+	}
+	if wait < time.Second {
+		wait = time.Second
+	}
+
+	if changed && !dryRun {
+		if err := (*state).Save(config.GetWallpaperStatePath()); err != nil {
+			return wait, fmt.Errorf("failed to save wallpaper state: %w", err)
+		}
+	}
+	return wait, nil
+}
+
+// wallpaperRenderCmd renders the processed copy of a wallpaper
+var wallpaperRenderCmd = &cobra.Command{
+	Use:   "render [file]",
+	Short: "Render a wallpaper for the display",
+	Long: `Fit an image (default: the current wallpaper) to an output's resolution
+(--monitor, or the "*" entry of monitors; --size overrides it) with
+wallpaper.fillMode, blur it by wallpaper.blurStrength when wallpaper.blur is
+set and darken it by wallpaper.dimStrength when wallpaper.dim is set. The
+flags override the configuration.
+
+The result is cached in $XDG_CACHE_HOME/heimdall/wallpapers under a key made
+from the image's content hash and the parameters, so each rendering is
+computed once. set, next, prev and the daemon show this copy whenever blur
+or dimming is enabled.
+
+With -o the rendering is also written to that file; with --check it is
+compared against that file instead, for golden image tests.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		size, _ := cmd.Flags().GetString("size")
+		monitor, _ := cmd.Flags().GetString("monitor")
+		output, _ := cmd.Flags().GetString("output")
+		check, _ := cmd.Flags().GetBool("check")
+		if check && output == "" {
+			return fmt.Errorf("--check needs the golden file as -o")
+		}
+
+		cfg, err := loadWallpaperConfig()
+		if err != nil {
+			return err
+		}
+
+		source := ""
+		if len(args) > 0 {
+			source = config.ExpandHome(args[0])
+		} else if pinned := config.PinnedWallpapers(cfg)[monitor]; pinned != "" {
+			source = pinned
+		} else if source, err = config.CurrentWallpaper(cfg); err != nil {
+			return err
+		}
+
+		var width, height int
+		if size != "" {
+			if width, height, err = config.ParseResolution(size); err != nil {
+				return err
+			}
+		} else {
+			if monitor == "" {
+				monitor = config.AllMonitors
+			}
+			var ok bool
+			if width, height, ok = cfg.Monitor(monitor).PixelSize(); !ok {
+				return fmt.Errorf("no resolution for %s (set monitors.%s.resolution or pass --size WIDTHxHEIGHT)",
+					monitorLabel(monitor), monitor)
+			}
+		}
+		params := config.WallpaperParamsFromConfig(&cfg.Wallpaper, width, height)
+		if cmd.Flags().Changed("fill") {
+			params.FillMode, _ = cmd.Flags().GetString("fill")
+		}
+		if cmd.Flags().Changed("blur") {
+			params.Blur, _ = cmd.Flags().GetInt("blur")
+		}
+		if cmd.Flags().Changed("dim") {
+			params.Dim, _ = cmd.Flags().GetFloat64("dim")
+		}
+
+		if check {
+			rendered, err := config.ProcessWallpaperFile(source, params)
+			if err != nil {
+				return err
+			}
+			pixels, maxDelta, err := config.ImageDrift(output, rendered, 1)
+			if err != nil {
+				return err
+			}
+			if pixels > 0 {
+				return fmt.Errorf("%s differs from the rendering in %d pixels (max difference %d)", output, pixels, maxDelta)
+			}
+			fmt.Printf("✓ %s matches (%s)\n", output, params)
+			return nil
+		}
+
+		cache := config.NewWallpaperCache(config.GetWallpaperCacheDir())
+		path, cached, err := cache.Render(source, params)
+		if err != nil {
+			return err
+		}
+		if output != "" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if err := config.WriteRenderedFile(config.ExpandHome(output), data); err != nil {
+				return fmt.Errorf("failed to write %s: %w", output, err)
+			}
+			path = output
+		}
+		if cached {
+			fmt.Printf("✓ %s (cached, %s)\n", path, params)
+		} else {
+			fmt.Printf("✓ Rendered %s (%s)\n", path, params)
+		}
+		return nil
+	},
+}
+
+// displayedWallpaper returns the file to hand to the backend for path on
+// an output: the processed copy when blur or dimming is enabled and the
+// output's resolution is known, otherwise path itself. Rendering failures
+// fall back to the original with a warning.
+func displayedWallpaper(cfg *config.ShellConfig, monitor, path string) string {
+	if !cfg.Wallpaper.NeedsProcessing() {
+		return path
+	}
+	width, height, ok := cfg.Monitor(monitor).PixelSize()
+	if !ok {
+		return path
+	}
+	params := config.WallpaperParamsFromConfig(&cfg.Wallpaper, width, height)
+	rendered, _, err := config.NewWallpaperCache(config.GetWallpaperCacheDir()).Render(path, params)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ Showing %s unprocessed: %v\n", path, err)
+		return path
+	}
+	return rendered
+}
+
+// monitorLabel names a slideshow monitor for output
+func monitorLabel(monitor string) string {
+	if monitor == config.AllMonitors {
+		return "all outputs"
+	}
+	return monitor
+}
+
+// loadWallpaperConfig loads the configuration for the read-only commands
+func loadWallpaperConfig() (*config.ShellConfig, error) {
+	logger := NewLogger()
+	manager, err := config.NewConfigManager(logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config manager: %w", err)
+	}
+
+	// Load configuration
+	cfg, err := manager.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// openSlideshow builds the slideshow over the indexed wallpaper directory
+func openSlideshow(cfg *config.ShellConfig) (*config.Slideshow, error) {
+	if cfg.Wallpaper.Directory == "" {
+		return nil, fmt.Errorf("no wallpaper directory (set wallpaper.directory)")
+	}
+	index, err := config.WallpaperLibrary(cfg.Wallpaper.Directory, config.GetWallpaperIndexPath())
+	if err != nil {
+		return nil, err
+	}
+	return &config.Slideshow{Images: index.Paths(), Random: cfg.Wallpaper.Random}, nil
+}
+
+// wallpaperSetter returns the configured backend, or a fake that prints
+// what it would do for --dry-run
+func wallpaperSetter(cfg *config.ShellConfig, dryRun bool) (config.WallpaperSetter, error) {
+	if dryRun {
+		return &config.FakeSetter{Out: os.Stdout}, nil
+	}
+	return config.NewWallpaperSetter(cfg.Wallpaper.Backend, config.OSProbe{})
+}
+
+// printJSON prints v as indented JSON
+func printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func init() {
+	wallpaperIndexCmd.Flags().Bool("json", false, "Print the index as JSON without saving it")
+	wallpaperListCmd.Flags().Bool("json", false, "Print the images as JSON")
+	for _, cmd := range []*cobra.Command{wallpaperSetCmd, wallpaperNextCmd, wallpaperPrevCmd} {
+		cmd.Flags().String("monitor", "", "Only change this output (e.g. DP-1)")
+		cmd.Flags().Bool("dry-run", false, "Print what would be shown without changing anything")
+		cmd.RegisterFlagCompletionFunc("monitor", completeMonitors)
+	}
+	wallpaperDaemonCmd.Flags().Duration("interval", 0, "Time each image is shown (default: wallpaper.interval seconds)")
+	wallpaperDaemonCmd.Flags().Bool("dry-run", false, "Print the rotation without changing wallpapers or state")
+	wallpaperRenderCmd.Flags().String("size", "", "Target resolution, e.g. 2560x1440 (default: the output's monitors resolution)")
+	wallpaperRenderCmd.Flags().String("monitor", "", "Render for this output's resolution and wallpaper")
+	wallpaperRenderCmd.RegisterFlagCompletionFunc("monitor", completeMonitors)
+	wallpaperRenderCmd.Flags().String("fill", "", "Fill mode: cover, contain, fill, scale-down or none (default: wallpaper.fillMode)")
+	wallpaperRenderCmd.Flags().Int("blur", 0, "Gaussian blur strength (sigma) in pixels, 0 for none (default: wallpaper.blurStrength when wallpaper.blur is set)")
+	wallpaperRenderCmd.Flags().Float64("dim", 0, "Darken by this fraction, 0-1 (default: wallpaper.dimStrength when wallpaper.dim is set)")
+	wallpaperRenderCmd.Flags().StringP("output", "o", "", "Also write the rendering here, or the golden file with --check")
+	wallpaperRenderCmd.Flags().Bool("check", false, "Compare the rendering against -o instead of writing it")
+	wallpaperRenderCmd.RegisterFlagCompletionFunc("fill", cobra.FixedCompletions(config.FieldEnums["wallpaper.fillMode"], cobra.ShellCompDirectiveNoFileComp))
+
+	WallpaperCmd.AddCommand(wallpaperIndexCmd)
+	WallpaperCmd.AddCommand(wallpaperListCmd)
+	WallpaperCmd.AddCommand(wallpaperSetCmd)
+	WallpaperCmd.AddCommand(wallpaperNextCmd)
+	WallpaperCmd.AddCommand(wallpaperPrevCmd)
+	WallpaperCmd.AddCommand(wallpaperDaemonCmd)
+	WallpaperCmd.AddCommand(wallpaperRenderCmd)
+}
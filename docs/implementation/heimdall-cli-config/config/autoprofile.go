@@ -0,0 +1,305 @@
+package config
+
+import (
+	"fmt"
+	pathpkg "path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PowerSources lists the values accepted by a rule's power condition
+var PowerSources = []string{"ac", "battery"}
+
+// internalConnectors are DRM connector types built into laptops
+var internalConnectors = []string{"eDP", "LVDS", "DSI"}
+
+// ProfileContext is the system state profile rules are evaluated against
+type ProfileContext struct {
+	Hostname string
+	Power    string
+	Monitors []string
+	Time     time.Time
+	Env      map[string]string
+}
+
+// String renders the context on one line for logs and previews
+func (c *ProfileContext) String() string {
+	monitors := "none"
+	if len(c.Monitors) > 0 {
+		monitors = strings.Join(c.Monitors, ",")
+	}
+	return fmt.Sprintf("host=%s power=%s monitors=%s time=%s",
+		c.Hostname, c.Power, monitors, c.Time.Format("15:04"))
+}
+
+// ContextDetector reads the system state through a SystemProbe.
+// Root prefixes every sysfs and procfs path so a fake tree can stand in
+// for the real one.
+type ContextDetector struct {
+	probe SystemProbe
+	root  string
+	now   func() time.Time
+}
+
+// NewContextDetector creates a detector reading paths below root ("/" for the real system)
+func NewContextDetector(probe SystemProbe, root string) *ContextDetector {
+	if probe == nil {
+		probe = OSProbe{}
+	}
+	if root == "" {
+		root = "/"
+	}
+	return &ContextDetector{probe: probe, root: root, now: time.Now}
+}
+
+// SetClock replaces the time source
+func (d *ContextDetector) SetClock(now func() time.Time) {
+	d.now = now
+}
+
+// Detect gathers the context needed by rules. Only the environment
+// variables referenced by rules are read.
+func (d *ContextDetector) Detect(rules []ProfileRule) *ProfileContext {
+	ctx := &ProfileContext{
+		Hostname: d.hostname(),
+		Power:    d.powerSource(),
+		Monitors: d.monitors(),
+		Time:     d.now(),
+		Env:      make(map[string]string),
+	}
+
+	for _, rule := range rules {
+		for key := range rule.When.Env {
+			ctx.Env[key] = d.probe.Getenv(key)
+		}
+	}
+
+	return ctx
+}
+
+// hostname reads the kernel hostname, falling back to $HOSTNAME
+func (d *ContextDetector) hostname() string {
+	if data, err := d.probe.ReadFile(d.path("proc/sys/kernel/hostname")); err == nil {
+		if name := strings.TrimSpace(string(data)); name != "" {
+			return name
+		}
+	}
+	return d.probe.Getenv("HOSTNAME")
+}
+
+// powerSource reports "ac" when a mains supply is online or no battery
+// exists, and "battery" otherwise
+func (d *ContextDetector) powerSource() string {
+	dir := d.path("sys/class/power_supply")
+	entries, err := d.probe.ReadDir(dir)
+	if err != nil {
+		return "ac"
+	}
+
+	hasBattery := false
+	for _, entry := range entries {
+		supply := filepath.Join(dir, entry.Name())
+		switch d.readValue(filepath.Join(supply, "type")) {
+		case "Mains", "USB":
+			if d.readValue(filepath.Join(supply, "online")) == "1" {
+				return "ac"
+			}
+		case "Battery":
+			hasBattery = true
+		}
+	}
+
+	if hasBattery {
+		return "battery"
+	}
+	return "ac"
+}
+
+// monitors lists connected DRM connectors by output name (e.g. "HDMI-A-1")
+func (d *ContextDetector) monitors() []string {
+	dir := d.path("sys/class/drm")
+	entries, err := d.probe.ReadDir(dir)
+	if err != nil {
+		return []string{}
+	}
+
+	monitors := make([]string, 0)
+	for _, entry := range entries {
+		// Connectors are named card<N>-<output>
+		name := entry.Name()
+		if !strings.HasPrefix(name, "card") || !strings.Contains(name, "-") {
+			continue
+		}
+		if d.readValue(filepath.Join(dir, name, "status")) != "connected" {
+			continue
+		}
+		monitors = append(monitors, name[strings.Index(name, "-")+1:])
+	}
+
+	sort.Strings(monitors)
+	return monitors
+}
+
+// readValue reads a trimmed sysfs attribute
+func (d *ContextDetector) readValue(path string) string {
+	data, err := d.probe.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// path joins a system path onto the detector root
+func (d *ContextDetector) path(rel string) string {
+	return filepath.Join(d.root, rel)
+}
+
+// SelectProfile returns the profile chosen by the first matching rule and the
+// rule's name. The fallback is returned when no rule matches; ok is false
+// when there is neither a match nor a fallback.
+func SelectProfile(auto *AutoProfileConfig, ctx *ProfileContext) (profile, rule string, ok bool) {
+	for i, r := range auto.Rules {
+		if r.Matches(ctx) {
+			name := r.Name
+			if name == "" {
+				name = fmt.Sprintf("rule %d", i+1)
+			}
+			return r.Profile, name, true
+		}
+	}
+
+	if auto.Fallback != "" {
+		return auto.Fallback, "fallback", true
+	}
+	return "", "", false
+}
+
+// Matches reports whether every condition of the rule holds in ctx
+func (r ProfileRule) Matches(ctx *ProfileContext) bool {
+	when := r.When
+
+	if len(when.Hostname) > 0 && !matchAny(when.Hostname, ctx.Hostname) {
+		return false
+	}
+
+	if when.Power != "" && when.Power != ctx.Power {
+		return false
+	}
+
+	// Every monitor pattern must match a connected output
+	for _, pattern := range when.Monitors {
+		if !matchAnyValue(pattern, ctx.Monitors) {
+			return false
+		}
+	}
+
+	if when.ExternalMonitor != nil && *when.ExternalMonitor != hasExternalMonitor(ctx.Monitors) {
+		return false
+	}
+
+	if when.Time != "" {
+		start, end, err := ParseTimeWindow(when.Time)
+		if err != nil || !inTimeWindow(ctx.Time, start, end) {
+			return false
+		}
+	}
+
+	// "" requires the variable to be set, "!" requires it to be unset,
+	// anything else is a glob the value must match
+	for key, want := range when.Env {
+		value := ctx.Env[key]
+		switch want {
+		case "":
+			if value == "" {
+				return false
+			}
+		case "!":
+			if value != "" {
+				return false
+			}
+		default:
+			if ok, _ := pathpkg.Match(want, value); !ok {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// ParseTimeWindow parses "HH:MM-HH:MM" into minutes after midnight.
+// Windows whose end is before their start wrap past midnight.
+func ParseTimeWindow(window string) (start, end int, err error) {
+	parts := strings.Split(window, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid time window %q (use HH:MM-HH:MM)", window)
+	}
+
+	start, err = parseClock(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time window %q: %w", window, err)
+	}
+	end, err = parseClock(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time window %q: %w", window, err)
+	}
+
+	return start, end, nil
+}
+
+// parseClock parses "HH:MM" into minutes after midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// inTimeWindow reports whether t falls in [start, end)
+func inTimeWindow(t time.Time, start, end int) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if start <= end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// hasExternalMonitor reports whether any output is not a built-in panel
+func hasExternalMonitor(monitors []string) bool {
+	for _, monitor := range monitors {
+		internal := false
+		for _, prefix := range internalConnectors {
+			if strings.HasPrefix(monitor, prefix) {
+				internal = true
+				break
+			}
+		}
+		if !internal {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAny reports whether value matches any of the glob patterns
+func matchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := pathpkg.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAnyValue reports whether the glob pattern matches any of the values
+func matchAnyValue(pattern string, values []string) bool {
+	for _, value := range values {
+		if ok, _ := pathpkg.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,247 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sysfsTree writes files below a fresh root for a ContextDetector
+func sysfsTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, data := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestContextDetector(t *testing.T) {
+	const supply = "sys/class/power_supply/"
+	const drm = "sys/class/drm/"
+
+	tests := []struct {
+		name     string
+		files    map[string]string
+		power    string
+		monitors []string
+	}{
+		{"no sysfs", nil, "ac", []string{}},
+		{"mains online", map[string]string{
+			supply + "AC/type": "Mains", supply + "AC/online": "1",
+			supply + "BAT0/type": "Battery",
+		}, "ac", []string{}},
+		{"mains offline", map[string]string{
+			supply + "AC/type": "Mains", supply + "AC/online": "0",
+			supply + "BAT0/type": "Battery",
+		}, "battery", []string{}},
+		{"usb-c charger", map[string]string{
+			supply + "ucsi-source-psy-USBC000:001/type": "USB", supply + "ucsi-source-psy-USBC000:001/online": "1",
+			supply + "BAT0/type": "Battery",
+		}, "ac", []string{}},
+		{"desktop without battery", map[string]string{
+			supply + "hidpp_battery_0/type": "Mouse",
+		}, "ac", []string{}},
+		{"connectors", map[string]string{
+			drm + "card0-eDP-1/status":    "connected",
+			drm + "card0-HDMI-A-1/status": "disconnected",
+			drm + "card1-DP-2/status":     "connected",
+			drm + "card0/dev":             "226:0",
+			drm + "renderD128/dev":        "226:128",
+		}, "ac", []string{"DP-2", "eDP-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detector := NewContextDetector(OSProbe{}, sysfsTree(t, tt.files))
+			ctx := detector.Detect(nil)
+			if ctx.Power != tt.power {
+				t.Errorf("power = %q, want %q", ctx.Power, tt.power)
+			}
+			if !reflect.DeepEqual(ctx.Monitors, tt.monitors) {
+				t.Errorf("monitors = %v, want %v", ctx.Monitors, tt.monitors)
+			}
+		})
+	}
+}
+
+func TestDetectHostnameAndEnv(t *testing.T) {
+	t.Setenv("HEIMDALL_TEST_DOCKED", "yes")
+	t.Setenv("HEIMDALL_TEST_UNUSED", "yes")
+	root := sysfsTree(t, map[string]string{"proc/sys/kernel/hostname": "workstation"})
+	now := time.Date(2026, 3, 1, 23, 30, 0, 0, time.UTC)
+
+	detector := NewContextDetector(OSProbe{}, root)
+	detector.SetClock(func() time.Time { return now })
+	ctx := detector.Detect([]ProfileRule{{When: RuleConditions{Env: map[string]string{"HEIMDALL_TEST_DOCKED": ""}}}})
+
+	if ctx.Hostname != "workstation" {
+		t.Errorf("hostname = %q, want workstation", ctx.Hostname)
+	}
+	if !ctx.Time.Equal(now) {
+		t.Errorf("time = %v, want %v", ctx.Time, now)
+	}
+	// Only variables named by rules are read
+	if want := map[string]string{"HEIMDALL_TEST_DOCKED": "yes"}; !reflect.DeepEqual(ctx.Env, want) {
+		t.Errorf("env = %v, want %v", ctx.Env, want)
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	at := func(clock string) time.Time {
+		parsed, err := time.Parse("15:04", clock)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return time.Date(2026, 3, 1, parsed.Hour(), parsed.Minute(), 0, 0, time.Local)
+	}
+	yes, no := true, false
+	laptop := ProfileContext{
+		Hostname: "thinkpad",
+		Power:    "battery",
+		Monitors: []string{"eDP-1"},
+		Time:     at("12:00"),
+		Env:      map[string]string{"SSH_CONNECTION": "", "XDG_SESSION_DESKTOP": "Hyprland"},
+	}
+	docked := laptop
+	docked.Power = "ac"
+	docked.Monitors = []string{"DP-1", "DP-2", "eDP-1"}
+	night := laptop
+	night.Time = at("23:30")
+	early := laptop
+	early.Time = at("05:59")
+	morning := laptop
+	morning.Time = at("06:00")
+
+	tests := []struct {
+		name string
+		when RuleConditions
+		ctx  ProfileContext
+		want bool
+	}{
+		{"no conditions", RuleConditions{}, laptop, true},
+		{"power matches", RuleConditions{Power: "battery"}, laptop, true},
+		{"power differs", RuleConditions{Power: "ac"}, laptop, false},
+		{"hostname glob", RuleConditions{Hostname: []string{"desk*", "think*"}}, laptop, true},
+		{"hostname differs", RuleConditions{Hostname: []string{"desk*"}}, laptop, false},
+		{"two monitors required", RuleConditions{Monitors: []string{"DP-*", "eDP-*"}}, docked, true},
+		{"second monitor missing", RuleConditions{Monitors: []string{"DP-*", "eDP-*"}}, laptop, false},
+		{"external monitor", RuleConditions{ExternalMonitor: &yes}, docked, true},
+		{"panel is not external", RuleConditions{ExternalMonitor: &yes}, laptop, false},
+		{"no external monitor", RuleConditions{ExternalMonitor: &no}, laptop, true},
+		{"inside daytime window", RuleConditions{Time: "09:00-17:00"}, laptop, true},
+		{"outside daytime window", RuleConditions{Time: "09:00-17:00"}, night, false},
+		{"midnight window before midnight", RuleConditions{Time: "22:00-06:00"}, night, true},
+		{"midnight window after midnight", RuleConditions{Time: "22:00-06:00"}, early, true},
+		{"midnight window end is exclusive", RuleConditions{Time: "22:00-06:00"}, morning, false},
+		{"midnight window at noon", RuleConditions{Time: "22:00-06:00"}, laptop, false},
+		{"invalid window", RuleConditions{Time: "late"}, laptop, false},
+		{"env set", RuleConditions{Env: map[string]string{"XDG_SESSION_DESKTOP": ""}}, laptop, true},
+		{"env required but empty", RuleConditions{Env: map[string]string{"SSH_CONNECTION": ""}}, laptop, false},
+		{"env unset", RuleConditions{Env: map[string]string{"SSH_CONNECTION": "!"}}, laptop, true},
+		{"env glob", RuleConditions{Env: map[string]string{"XDG_SESSION_DESKTOP": "Hypr*"}}, laptop, true},
+		{"env glob differs", RuleConditions{Env: map[string]string{"XDG_SESSION_DESKTOP": "sway"}}, laptop, false},
+		{"all conditions", RuleConditions{Power: "ac", ExternalMonitor: &yes, Hostname: []string{"thinkpad"}}, docked, true},
+		{"one condition fails", RuleConditions{Power: "ac", ExternalMonitor: &yes}, laptop, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if got := (ProfileRule{When: tt.when}).Matches(&ctx); got != tt.want {
+				t.Errorf("Matches(%s) = %v, want %v", ctx.String(), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectProfile(t *testing.T) {
+	rules := []ProfileRule{
+		{Name: "docked", Profile: "gaming", When: RuleConditions{Power: "ac", Monitors: []string{"DP-*"}}},
+		{Profile: "productivity", When: RuleConditions{Power: "ac"}},
+		{Name: "mobile", Profile: "minimal", When: RuleConditions{Power: "battery"}},
+		{Name: "shadowed", Profile: "development", When: RuleConditions{Power: "battery"}},
+	}
+
+	tests := []struct {
+		name          string
+		ctx           ProfileContext
+		fallback      string
+		profile, rule string
+		ok            bool
+	}{
+		{"first match wins", ProfileContext{Power: "ac", Monitors: []string{"DP-1"}}, "", "gaming", "docked", true},
+		{"unnamed rule", ProfileContext{Power: "ac"}, "", "productivity", "rule 2", true},
+		{"earlier rule shadows later", ProfileContext{Power: "battery"}, "", "minimal", "mobile", true},
+		{"fallback", ProfileContext{Power: "unknown"}, "default", "default", "fallback", true},
+		{"no match", ProfileContext{Power: "unknown"}, "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auto := &AutoProfileConfig{Rules: rules, Fallback: tt.fallback}
+			profile, rule, ok := SelectProfile(auto, &tt.ctx)
+			if profile != tt.profile || rule != tt.rule || ok != tt.ok {
+				t.Errorf("SelectProfile = %q, %q, %v, want %q, %q, %v", profile, rule, ok, tt.profile, tt.rule, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSelectProfileFromSysfs(t *testing.T) {
+	root := sysfsTree(t, map[string]string{
+		"sys/class/power_supply/AC/type":   "Mains",
+		"sys/class/power_supply/AC/online": "0",
+		"sys/class/power_supply/BAT0/type": "Battery",
+		"sys/class/drm/card0-eDP-1/status": "connected",
+	})
+	auto := &AutoProfileConfig{
+		Fallback: "default",
+		Rules: []ProfileRule{
+			{Name: "docked", Profile: "gaming", When: RuleConditions{Power: "ac"}},
+			{Name: "mobile", Profile: "minimal", When: RuleConditions{Power: "battery", Monitors: []string{"eDP-*"}}},
+		},
+	}
+
+	ctx := NewContextDetector(OSProbe{}, root).Detect(auto.Rules)
+	if profile, rule, _ := SelectProfile(auto, ctx); profile != "minimal" || rule != "mobile" {
+		t.Errorf("selected %q by %q on %s, want minimal by mobile", profile, rule, ctx)
+	}
+}
+
+func TestValidateProfileNames(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Profiles.Auto = AutoProfileConfig{
+		Fallback: "gone",
+		Rules:    []ProfileRule{{Profile: "work"}, {Profile: "minimal"}},
+	}
+
+	unknown := func(v *SchemaValidator) []string {
+		paths := make([]string, 0)
+		for _, verr := range v.Validate(config) {
+			if strings.HasPrefix(verr.Message, "Unknown profile") {
+				paths = append(paths, verr.Path)
+			}
+		}
+		return paths
+	}
+
+	// Names are only checked against a list the caller provides
+	if got := unknown(NewSchemaValidator()); len(got) != 0 {
+		t.Errorf("unknown profiles without a name list: %v", got)
+	}
+	validator := NewSchemaValidator()
+	validator.SetKnownProfiles(append([]string{"work"}, BuiltinProfiles...))
+	if got, want := unknown(validator), []string{"profiles.auto.fallback"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unknown profiles = %v, want %v", got, want)
+	}
+}
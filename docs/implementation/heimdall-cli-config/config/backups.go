@@ -0,0 +1,98 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BackupInfo describes a configuration backup file
+type BackupInfo struct {
+	ID      string
+	Path    string
+	ModTime time.Time
+	Size    int64
+}
+
+// ListBackups returns the available backups, newest first
+func (cm *ConfigManager) ListBackups() ([]BackupInfo, error) {
+	entries, err := os.ReadDir(cm.backupDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []BackupInfo{}, nil
+		}
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	backups := make([]BackupInfo, 0)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") || name == "migration-history.json" {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, BackupInfo{
+			ID:      strings.TrimSuffix(name, ".json"),
+			Path:    filepath.Join(cm.backupDir, name),
+			ModTime: info.ModTime(),
+			Size:    info.Size(),
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].ModTime.After(backups[j].ModTime)
+	})
+
+	return backups, nil
+}
+
+// RestoreBackup replaces the current configuration with a backup
+func (cm *ConfigManager) RestoreBackup(id string) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	// Reject anything that could escape the backup directory
+	id = strings.TrimSuffix(id, ".json")
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.Contains(id, "..") {
+		return fmt.Errorf("invalid backup id: %s", id)
+	}
+
+	backupPath := filepath.Join(cm.backupDir, id+".json")
+	data, err := os.ReadFile(backupPath)
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	config := &ShellConfig{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return fmt.Errorf("failed to parse backup: %w", err)
+	}
+
+	// Keep the current state recoverable
+	if err := cm.createBackup(); err != nil {
+		cm.logger.Warn("Failed to create pre-restore backup",
+			Field{"error", err.Error()})
+	}
+
+	// Backups hold the base layer as written, so restore it verbatim
+	if err := writeFileAtomic(cm.configPath, data); err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	// Invalidate cache
+	cm.cache.config = nil
+
+	cm.logger.Info("Configuration restored from backup",
+		Field{"backup", backupPath})
+
+	return nil
+}
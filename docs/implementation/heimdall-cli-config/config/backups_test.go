@@ -0,0 +1,107 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"heimdall-cli/config"
+	"heimdall-cli/logging"
+)
+
+// newTestManager creates a manager whose config and backups live in a
+// temporary directory
+func newTestManager(t *testing.T) (*config.ConfigManager, string) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HEIMDALL_CONFIG_PATH", filepath.Join(dir, "shell.json"))
+	t.Setenv("HEIMDALL_BACKUP_DIR", filepath.Join(dir, "backups"))
+	manager, err := config.NewConfigManager(logging.NewRecorder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return manager, filepath.Join(dir, "backups")
+}
+
+func TestListBackups(t *testing.T) {
+	manager, dir := newTestManager(t)
+
+	backups, err := manager.ListBackups()
+	if err != nil || len(backups) != 0 {
+		t.Fatalf("empty directory: %v, %v", backups, err)
+	}
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	files := map[string]time.Duration{
+		"shell-20240501-120000.json": 0,
+		"shell-20240503-120000.json": 48 * time.Hour,
+		"shell-20240502-120000.json": 24 * time.Hour,
+		"migration-history.json":     72 * time.Hour,
+		"notes.txt":                  72 * time.Hour,
+	}
+	for name, age := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, base.Add(age), base.Add(age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "old.json"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, err = manager.ListBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]string, len(backups))
+	for i, b := range backups {
+		ids[i] = b.ID
+	}
+	want := "shell-20240503-120000 shell-20240502-120000 shell-20240501-120000"
+	if strings.Join(ids, " ") != want {
+		t.Errorf("backups = %v, want newest first: %s", ids, want)
+	}
+}
+
+func TestRestoreBackup(t *testing.T) {
+	manager, dir := newTestManager(t)
+
+	cfg := config.GetDefaultConfig()
+	cfg.Bar.Height = 48
+	if err := manager.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := os.ReadFile(config.GetConfigPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "known.json"), saved, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.Bar.Height = 20
+	if err := manager.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.RestoreBackup("known"); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := manager.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.Bar.Height != 48 {
+		t.Errorf("bar.height = %d after restoring, want 48", restored.Bar.Height)
+	}
+
+	for _, id := range []string{"", "../shell", "a/b", "missing"} {
+		if err := manager.RestoreBackup(id); err == nil {
+			t.Errorf("RestoreBackup(%q) succeeded", id)
+		}
+	}
+}
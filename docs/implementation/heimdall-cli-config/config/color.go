@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+	"math"
+)
+
+// OKLCH is a color in the OKLCH space: perceptual lightness (0-1), chroma
+// (0 to about 0.37 inside sRGB) and hue in degrees
+type OKLCH struct {
+	L, C, H float64
+}
+
+// ToOKLCH converts 8-bit sRGB channels to OKLCH
+func ToOKLCH(r, g, b uint8) OKLCH {
+	lr, lg, lb := srgbToLinear(r), srgbToLinear(g), srgbToLinear(b)
+
+	l := math.Cbrt(0.4122214708*lr + 0.5363325363*lg + 0.0514459929*lb)
+	m := math.Cbrt(0.2119034982*lr + 0.6806995451*lg + 0.1073969566*lb)
+	s := math.Cbrt(0.0883024619*lr + 0.2817188376*lg + 0.6299787005*lb)
+
+	L := 0.2104542553*l + 0.7936177850*m - 0.0040720468*s
+	A := 1.9779984951*l - 2.4285922050*m + 0.4505937099*s
+	B := 0.0259040371*l + 0.7827717662*m - 0.8086757660*s
+
+	h := math.Atan2(B, A) * 180 / math.Pi
+	if h < 0 {
+		h += 360
+	}
+	return OKLCH{L: L, C: math.Hypot(A, B), H: h}
+}
+
+// HexToOKLCH parses a color, usually #RRGGBB, into OKLCH
+func HexToOKLCH(color string) (OKLCH, error) {
+	c, err := ParseColor(color)
+	if err != nil {
+		return OKLCH{}, err
+	}
+	return ToOKLCH(c[0], c[1], c[2]), nil
+}
+
+// Hex renders the color as #rrggbb. Colors outside sRGB keep their
+// lightness and hue and lose chroma until they fit.
+func (c OKLCH) Hex() string {
+	c.L = clamp01(c.L)
+	if c.C < 0 {
+		c.C = 0
+	}
+
+	r, g, b, ok := c.linearRGB()
+	if !ok {
+		lo, hi := 0.0, c.C
+		for i := 0; i < 24; i++ {
+			c.C = (lo + hi) / 2
+			if _, _, _, fits := c.linearRGB(); fits {
+				lo = c.C
+			} else {
+				hi = c.C
+			}
+		}
+		c.C = lo
+		r, g, b, _ = c.linearRGB()
+	}
+
+	return fmt.Sprintf("#%02x%02x%02x", linearToSRGB(r), linearToSRGB(g), linearToSRGB(b))
+}
+
+// linearRGB converts to linear sRGB and reports whether the color is in gamut
+func (c OKLCH) linearRGB() (r, g, b float64, ok bool) {
+	h := c.H * math.Pi / 180
+	A, B := c.C*math.Cos(h), c.C*math.Sin(h)
+
+	l := c.L + 0.3963377774*A + 0.2158037573*B
+	m := c.L - 0.1055613458*A - 0.0638541728*B
+	s := c.L - 0.0894841775*A - 1.2914855480*B
+	l, m, s = l*l*l, m*m*m, s*s*s
+
+	r = 4.0767416621*l - 3.3077115913*m + 0.2309699292*s
+	g = -1.2684380046*l + 2.6097574011*m - 0.3413193965*s
+	b = -0.0041960863*l - 0.7034186147*m + 1.7076147010*s
+
+	const eps = 1e-6
+	ok = r >= -eps && r <= 1+eps && g >= -eps && g <= 1+eps && b >= -eps && b <= 1+eps
+	return r, g, b, ok
+}
+
+// hueDistance returns the angle between two hues in degrees (0-180)
+func hueDistance(a, b float64) float64 {
+	d := math.Mod(math.Abs(a-b), 360)
+	if d > 180 {
+		d = 360 - d
+	}
+	return d
+}
+
+// srgbToLinear removes the sRGB transfer curve from an 8-bit channel
+func srgbToLinear(v uint8) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB applies the sRGB transfer curve and rounds to 8 bits
+func linearToSRGB(v float64) uint8 {
+	v = clamp01(v)
+	if v <= 0.0031308 {
+		v *= 12.92
+	} else {
+		v = 1.055*math.Pow(v, 1/2.4) - 0.055
+	}
+	return uint8(math.Round(clamp01(v) * 255))
+}
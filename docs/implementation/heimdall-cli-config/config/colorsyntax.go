@@ -0,0 +1,599 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ColorRefPrefix starts a reference to another palette role: "@primary",
+// "@background/80%"
+const ColorRefPrefix = "@"
+
+// ColorPaths lists the config paths that hold colors. They accept every
+// syntax ParseColor does plus references, and are resolved to hex for
+// rendering and exporting.
+var ColorPaths = []string{
+	"appearance.accentColor",
+	"appearance.colors.background",
+	"appearance.colors.foreground",
+	"appearance.colors.primary",
+	"appearance.colors.secondary",
+	"appearance.colors.success",
+	"appearance.colors.warning",
+	"appearance.colors.error",
+	"appearance.colors.info",
+	"appearance.colors.surface",
+	"appearance.colors.border",
+	"bar.background",
+	"bar.foreground",
+}
+
+// colorRefPaths maps reference names to the paths they read; "accent" is
+// appearance.accentColor, the rest are the appearance.colors roles
+var colorRefPaths = map[string]string{
+	"accent":     "appearance.accentColor",
+	"background": "appearance.colors.background",
+	"foreground": "appearance.colors.foreground",
+	"primary":    "appearance.colors.primary",
+	"secondary":  "appearance.colors.secondary",
+	"success":    "appearance.colors.success",
+	"warning":    "appearance.colors.warning",
+	"error":      "appearance.colors.error",
+	"info":       "appearance.colors.info",
+	"surface":    "appearance.colors.surface",
+	"border":     "appearance.colors.border",
+}
+
+var colorFuncPattern = regexp.MustCompile(`^(rgba?|hsla?|oklch)\((.*)\)$`)
+
+// ColorSyntaxHelp describes the accepted color syntaxes for error fixes
+const ColorSyntaxHelp = "Use #RGB, #RRGGBB, #RRGGBBAA, rgb(), rgba(), hsl(), oklch(), a CSS color name or a reference like @primary or @background/80%"
+
+// ParseColor decodes a literal color: #RGB, #RGBA, #RRGGBB, #RRGGBBAA,
+// rgb()/rgba(), hsl()/hsla(), oklch() or a CSS named color. References are
+// rejected; ResolveColors handles those.
+func ParseColor(value string) ([4]uint8, error) {
+	v := strings.ToLower(strings.TrimSpace(value))
+
+	if strings.HasPrefix(v, ColorRefPrefix) {
+		return [4]uint8{}, fmt.Errorf("color reference %s needs a palette to resolve against", value)
+	}
+
+	if strings.HasPrefix(v, "#") {
+		hex := v[1:]
+		if !isHexDigits(hex) {
+			return [4]uint8{}, fmt.Errorf("invalid hex color: %q", value)
+		}
+		switch len(hex) {
+		case 3, 4:
+			long := make([]byte, 0, 8)
+			for i := 0; i < len(hex); i++ {
+				long = append(long, hex[i], hex[i])
+			}
+			hex = string(long)
+		case 6, 8:
+		default:
+			return [4]uint8{}, fmt.Errorf("invalid hex color: %q", value)
+		}
+		if len(hex) == 6 {
+			hex += "ff"
+		}
+		n, _ := strconv.ParseUint(hex, 16, 32)
+		return [4]uint8{uint8(n >> 24), uint8(n >> 16), uint8(n >> 8), uint8(n)}, nil
+	}
+
+	if m := colorFuncPattern.FindStringSubmatch(v); m != nil {
+		args, alpha, err := splitColorArgs(m[2])
+		if err != nil {
+			return [4]uint8{}, fmt.Errorf("invalid color %q: %w", value, err)
+		}
+		c, err := colorFromFunc(m[1], args)
+		if err != nil {
+			return [4]uint8{}, fmt.Errorf("invalid color %q: %w", value, err)
+		}
+		if alpha != "" {
+			a, err := parseAlpha(alpha)
+			if err != nil {
+				return [4]uint8{}, fmt.Errorf("invalid color %q: %w", value, err)
+			}
+			c[3] = a
+		}
+		return c, nil
+	}
+
+	if hex, ok := cssNamedColors[v]; ok {
+		return ParseColor(hex)
+	}
+
+	return [4]uint8{}, fmt.Errorf("invalid color: %q", value)
+}
+
+// isHexDigits reports whether s is non-empty and only hex digits
+func isHexDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeColor converts a literal color to canonical hex
+func NormalizeColor(value string) (string, error) {
+	c, err := ParseColor(value)
+	if err != nil {
+		return "", err
+	}
+	return formatHexColor(c), nil
+}
+
+// CheckColorSyntax reports whether value is a valid literal color or a
+// well-formed reference, without resolving the reference
+func CheckColorSyntax(value string) error {
+	v := strings.TrimSpace(value)
+	if !strings.HasPrefix(v, ColorRefPrefix) {
+		_, err := ParseColor(v)
+		return err
+	}
+
+	_, _, _, err := parseColorRef(v)
+	return err
+}
+
+// ResolveColors rewrites every ColorPaths value to canonical hex, following
+// references. Empty values stay empty. Callers that need the original
+// syntax, like anything that saves shell.json, resolve a clone.
+func ResolveColors(config *ShellConfig) error {
+	values, errs := resolveColorPaths(config)
+	for _, path := range ColorPaths {
+		if err, ok := errs[path]; ok {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	configMap, err := structToMap(config)
+	if err != nil {
+		return fmt.Errorf("failed to convert config to map: %w", err)
+	}
+	for path, value := range values {
+		SetMapPath(configMap, path, value)
+	}
+	if err := mapToStruct(configMap, config); err != nil {
+		return fmt.Errorf("failed to apply resolved colors: %w", err)
+	}
+	return nil
+}
+
+// resolveColorPaths resolves every non-empty color path, returning the hex
+// values and, separately, the error of each path that failed
+func resolveColorPaths(config *ShellConfig) (map[string]string, map[string]error) {
+	raw := make(map[string]string, len(ColorPaths))
+	if configMap, err := structToMap(config); err == nil {
+		for _, path := range ColorPaths {
+			value, _ := LookupPath(configMap, path)
+			if s, _ := value.(string); s != "" {
+				raw[path] = s
+			}
+		}
+	}
+
+	r := &colorResolver{
+		raw:      raw,
+		resolved: make(map[string][4]uint8),
+	}
+	values := make(map[string]string)
+	errs := make(map[string]error)
+	for _, path := range ColorPaths {
+		if _, ok := raw[path]; !ok {
+			continue
+		}
+		c, err := r.resolvePath(path)
+		if err != nil {
+			errs[path] = err
+			continue
+		}
+		values[path] = formatHexColor(c)
+	}
+	return values, errs
+}
+
+// colorResolver follows references between color paths
+type colorResolver struct {
+	raw      map[string]string
+	resolved map[string][4]uint8
+	stack    []string
+}
+
+// resolvePath resolves the color at path, detecting reference cycles
+func (r *colorResolver) resolvePath(path string) ([4]uint8, error) {
+	if c, ok := r.resolved[path]; ok {
+		return c, nil
+	}
+
+	for i, p := range r.stack {
+		if p == path {
+			cycle := append(append([]string{}, r.stack[i:]...), path)
+			for j := range cycle {
+				cycle[j] = ColorRefPrefix + colorRefName(cycle[j])
+			}
+			return [4]uint8{}, fmt.Errorf("circular color reference: %s", strings.Join(cycle, " → "))
+		}
+	}
+
+	value, ok := r.raw[path]
+	if !ok {
+		return [4]uint8{}, fmt.Errorf("%s%s is not set", ColorRefPrefix, colorRefName(path))
+	}
+
+	r.stack = append(r.stack, path)
+	c, err := r.resolveValue(value)
+	r.stack = r.stack[:len(r.stack)-1]
+
+	if err != nil {
+		return [4]uint8{}, err
+	}
+	r.resolved[path] = c
+	return c, nil
+}
+
+// resolveValue resolves a literal color or a "@role[/alpha]" reference
+func (r *colorResolver) resolveValue(value string) ([4]uint8, error) {
+	v := strings.TrimSpace(value)
+	if !strings.HasPrefix(v, ColorRefPrefix) {
+		return ParseColor(v)
+	}
+
+	path, alpha, hasAlpha, err := parseColorRef(v)
+	if err != nil {
+		return [4]uint8{}, err
+	}
+
+	c, err := r.resolvePath(path)
+	if err != nil {
+		return [4]uint8{}, err
+	}
+	if hasAlpha {
+		c[3] = uint8(math.Round(float64(c[3]) * float64(alpha) / 255))
+	}
+	return c, nil
+}
+
+// parseColorRef splits "@role/80%" into the role's path and the alpha
+func parseColorRef(value string) (path string, alpha uint8, hasAlpha bool, err error) {
+	name, rawAlpha, hasAlpha := strings.Cut(strings.TrimPrefix(value, ColorRefPrefix), "/")
+	name = strings.ToLower(strings.TrimSpace(name))
+	path, ok := colorRefPaths[name]
+	if !ok {
+		return "", 0, false, fmt.Errorf("unknown color reference %s%s (use %s)", ColorRefPrefix, name, strings.Join(colorRefNames(), ", "))
+	}
+	if hasAlpha {
+		if alpha, err = parseAlpha(rawAlpha); err != nil {
+			return "", 0, false, fmt.Errorf("invalid alpha in %s: %w", value, err)
+		}
+	}
+	return path, alpha, hasAlpha, nil
+}
+
+// colorRefNamesByPath is colorRefPaths reversed. Were two names to share
+// a path, the first in sorted order wins, so messages stay stable.
+var colorRefNamesByPath = func() map[string]string {
+	names := make([]string, 0, len(colorRefPaths))
+	for name := range colorRefPaths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	byPath := make(map[string]string, len(names))
+	for _, name := range names {
+		if _, ok := byPath[colorRefPaths[name]]; !ok {
+			byPath[colorRefPaths[name]] = name
+		}
+	}
+	return byPath
+}()
+
+// colorRefName returns the reference name of a color path
+func colorRefName(path string) string {
+	if name, ok := colorRefNamesByPath[path]; ok {
+		return name
+	}
+	return path
+}
+
+// colorRefNames lists the reference names with the @ prefix
+func colorRefNames() []string {
+	names := make([]string, 0, len(colorRefPaths))
+	for name := range colorRefPaths {
+		names = append(names, ColorRefPrefix+name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// splitColorArgs splits function arguments in either the comma form
+// "1, 2, 3, 0.5" or the space form "1 2 3 / 0.5"
+func splitColorArgs(s string) (args []string, alpha string, err error) {
+	if strings.Contains(s, ",") {
+		for _, part := range strings.Split(s, ",") {
+			args = append(args, strings.TrimSpace(part))
+		}
+		if len(args) == 4 {
+			args, alpha = args[:3], args[3]
+		}
+	} else {
+		main, a, hasAlpha := strings.Cut(s, "/")
+		args = strings.Fields(main)
+		if hasAlpha {
+			alpha = strings.TrimSpace(a)
+		}
+	}
+	if len(args) != 3 {
+		return nil, "", fmt.Errorf("expected 3 components and an optional alpha")
+	}
+	return args, alpha, nil
+}
+
+// colorFromFunc converts rgb(), hsl() or oklch() components
+func colorFromFunc(name string, args []string) ([4]uint8, error) {
+	switch name {
+	case "rgb", "rgba":
+		var c [4]uint8
+		for i, arg := range args {
+			v, err := parseNumber(arg, 255)
+			if err != nil {
+				return c, err
+			}
+			c[i] = uint8(math.Round(math.Max(0, math.Min(255, v))))
+		}
+		c[3] = 255
+		return c, nil
+
+	case "hsl", "hsla":
+		h, err := parseHue(args[0])
+		if err != nil {
+			return [4]uint8{}, err
+		}
+		s, err := parseNumber(args[1], 100)
+		if err != nil {
+			return [4]uint8{}, err
+		}
+		l, err := parseNumber(args[2], 100)
+		if err != nil {
+			return [4]uint8{}, err
+		}
+		r, g, b := hslToRGB(h, clamp01(s/100), clamp01(l/100))
+		return [4]uint8{r, g, b, 255}, nil
+
+	default: // oklch
+		l, err := parseNumber(args[0], 1)
+		if err != nil {
+			return [4]uint8{}, err
+		}
+		c, err := parseNumber(args[1], 0.4)
+		if err != nil {
+			return [4]uint8{}, err
+		}
+		h, err := parseHue(args[2])
+		if err != nil {
+			return [4]uint8{}, err
+		}
+		return ParseColor(OKLCH{L: l, C: c, H: h}.Hex())
+	}
+}
+
+// parseNumber parses a number, or a percentage of full
+func parseNumber(s string, full float64) (float64, error) {
+	if strings.HasSuffix(s, "%") {
+		v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid percentage %q", s)
+		}
+		return v / 100 * full, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return v, nil
+}
+
+// parseHue parses a hue in degrees, with or without "deg"
+func parseHue(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "deg"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hue %q", s)
+	}
+	v = math.Mod(v, 360)
+	if v < 0 {
+		v += 360
+	}
+	return v, nil
+}
+
+// parseAlpha parses an alpha of 0-1 or 0%-100% into 0-255
+func parseAlpha(s string) (uint8, error) {
+	v, err := parseNumber(strings.TrimSpace(s), 1)
+	if err != nil {
+		return 0, err
+	}
+	return uint8(math.Round(clamp01(v) * 255)), nil
+}
+
+// hslToRGB converts hue (degrees), saturation and lightness (0-1) to sRGB
+func hslToRGB(h, s, l float64) (uint8, uint8, uint8) {
+	f := func(n float64) uint8 {
+		k := math.Mod(n+h/30, 12)
+		a := s * math.Min(l, 1-l)
+		v := l - a*math.Max(-1, math.Min(k-3, math.Min(9-k, 1)))
+		return uint8(math.Round(clamp01(v) * 255))
+	}
+	return f(0), f(8), f(4)
+}
+
+// formatHexColor renders #rrggbb, or #rrggbbaa when not opaque
+func formatHexColor(c [4]uint8) string {
+	if c[3] == 255 {
+		return fmt.Sprintf("#%02x%02x%02x", c[0], c[1], c[2])
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c[0], c[1], c[2], c[3])
+}
+
+// cssNamedColors are the CSS Color Module Level 4 named colors
+var cssNamedColors = map[string]string{
+	"aliceblue":            "#f0f8ff",
+	"antiquewhite":         "#faebd7",
+	"aqua":                 "#00ffff",
+	"aquamarine":           "#7fffd4",
+	"azure":                "#f0ffff",
+	"beige":                "#f5f5dc",
+	"bisque":               "#ffe4c4",
+	"black":                "#000000",
+	"blanchedalmond":       "#ffebcd",
+	"blue":                 "#0000ff",
+	"blueviolet":           "#8a2be2",
+	"brown":                "#a52a2a",
+	"burlywood":            "#deb887",
+	"cadetblue":            "#5f9ea0",
+	"chartreuse":           "#7fff00",
+	"chocolate":            "#d2691e",
+	"coral":                "#ff7f50",
+	"cornflowerblue":       "#6495ed",
+	"cornsilk":             "#fff8dc",
+	"crimson":              "#dc143c",
+	"cyan":                 "#00ffff",
+	"darkblue":             "#00008b",
+	"darkcyan":             "#008b8b",
+	"darkgoldenrod":        "#b8860b",
+	"darkgray":             "#a9a9a9",
+	"darkgreen":            "#006400",
+	"darkgrey":             "#a9a9a9",
+	"darkkhaki":            "#bdb76b",
+	"darkmagenta":          "#8b008b",
+	"darkolivegreen":       "#556b2f",
+	"darkorange":           "#ff8c00",
+	"darkorchid":           "#9932cc",
+	"darkred":              "#8b0000",
+	"darksalmon":           "#e9967a",
+	"darkseagreen":         "#8fbc8f",
+	"darkslateblue":        "#483d8b",
+	"darkslategray":        "#2f4f4f",
+	"darkslategrey":        "#2f4f4f",
+	"darkturquoise":        "#00ced1",
+	"darkviolet":           "#9400d3",
+	"deeppink":             "#ff1493",
+	"deepskyblue":          "#00bfff",
+	"dimgray":              "#696969",
+	"dimgrey":              "#696969",
+	"dodgerblue":           "#1e90ff",
+	"firebrick":            "#b22222",
+	"floralwhite":          "#fffaf0",
+	"forestgreen":          "#228b22",
+	"fuchsia":              "#ff00ff",
+	"gainsboro":            "#dcdcdc",
+	"ghostwhite":           "#f8f8ff",
+	"gold":                 "#ffd700",
+	"goldenrod":            "#daa520",
+	"gray":                 "#808080",
+	"green":                "#008000",
+	"greenyellow":          "#adff2f",
+	"grey":                 "#808080",
+	"honeydew":             "#f0fff0",
+	"hotpink":              "#ff69b4",
+	"indianred":            "#cd5c5c",
+	"indigo":               "#4b0082",
+	"ivory":                "#fffff0",
+	"khaki":                "#f0e68c",
+	"lavender":             "#e6e6fa",
+	"lavenderblush":        "#fff0f5",
+	"lawngreen":            "#7cfc00",
+	"lemonchiffon":         "#fffacd",
+	"lightblue":            "#add8e6",
+	"lightcoral":           "#f08080",
+	"lightcyan":            "#e0ffff",
+	"lightgoldenrodyellow": "#fafad2",
+	"lightgray":            "#d3d3d3",
+	"lightgreen":           "#90ee90",
+	"lightgrey":            "#d3d3d3",
+	"lightpink":            "#ffb6c1",
+	"lightsalmon":          "#ffa07a",
+	"lightseagreen":        "#20b2aa",
+	"lightskyblue":         "#87cefa",
+	"lightslategray":       "#778899",
+	"lightslategrey":       "#778899",
+	"lightsteelblue":       "#b0c4de",
+	"lightyellow":          "#ffffe0",
+	"lime":                 "#00ff00",
+	"limegreen":            "#32cd32",
+	"linen":                "#faf0e6",
+	"magenta":              "#ff00ff",
+	"maroon":               "#800000",
+	"mediumaquamarine":     "#66cdaa",
+	"mediumblue":           "#0000cd",
+	"mediumorchid":         "#ba55d3",
+	"mediumpurple":         "#9370db",
+	"mediumseagreen":       "#3cb371",
+	"mediumslateblue":      "#7b68ee",
+	"mediumspringgreen":    "#00fa9a",
+	"mediumturquoise":      "#48d1cc",
+	"mediumvioletred":      "#c71585",
+	"midnightblue":         "#191970",
+	"mintcream":            "#f5fffa",
+	"mistyrose":            "#ffe4e1",
+	"moccasin":             "#ffe4b5",
+	"navajowhite":          "#ffdead",
+	"navy":                 "#000080",
+	"oldlace":              "#fdf5e6",
+	"olive":                "#808000",
+	"olivedrab":            "#6b8e23",
+	"orange":               "#ffa500",
+	"orangered":            "#ff4500",
+	"orchid":               "#da70d6",
+	"palegoldenrod":        "#eee8aa",
+	"palegreen":            "#98fb98",
+	"paleturquoise":        "#afeeee",
+	"palevioletred":        "#db7093",
+	"papayawhip":           "#ffefd5",
+	"peachpuff":            "#ffdab9",
+	"peru":                 "#cd853f",
+	"pink":                 "#ffc0cb",
+	"plum":                 "#dda0dd",
+	"powderblue":           "#b0e0e6",
+	"purple":               "#800080",
+	"rebeccapurple":        "#663399",
+	"red":                  "#ff0000",
+	"rosybrown":            "#bc8f8f",
+	"royalblue":            "#4169e1",
+	"saddlebrown":          "#8b4513",
+	"salmon":               "#fa8072",
+	"sandybrown":           "#f4a460",
+	"seagreen":             "#2e8b57",
+	"seashell":             "#fff5ee",
+	"sienna":               "#a0522d",
+	"silver":               "#c0c0c0",
+	"skyblue":              "#87ceeb",
+	"slateblue":            "#6a5acd",
+	"slategray":            "#708090",
+	"slategrey":            "#708090",
+	"snow":                 "#fffafa",
+	"springgreen":          "#00ff7f",
+	"steelblue":            "#4682b4",
+	"tan":                  "#d2b48c",
+	"teal":                 "#008080",
+	"thistle":              "#d8bfd8",
+	"tomato":               "#ff6347",
+	"transparent":          "#00000000",
+	"turquoise":            "#40e0d0",
+	"violet":               "#ee82ee",
+	"wheat":                "#f5deb3",
+	"white":                "#ffffff",
+	"whitesmoke":           "#f5f5f5",
+	"yellow":               "#ffff00",
+	"yellowgreen":          "#9acd32",
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeColor(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		// Hex
+		{"#abc", "#aabbcc"},
+		{"#abcd", "#aabbccdd"},
+		{"#ABCDEF", "#abcdef"},
+		{"#11223344", "#11223344"},
+		{"#aabbccff", "#aabbcc"},
+		{" #1e1e2e ", "#1e1e2e"},
+		// rgb() in comma and space-slash forms
+		{"rgb(255, 0, 0)", "#ff0000"},
+		{"rgba(255, 0, 0, 0.5)", "#ff000080"},
+		{"rgb(255 0 0 / 50%)", "#ff000080"},
+		{"rgb(100%, 0%, 50%)", "#ff0080"},
+		{"rgba(0 0 255 / 0)", "#0000ff00"},
+		{"rgb(300, -5, 0)", "#ff0000"},
+		// hsl() and oklch() with percentages
+		{"hsl(120, 100%, 50%)", "#00ff00"},
+		{"hsl(120deg 100% 25%)", "#008000"},
+		{"hsla(0, 100%, 50%, 0.5)", "#ff000080"},
+		{"hsl(-120 100% 50% / 25%)", "#0000ff40"},
+		{"oklch(100% 0 0)", "#ffffff"},
+		{"oklch(0% 0 0)", "#000000"},
+		{"oklch(1 0 0 / 50%)", "#ffffff80"},
+		// Named colors
+		{"red", "#ff0000"},
+		{"RebeccaPurple", "#663399"},
+		{"transparent", "#00000000"},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeColor(tt.value)
+		if err != nil {
+			t.Errorf("NormalizeColor(%q): %v", tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeColor(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseColorErrors(t *testing.T) {
+	for _, value := range []string{
+		"", "#", "#ab", "#abcde", "#ggg", "#1234567", "abc",
+		"rgb(1, 2)", "rgb(1 2 3 4)", "rgb(a, b, c)", "hsl(x 50% 50%)", "oklch(50% 0.1)",
+		"notacolor", "@primary",
+	} {
+		if c, err := ParseColor(value); err == nil {
+			t.Errorf("ParseColor(%q) = %v, want an error", value, c)
+		}
+	}
+}
+
+func TestResolveColors(t *testing.T) {
+	tests := []struct {
+		name   string
+		set    func(c *ShellConfig)
+		path   string
+		want   string
+		errMsg string
+	}{
+		{"literal", func(c *ShellConfig) { c.Appearance.Colors.Primary = "rgb(51, 102, 153)" }, "appearance.colors.primary", "#336699", ""},
+		{"reference", func(c *ShellConfig) {
+			c.Appearance.Colors.Primary = "#336699"
+			c.Appearance.AccentColor = "@primary"
+		}, "appearance.accentColor", "#336699", ""},
+		{"reference with alpha", func(c *ShellConfig) {
+			c.Appearance.Colors.Primary = "#336699"
+			c.Appearance.AccentColor = "@primary/80%"
+		}, "appearance.accentColor", "#336699cc", ""},
+		{"alpha multiplies", func(c *ShellConfig) {
+			c.Appearance.Colors.Background = "#00000080"
+			c.Bar.Background = "@background/0.5"
+		}, "bar.background", "#00000040", ""},
+		{"chain", func(c *ShellConfig) {
+			c.Appearance.Colors.Primary = "navy"
+			c.Appearance.Colors.Info = "@accent"
+			c.Appearance.AccentColor = "@primary"
+		}, "appearance.colors.info", "#000080", ""},
+		{"two-cycle", func(c *ShellConfig) {
+			c.Appearance.Colors.Primary = "@secondary"
+			c.Appearance.Colors.Secondary = "@primary"
+		}, "", "", "appearance.colors.primary: circular color reference: @primary → @secondary → @primary"},
+		{"self reference", func(c *ShellConfig) {
+			c.Appearance.Colors.Border = "@border/50%"
+		}, "", "", "appearance.colors.border: circular color reference: @border → @border"},
+		{"unknown reference", func(c *ShellConfig) {
+			c.Appearance.Colors.Border = "@nope"
+		}, "", "", "unknown color reference @nope"},
+		{"unset target", func(c *ShellConfig) {
+			c.Appearance.Colors.Surface = ""
+			c.Appearance.Colors.Border = "@surface"
+		}, "", "", "@surface is not set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			tt.set(config)
+
+			err := ResolveColors(config)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("ResolveColors error = %v, want %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveColors: %v", err)
+			}
+			configMap, _ := structToMap(config)
+			if got, _ := LookupPath(configMap, tt.path); got != tt.want {
+				t.Errorf("%s = %v, want %s", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckColorSyntax(t *testing.T) {
+	valid := []string{"#fff", "hsl(0 0% 0%)", "white", "@primary", "@Background/80%", "@accent/0.25"}
+	for _, value := range valid {
+		if err := CheckColorSyntax(value); err != nil {
+			t.Errorf("CheckColorSyntax(%q): %v", value, err)
+		}
+	}
+	invalid := []string{"#ffff0", "@", "@nope", "@primary/xx", "rgb()"}
+	for _, value := range invalid {
+		if err := CheckColorSyntax(value); err == nil {
+			t.Errorf("CheckColorSyntax(%q) passed, want an error", value)
+		}
+	}
+}
+
+func TestColorRefName(t *testing.T) {
+	for name, path := range colorRefPaths {
+		if got := colorRefName(path); got != name {
+			t.Errorf("colorRefName(%s) = %s, want %s", path, got, name)
+		}
+	}
+	if got := colorRefName("bar.background"); got != "bar.background" {
+		t.Errorf("colorRefName of a path without a name = %s", got)
+	}
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"fmt"
+	"math"
+)
+
+// Contrast levels for appearance.contrast
+const (
+	ContrastAA  = "aa"
+	ContrastAAA = "aaa"
+	ContrastOff = "off"
+)
+
+// ContrastThreshold holds a level's minimum ratios for text and for
+// non-text elements such as icons and indicators (WCAG 1.4.3/1.4.6 and 1.4.11)
+type ContrastThreshold struct {
+	Text    float64
+	NonText float64
+}
+
+// ContrastThresholds maps each level to its minimum ratios
+var ContrastThresholds = map[string]ContrastThreshold{
+	ContrastAA:  {Text: 4.5, NonText: 3},
+	ContrastAAA: {Text: 7, NonText: 4.5},
+}
+
+// ContrastPair is a foreground/background combination the validator checks
+type ContrastPair struct {
+	Foreground string
+	Background string
+	Text       bool
+}
+
+// ContrastPairs lists the checked combinations. The status colors mostly
+// appear as icons and indicators, so they are held to the non-text ratios.
+var ContrastPairs = []ContrastPair{
+	{Foreground: "appearance.colors.foreground", Background: "appearance.colors.background", Text: true},
+	{Foreground: "appearance.colors.foreground", Background: "appearance.colors.surface", Text: true},
+	{Foreground: "appearance.colors.error", Background: "appearance.colors.background"},
+	{Foreground: "appearance.colors.warning", Background: "appearance.colors.background"},
+	{Foreground: "appearance.colors.info", Background: "appearance.colors.background"},
+	{Foreground: "bar.foreground", Background: "bar.background", Text: true},
+}
+
+// contrastStep is the OKLCH lightness step FixContrast searches with
+const contrastStep = 0.005
+
+// ContrastRatio returns the WCAG contrast ratio (1-21) of fg on bg. A
+// translucent foreground is blended over the background first; the
+// background's own alpha is ignored since what lies behind it is unknown.
+func ContrastRatio(fg, bg string) (float64, error) {
+	f, err := ParseColor(fg)
+	if err != nil {
+		return 0, err
+	}
+	b, err := ParseColor(bg)
+	if err != nil {
+		return 0, err
+	}
+
+	alpha := float64(f[3]) / 255
+	for i := 0; i < 3; i++ {
+		f[i] = uint8(math.Round(float64(f[i])*alpha + float64(b[i])*(1-alpha)))
+	}
+
+	l1, l2 := relativeLuminance(f), relativeLuminance(b)
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05), nil
+}
+
+// FixContrast returns the color closest to fg in OKLCH lightness that reaches
+// ratio on bg, keeping fg's hue and alpha. Both lighter and darker colors
+// are searched and the smaller change wins, so a light foreground on a
+// mid-tone background gets lighter rather than crossing the background.
+// It reports false when neither white nor black end reaches the ratio.
+func FixContrast(fg, bg string, ratio float64) (string, bool) {
+	f, err := ParseColor(fg)
+	if err != nil {
+		return "", false
+	}
+	if _, err := ParseColor(bg); err != nil {
+		return "", false
+	}
+
+	alpha := ""
+	if f[3] != 255 {
+		alpha = fmt.Sprintf("%02x", f[3])
+	}
+
+	color := ToOKLCH(f[0], f[1], f[2])
+	lighter, upOK := searchContrast(color, alpha, bg, ratio, contrastStep)
+	darker, downOK := searchContrast(color, alpha, bg, ratio, -contrastStep)
+	switch {
+	case upOK && downOK:
+		if math.Abs(lighter.L-color.L) <= math.Abs(darker.L-color.L) {
+			return lighter.Hex() + alpha, true
+		}
+		return darker.Hex() + alpha, true
+	case upOK:
+		return lighter.Hex() + alpha, true
+	case downOK:
+		return darker.Hex() + alpha, true
+	}
+	return "", false
+}
+
+// searchContrast steps color's lightness by step until it reaches ratio on
+// bg, returning the first color that does
+func searchContrast(color OKLCH, alpha, bg string, ratio, step float64) (OKLCH, bool) {
+	for l := color.L; l >= 0 && l <= 1+contrastStep/2; l += step {
+		candidate := OKLCH{L: l, C: color.C, H: color.H}
+		if got, err := ContrastRatio(candidate.Hex()+alpha, bg); err == nil && got >= ratio {
+			return candidate, true
+		}
+	}
+	return OKLCH{}, false
+}
+
+// ContrastLevel returns the ratios appearance.contrast asks for; "off"
+// returns false
+func ContrastLevel(level string) (ContrastThreshold, bool) {
+	if level == "" {
+		level = ContrastAA
+	}
+	threshold, ok := ContrastThresholds[level]
+	return threshold, ok
+}
+
+// luminance returns the WCAG relative luminance of a hex color (0 if invalid)
+func luminance(color string) float64 {
+	c, err := ParseColor(color)
+	if err != nil {
+		return 0
+	}
+	return relativeLuminance(c)
+}
+
+// relativeLuminance returns the WCAG relative luminance of sRGB channels
+func relativeLuminance(c [4]uint8) float64 {
+	return 0.2126*srgbToLinear(c[0]) + 0.7152*srgbToLinear(c[1]) + 0.0722*srgbToLinear(c[2])
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"math"
+	"testing"
+)
+
+func TestContrastRatio(t *testing.T) {
+	tests := []struct {
+		fg, bg string
+		want   float64
+	}{
+		{"#000000", "#ffffff", 21},
+		{"#ffffff", "#ffffff", 1},
+		{"#777777", "#ffffff", 4.48},
+		// Half-transparent black over white is mid gray
+		{"#00000080", "#ffffff", 4.00},
+	}
+	for _, tt := range tests {
+		got, err := ContrastRatio(tt.fg, tt.bg)
+		if err != nil {
+			t.Fatalf("ContrastRatio(%s, %s): %v", tt.fg, tt.bg, err)
+		}
+		if math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("ContrastRatio(%s, %s) = %.2f, want %.2f", tt.fg, tt.bg, got, tt.want)
+		}
+	}
+}
+
+func TestFixContrast(t *testing.T) {
+	tests := []struct {
+		name    string
+		fg, bg  string
+		ratio   float64
+		lighter bool
+	}{
+		{"dark text on white", "#999999", "#ffffff", 4.5, false},
+		{"light text on black", "#444444", "#000000", 4.5, true},
+		// Luminance above 0.18 used to force darkening, crossing the background
+		{"light text on mid tone", "#e0e0e0", "#8a8a8a", 3, true},
+		{"dark text on mid tone", "#303030", "#8a8a8a", 4.5, false},
+		{"translucent", "#cccccc80", "#202020", 4.5, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixed, ok := FixContrast(tt.fg, tt.bg, tt.ratio)
+			if !ok {
+				t.Fatalf("FixContrast(%s, %s) found no color", tt.fg, tt.bg)
+			}
+			got, _ := ContrastRatio(fixed, tt.bg)
+			if got < tt.ratio {
+				t.Errorf("%s reaches %.2f, want at least %.2f", fixed, got, tt.ratio)
+			}
+			if len(fixed) != len(tt.fg) {
+				t.Errorf("%s lost or gained alpha from %s", fixed, tt.fg)
+			}
+			if lighter := luminance(fixed[:7]) > luminance(tt.fg[:7]); lighter != tt.lighter {
+				t.Errorf("%s → %s: lighter = %v, want %v", tt.fg, fixed, lighter, tt.lighter)
+			}
+		})
+	}
+
+	if _, ok := FixContrast("#808080", "#808080", 22); ok {
+		t.Error("expected no color to reach a ratio above 21")
+	}
+}
+
+func TestApplyFixesKeepsReferences(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Appearance.Colors.Surface = "#303030"
+	config.Bar.Background = "#2a2a2a"
+	config.Bar.Foreground = "@surface"
+
+	validator := NewSchemaValidator()
+	var fix *SuggestedFix
+	for _, verr := range validator.Validate(config) {
+		if verr.Path == "bar.foreground" {
+			fix = verr.Fix
+		}
+	}
+	if fix == nil || fix.AutoFix {
+		t.Fatalf("fix for bar.foreground = %+v, want a manual one", fix)
+	}
+
+	if _, _, err := ApplyFixes(config, validator.Validate); err != nil {
+		t.Fatal(err)
+	}
+	if config.Bar.Foreground != "@surface" {
+		t.Errorf("bar.foreground = %s, want the reference kept", config.Bar.Foreground)
+	}
+	if config.Appearance.Colors.Surface != "#303030" {
+		t.Errorf("surface = %s, want it unchanged", config.Appearance.Colors.Surface)
+	}
+}
@@ -55,6 +55,7 @@ func GetDefaultConfig() *ShellConfig {
 			Shadows:        true,
 			Animations:     true,
 			AnimationSpeed: "normal",
+			Contrast:       ContrastAA,
 			Colors: ColorConfig{
 				Background: "#1e1e2e",
 				Foreground: "#cdd6f4",
@@ -67,6 +68,15 @@ func GetDefaultConfig() *ShellConfig {
 				Surface:    "#313244",
 				Border:     "#45475a",
 			},
+			Palettes: ThemePalettes{
+				Light: ThemePalette{ColorScheme: "catppuccin-latte"},
+				Dark:  ThemePalette{ColorScheme: "catppuccin-mocha"},
+			},
+			Schedule: ThemeSchedule{
+				Mode:  ScheduleOff,
+				Light: "07:00",
+				Dark:  "19:00",
+			},
 		},
 		Bar: BarConfig{
 			Position: "top",
@@ -168,9 +178,6 @@ func GetDefaultConfig() *ShellConfig {
 				AutoBrightness: false,
 				DPMS:           true,
 				DPMSTimeout:    600,
-				Resolution:     "auto",
-				RefreshRate:    60,
-				Scale:          1.0,
 			},
 		},
 		Commands: CommandsConfig{
@@ -187,7 +194,10 @@ func GetDefaultConfig() *ShellConfig {
 			Dim:          false,
 			DimStrength:  0.3,
 			FillMode:     "cover",
-			Monitors:     []string{},
+			Backend:      WallpaperBackendAuto,
+		},
+		Monitors: MonitorsConfig{
+			AllMonitors: {Resolution: "auto", RefreshRate: 60, Scale: 1.0},
 		},
 		HotReload: HotReloadConfig{
 			Enabled: true,
@@ -203,6 +213,15 @@ func GetDefaultConfig() *ShellConfig {
 			MaxRetries: 3,
 			RetryDelay: 1000,
 		},
+		Profiles: ProfilesConfig{
+			Auto: AutoProfileConfig{
+				Interval: 60,
+				Rules:    []ProfileRule{},
+			},
+		},
+		Render: RenderConfig{
+			Targets: make(map[string]RenderTarget),
+		},
 		Extra: make(map[string]interface{}),
 	}
 }
@@ -233,6 +252,7 @@ func GetMinimalConfig() *ShellConfig {
 			ColorScheme:  "default",
 			AccentColor:  "#0078d4",
 			Transparency: 1.0,
+			Contrast:     ContrastAA,
 			Colors: ColorConfig{
 				Background: "#000000",
 				Foreground: "#ffffff",
@@ -245,6 +265,9 @@ func GetMinimalConfig() *ShellConfig {
 				Surface:    "#1f1f1f",
 				Border:     "#3f3f3f",
 			},
+			Schedule: ThemeSchedule{
+				Mode: ScheduleOff,
+			},
 		},
 		Bar: BarConfig{
 			Position:      "top",
@@ -282,9 +305,7 @@ func GetMinimalConfig() *ShellConfig {
 				BatteryCriticalThreshold: 10,
 			},
 			Display: DisplayConfig{
-				Brightness:  100,
-				RefreshRate: 60,
-				Scale:       1.0,
+				Brightness: 100,
 			},
 		},
 		Commands: CommandsConfig{
@@ -293,17 +314,32 @@ func GetMinimalConfig() *ShellConfig {
 		Wallpaper: WallpaperConfig{
 			Mode:     "static",
 			FillMode: "cover",
-			Monitors: []string{},
+			Backend:  WallpaperBackendAuto,
+		},
+		Monitors: MonitorsConfig{
+			AllMonitors: {RefreshRate: 60, Scale: 1.0},
 		},
 		HotReload: HotReloadConfig{
 			Enabled:        false,
 			WatchPaths:     []string{},
 			IgnorePatterns: []string{},
 		},
+		Profiles: ProfilesConfig{
+			Auto: AutoProfileConfig{
+				Interval: 60,
+				Rules:    []ProfileRule{},
+			},
+		},
+		Render: RenderConfig{
+			Targets: make(map[string]RenderTarget),
+		},
 		Extra: make(map[string]interface{}),
 	}
 }
 
+// BuiltinProfiles lists the profile names known to GetProfileConfig
+var BuiltinProfiles = []string{"default", "minimal", "gaming", "productivity", "development"}
+
 // GetProfileConfig returns a configuration for a specific profile
 func GetProfileConfig(profile string) *ShellConfig {
 	switch profile {
@@ -364,7 +400,7 @@ func GetProductivityConfig() *ShellConfig {
 	config.Metadata.Profile = "productivity"
 
 	// Clean, distraction-free appearance
-	config.Appearance.ColorScheme = "nord"
+	useBuiltinScheme(config, "nord")
 	config.Appearance.Transparency = 0.95
 	config.Appearance.BlurRadius = 20
 	config.Appearance.AnimationSpeed = "fast"
@@ -406,7 +442,7 @@ func GetDevelopmentConfig() *ShellConfig {
 	config.Metadata.Profile = "development"
 
 	// Developer-friendly appearance
-	config.Appearance.ColorScheme = "dracula"
+	useBuiltinScheme(config, "dracula")
 	config.Appearance.Transparency = 0.9
 	config.System.Terminal = "alacritty"
 	config.System.Editor = "code"
@@ -431,7 +467,9 @@ func GetDevelopmentConfig() *ShellConfig {
 
 	// Development services
 	config.Services.Notifications.HistorySize = 100
-	config.Services.Display.Scale = 1.25 // Better for reading code
+	defaults := config.Monitors[AllMonitors]
+	defaults.Scale = 1.25 // Better for reading code
+	config.Monitors[AllMonitors] = defaults
 
 	// Hot reload for quick testing
 	config.HotReload.Enabled = true
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	pathpkg "path"
+	"reflect"
+	"strings"
+)
+
+// PathChange records a value change at a dotted path
+type PathChange struct {
+	Path string
+	Old  interface{}
+	New  interface{}
+}
+
+// String renders the change as "path: old → new"
+func (c PathChange) String() string {
+	return fmt.Sprintf("%s: %s → %s", c.Path, FormatValue(c.Old), FormatValue(c.New))
+}
+
+// DiffConfigs lists the schema leaves whose values differ between two configs.
+// When paths is empty every leaf is compared.
+func DiffConfigs(oldConfig, newConfig *ShellConfig, paths []string) ([]PathChange, error) {
+	oldMap, err := structToMap(oldConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert config to map: %w", err)
+	}
+	newMap, err := structToMap(newConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert config to map: %w", err)
+	}
+
+	if len(paths) == 0 {
+		paths = SchemaPaths()
+	}
+
+	changes := make([]PathChange, 0)
+	for _, path := range paths {
+		oldValue, _ := LookupPath(oldMap, path)
+		newValue, _ := LookupPath(newMap, path)
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, PathChange{Path: path, Old: oldValue, New: newValue})
+		}
+	}
+
+	return changes, nil
+}
+
+// MatchPaths expands dotted paths and globs into schema leaves.
+// Each pattern segment is matched with path.Match, and a pattern naming a
+// section selects every leaf below it (e.g. "appearance.colors", "bar.*.top").
+// Entries of a map of structs are expanded when the pattern names the key,
+// as in "monitors.DP-1.scale" or "monitors.*".
+func MatchPaths(patterns []string) ([]string, error) {
+	leaves := SchemaPaths()
+	seen := make(map[string]bool)
+	matched := make([]string, 0)
+
+	for _, pattern := range patterns {
+		found := false
+		candidates := append(append([]string{}, leaves...), mapEntryPaths(pattern)...)
+		for _, leaf := range candidates {
+			ok, err := matchPathPattern(pattern, leaf)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+			if !ok {
+				continue
+			}
+			found = true
+			if !seen[leaf] {
+				seen[leaf] = true
+				matched = append(matched, leaf)
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no configuration paths match %q", pattern)
+		}
+	}
+
+	return matched, nil
+}
+
+// mapEntryPaths lists the leaves of the map entry a pattern names, like
+// commands.custom.screenshot.* or monitors.DP-1.*. Keys are taken literally,
+// since the schema cannot list them; "*" is the name of the monitors
+// defaults entry.
+func mapEntryPaths(pattern string) []string {
+	paths := make([]string, 0)
+	for _, f := range SchemaFields() {
+		if f.Kind != reflect.Map || f.Type.Elem().Kind() != reflect.Struct || !strings.HasPrefix(pattern, f.Path+".") {
+			continue
+		}
+		key := strings.SplitN(strings.TrimPrefix(pattern, f.Path+"."), ".", 2)[0]
+		if key == "" || strings.ContainsAny(key, "?[\\") {
+			continue
+		}
+		elemFields := make([]FieldInfo, 0)
+		collectFields("", f.Type.Elem(), &elemFields)
+		for _, elem := range elemFields {
+			paths = append(paths, f.Path+"."+key+"."+elem.Path)
+		}
+	}
+	return paths
+}
+
+// matchPathPattern matches a leaf against a dotted glob, segment by segment
+func matchPathPattern(pattern, leaf string) (bool, error) {
+	patternParts := strings.Split(pattern, ".")
+	leafParts := strings.Split(leaf, ".")
+
+	if len(patternParts) > len(leafParts) {
+		return false, nil
+	}
+
+	for i, part := range patternParts {
+		ok, err := pathpkg.Match(part, leafParts[i])
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchPaths(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		want     []string
+	}{
+		{[]string{"bar.height"}, []string{"bar.height"}},
+		{[]string{"bar.margin"}, []string{"bar.margin.top", "bar.margin.right", "bar.margin.bottom", "bar.margin.left"}},
+		{[]string{"bar.*.top"}, []string{"bar.margin.top", "bar.padding.top"}},
+		{[]string{"commands.custom.screenshot.command"}, []string{"commands.custom.screenshot.command"}},
+		{[]string{"monitors.DP-1.scale"}, []string{"monitors.DP-1.scale"}},
+		{[]string{"monitors.*.refreshRate", "monitors.DP-1.refreshRate"}, []string{"monitors.*.refreshRate", "monitors.DP-1.refreshRate"}},
+	}
+
+	for _, tt := range tests {
+		got, err := MatchPaths(tt.patterns)
+		if err != nil {
+			t.Errorf("MatchPaths(%q): %v", tt.patterns, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("MatchPaths(%q) = %v, want %v", tt.patterns, got, tt.want)
+		}
+	}
+
+	entry, err := MatchPaths([]string{"commands.custom.screenshot"})
+	if err != nil || len(entry) == 0 {
+		t.Errorf("MatchPaths(commands.custom.screenshot) = %v, %v, want the entry's fields", entry, err)
+	}
+	for _, pattern := range []string{"nope", "commands.custom.shot?.command", "commands.custom.screenshot.nope"} {
+		if _, err := MatchPaths([]string{pattern}); err == nil {
+			t.Errorf("MatchPaths(%q) matched, want an error", pattern)
+		}
+	}
+}
+
+func TestResetMonitorEntry(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Monitors["DP-1"] = MonitorConfig{Scale: 1.5, Transform: "90"}
+	source := GetDefaultConfig()
+
+	paths, err := MatchPaths([]string{"monitors.DP-1.scale"})
+	if err != nil {
+		t.Fatalf("MatchPaths: %v", err)
+	}
+	injector := NewPropertyInjector()
+	plan, err := injector.PlanReset(config, source, paths, false)
+	if err != nil {
+		t.Fatalf("PlanReset: %v", err)
+	}
+	if len(plan.Changes) != 1 {
+		t.Fatalf("changes = %v, want one for monitors.DP-1.scale", plan.Changes)
+	}
+	if err := injector.ApplyChanges(config, plan.Changes); err != nil {
+		t.Fatalf("ApplyChanges: %v", err)
+	}
+
+	// The entry now inherits the scale from "*" and keeps its transform
+	dp1 := config.Monitors["DP-1"]
+	if dp1.Scale != 0 || dp1.Transform != "90" {
+		t.Errorf("monitors.DP-1 = %+v, want scale unset and transform 90", dp1)
+	}
+}
@@ -0,0 +1,474 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// SystemProbe abstracts the filesystem and PATH lookups used by the doctor
+type SystemProbe interface {
+	LookPath(name string) (string, error)
+	Stat(path string) (os.FileInfo, error)
+	ReadDir(path string) ([]os.DirEntry, error)
+	ReadFile(path string) ([]byte, error)
+	Getenv(key string) string
+}
+
+// OSProbe is the SystemProbe backed by the real system
+type OSProbe struct{}
+
+func (OSProbe) LookPath(name string) (string, error)       { return exec.LookPath(name) }
+func (OSProbe) Stat(path string) (os.FileInfo, error)      { return os.Stat(path) }
+func (OSProbe) ReadDir(path string) ([]os.DirEntry, error) { return os.ReadDir(path) }
+func (OSProbe) ReadFile(path string) ([]byte, error)       { return os.ReadFile(path) }
+func (OSProbe) Getenv(key string) string                   { return os.Getenv(key) }
+
+// ToolAlternatives lists well-known programs for each system tool field
+var ToolAlternatives = map[string][]string{
+	"system.shell":              {"bash", "zsh", "fish", "nu"},
+	"system.terminal":           {"kitty", "alacritty", "foot", "wezterm", "ghostty", "gnome-terminal", "konsole"},
+	"system.fileManager":        {"nemo", "nautilus", "thunar", "dolphin", "pcmanfm"},
+	"system.editor":             {"nvim", "vim", "hx", "code", "nano"},
+	"system.browser":            {"firefox", "chromium", "google-chrome-stable", "brave"},
+	"system.screenshotTool":     {"grim", "grimblast", "hyprshot", "flameshot"},
+	"system.colorPicker":        {"hyprpicker", "wl-color-picker"},
+	"system.clipboardTool":      {"wl-clipboard", "cliphist", "clipman"},
+	"system.launcher":           {"fuzzel", "rofi", "wofi", "tofi", "anyrun"},
+	"system.powerMenu":          {"wlogout", "nwg-bar"},
+	"system.lockScreen":         {"hyprlock", "swaylock", "gtklock"},
+	"system.notificationDaemon": {"dunst", "mako", "swaync"},
+	"system.audioControl":       {"pavucontrol", "pwvucontrol", "helvum"},
+	"system.networkManager":     {"nm-applet", "nm-connection-editor", "iwgtk"},
+	"system.bluetoothManager":   {"blueman-applet", "blueman-manager", "overskride"},
+	"system.displayManager":     {"wdisplays", "nwg-displays", "kanshi"},
+	"system.themeManager":       {"nwg-look", "lxappearance"},
+}
+
+// toolBinaries maps package-style tool names to the executable they install
+var toolBinaries = map[string]string{
+	"wl-clipboard": "wl-copy",
+}
+
+// genericFontFamilies are resolved by fontconfig aliases rather than files
+var genericFontFamilies = []string{"monospace", "sans-serif", "sans", "serif", "system-ui", "emoji"}
+
+var fontFilePattern = regexp.MustCompile(`(?i)\.(ttf|otf|ttc|otc|pcf|pcf\.gz|woff2?)$`)
+var fontconfigDirPattern = regexp.MustCompile(`<dir(\s+prefix="([^"]*)")?\s*>([^<]+)</dir>`)
+
+// Doctor checks that the programs, themes and files named by a config exist
+type Doctor struct {
+	probe SystemProbe
+}
+
+// NewDoctor creates a doctor using the given probe
+func NewDoctor(probe SystemProbe) *Doctor {
+	if probe == nil {
+		probe = OSProbe{}
+	}
+	return &Doctor{probe: probe}
+}
+
+// Check runs all environment checks and returns warnings with suggested fixes
+func (d *Doctor) Check(config *ShellConfig) []ValidationError {
+	errors := make([]ValidationError, 0)
+
+	errors = append(errors, d.checkTools(&config.System)...)
+	errors = append(errors, d.checkPolkitAgent(config.System.PolkitAgent)...)
+	errors = append(errors, d.checkThemes(&config.System)...)
+	errors = append(errors, d.checkFont(&config.System.Font)...)
+	errors = append(errors, d.checkWallpaper(&config.Wallpaper)...)
+	errors = append(errors, d.checkMonitorWallpapers(config.Monitors)...)
+
+	return errors
+}
+
+// checkTools resolves each system tool against $PATH
+func (d *Doctor) checkTools(sys *SystemConfig) []ValidationError {
+	errors := make([]ValidationError, 0)
+
+	tools := []struct {
+		path  string
+		value string
+	}{
+		{"system.shell", sys.Shell},
+		{"system.terminal", sys.Terminal},
+		{"system.fileManager", sys.FileManager},
+		{"system.editor", sys.Editor},
+		{"system.browser", sys.Browser},
+		{"system.screenshotTool", sys.ScreenshotTool},
+		{"system.colorPicker", sys.ColorPicker},
+		{"system.clipboardTool", sys.ClipboardTool},
+		{"system.launcher", sys.Launcher},
+		{"system.powerMenu", sys.PowerMenu},
+		{"system.lockScreen", sys.LockScreen},
+		{"system.notificationDaemon", sys.NotificationDaemon},
+		{"system.audioControl", sys.AudioControl},
+		{"system.networkManager", sys.NetworkManager},
+		{"system.bluetoothManager", sys.BluetoothManager},
+		{"system.displayManager", sys.DisplayManager},
+		{"system.themeManager", sys.ThemeManager},
+	}
+
+	for _, tool := range tools {
+		if tool.value == "" {
+			continue
+		}
+		if d.toolInstalled(tool.value) {
+			continue
+		}
+
+		fix := &SuggestedFix{
+			Description: fmt.Sprintf("Install %s or point %s at an installed program", d.toolBinary(tool.value), tool.path),
+		}
+		if alt := d.InstalledAlternatives(tool.path); len(alt) > 0 {
+			fix.Description = fmt.Sprintf("Installed alternatives: %s", strings.Join(alt, ", "))
+			fix.Command = fmt.Sprintf("heimdall-cli config set %s %s", tool.path, alt[0])
+		}
+
+		errors = append(errors, ValidationError{
+			Type:     EnvironmentErrorType,
+			Path:     tool.path,
+			Message:  fmt.Sprintf("%s not found in $PATH", d.toolBinary(tool.value)),
+			Severity: SeverityWarning,
+			Fix:      fix,
+		})
+	}
+
+	return errors
+}
+
+// InstalledAlternatives returns the known programs for a tool field found in $PATH
+func (d *Doctor) InstalledAlternatives(path string) []string {
+	installed := make([]string, 0)
+	for _, candidate := range ToolAlternatives[path] {
+		if d.toolInstalled(candidate) {
+			installed = append(installed, candidate)
+		}
+	}
+	return installed
+}
+
+// toolInstalled reports whether a tool command resolves to an executable
+func (d *Doctor) toolInstalled(value string) bool {
+	binary := d.toolBinary(value)
+	if filepath.IsAbs(binary) {
+		_, err := d.probe.Stat(binary)
+		return err == nil
+	}
+	_, err := d.probe.LookPath(binary)
+	return err == nil
+}
+
+// toolBinary extracts the executable name from a tool value
+func (d *Doctor) toolBinary(value string) string {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return value
+	}
+	if binary, ok := toolBinaries[fields[0]]; ok {
+		return binary
+	}
+	return d.expandHome(fields[0])
+}
+
+// checkPolkitAgent checks the polkit agent's absolute path
+func (d *Doctor) checkPolkitAgent(agent string) []ValidationError {
+	if agent == "" {
+		return nil
+	}
+
+	if !filepath.IsAbs(d.expandHome(agent)) {
+		return []ValidationError{{
+			Type:     EnvironmentErrorType,
+			Path:     "system.polkitAgent",
+			Message:  fmt.Sprintf("Polkit agent should be an absolute path: %s", agent),
+			Severity: SeverityWarning,
+			Fix: &SuggestedFix{
+				Description: "Use the full path to the agent executable",
+				Command:     "heimdall-cli config set system.polkitAgent /usr/lib/polkit-gnome/polkit-gnome-authentication-agent-1",
+			},
+		}}
+	}
+
+	if _, err := d.probe.Stat(d.expandHome(agent)); err != nil {
+		return []ValidationError{{
+			Type:     EnvironmentErrorType,
+			Path:     "system.polkitAgent",
+			Message:  fmt.Sprintf("Polkit agent not found: %s", agent),
+			Severity: SeverityWarning,
+			Fix: &SuggestedFix{
+				Description: "Install a polkit agent (polkit-gnome, polkit-kde-agent, hyprpolkitagent) and set its path",
+			},
+		}}
+	}
+
+	return nil
+}
+
+// checkThemes looks up the icon and cursor themes in the XDG icon directories
+func (d *Doctor) checkThemes(sys *SystemConfig) []ValidationError {
+	errors := make([]ValidationError, 0)
+
+	if sys.IconTheme != "" && !d.themeExists(sys.IconTheme, "index.theme") {
+		errors = append(errors, ValidationError{
+			Type:     EnvironmentErrorType,
+			Path:     "system.iconTheme",
+			Message:  fmt.Sprintf("Icon theme not found: %s", sys.IconTheme),
+			Severity: SeverityWarning,
+			Fix: &SuggestedFix{
+				Description: fmt.Sprintf("Install the theme or pick one of: %s", strings.Join(d.listThemes("index.theme"), ", ")),
+			},
+		})
+	}
+
+	if sys.CursorTheme != "" && !d.themeExists(sys.CursorTheme, "cursors") {
+		errors = append(errors, ValidationError{
+			Type:     EnvironmentErrorType,
+			Path:     "system.cursorTheme",
+			Message:  fmt.Sprintf("Cursor theme not found: %s", sys.CursorTheme),
+			Severity: SeverityWarning,
+			Fix: &SuggestedFix{
+				Description: fmt.Sprintf("Install the theme or pick one of: %s", strings.Join(d.listThemes("cursors"), ", ")),
+			},
+		})
+	}
+
+	return errors
+}
+
+// iconDirs returns the XDG icon search directories
+func (d *Doctor) iconDirs() []string {
+	dirs := []string{
+		filepath.Join(d.dataHome(), "icons"),
+		filepath.Join(d.probe.Getenv("HOME"), ".icons"),
+	}
+	for _, dir := range d.dataDirs() {
+		dirs = append(dirs, filepath.Join(dir, "icons"))
+	}
+	return append(dirs, "/usr/share/pixmaps")
+}
+
+// themeExists checks for a theme directory containing marker
+func (d *Doctor) themeExists(name, marker string) bool {
+	for _, dir := range d.iconDirs() {
+		if _, err := d.probe.Stat(filepath.Join(dir, name, marker)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// listThemes lists installed themes containing marker
+func (d *Doctor) listThemes(marker string) []string {
+	seen := make(map[string]bool)
+	themes := make([]string, 0)
+	for _, dir := range d.iconDirs() {
+		entries, err := d.probe.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if seen[name] || !d.themeExistsIn(dir, name, marker) {
+				continue
+			}
+			seen[name] = true
+			themes = append(themes, name)
+		}
+	}
+	return themes
+}
+
+// themeExistsIn checks a single icon directory for a theme
+func (d *Doctor) themeExistsIn(dir, name, marker string) bool {
+	_, err := d.probe.Stat(filepath.Join(dir, name, marker))
+	return err == nil
+}
+
+// checkFont resolves the font family against the fontconfig font directories
+func (d *Doctor) checkFont(font *FontConfig) []ValidationError {
+	if font.Family == "" || contains(genericFontFamilies, strings.ToLower(font.Family)) {
+		return nil
+	}
+
+	if d.fontInstalled(font.Family) {
+		return nil
+	}
+
+	return []ValidationError{{
+		Type:     EnvironmentErrorType,
+		Path:     "system.font.family",
+		Message:  fmt.Sprintf("Font family not found in fontconfig directories: %s", font.Family),
+		Severity: SeverityWarning,
+		Fix: &SuggestedFix{
+			Description: "Install the font or use a generic family",
+			Command:     "heimdall-cli config set system.font.family monospace",
+		},
+	}}
+}
+
+// fontInstalled searches font files whose names match the family
+func (d *Doctor) fontInstalled(family string) bool {
+	want := normalizeFontName(family)
+	for _, dir := range d.fontDirs() {
+		if d.findFontFile(dir, want, 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// findFontFile walks a font directory looking for a matching file
+func (d *Doctor) findFontFile(dir, want string, depth int) bool {
+	if depth > 6 {
+		return false
+	}
+	entries, err := d.probe.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			if d.findFontFile(filepath.Join(dir, entry.Name()), want, depth+1) {
+				return true
+			}
+			continue
+		}
+		if fontFilePattern.MatchString(entry.Name()) && strings.Contains(normalizeFontName(entry.Name()), want) {
+			return true
+		}
+	}
+	return false
+}
+
+// fontDirs returns the font directories declared in fonts.conf plus the usual defaults
+func (d *Doctor) fontDirs() []string {
+	dirs := []string{
+		filepath.Join(d.dataHome(), "fonts"),
+		filepath.Join(d.probe.Getenv("HOME"), ".fonts"),
+		"/usr/share/fonts",
+		"/usr/local/share/fonts",
+	}
+
+	data, err := d.probe.ReadFile("/etc/fonts/fonts.conf")
+	if err != nil {
+		return dirs
+	}
+	for _, match := range fontconfigDirPattern.FindAllStringSubmatch(string(data), -1) {
+		dir := strings.TrimSpace(match[3])
+		if match[2] == "xdg" {
+			dir = filepath.Join(d.dataHome(), dir)
+		}
+		dir = d.expandHome(dir)
+		if !contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// checkWallpaper checks that configured wallpaper paths exist
+func (d *Doctor) checkWallpaper(wallpaper *WallpaperConfig) []ValidationError {
+	errors := make([]ValidationError, 0)
+
+	if wallpaper.Path != "" {
+		if _, err := d.probe.Stat(d.expandHome(wallpaper.Path)); err != nil {
+			errors = append(errors, ValidationError{
+				Type:     EnvironmentErrorType,
+				Path:     "wallpaper.path",
+				Message:  fmt.Sprintf("Wallpaper file not found: %s", wallpaper.Path),
+				Severity: SeverityWarning,
+				Fix: &SuggestedFix{
+					Description: "Point wallpaper.path at an existing image",
+					Command:     "heimdall-cli config set wallpaper.path <file>",
+				},
+			})
+		}
+	}
+
+	if wallpaper.Directory != "" {
+		info, err := d.probe.Stat(d.expandHome(wallpaper.Directory))
+		if err != nil || !info.IsDir() {
+			errors = append(errors, ValidationError{
+				Type:     EnvironmentErrorType,
+				Path:     "wallpaper.directory",
+				Message:  fmt.Sprintf("Wallpaper directory not found: %s", wallpaper.Directory),
+				Severity: SeverityWarning,
+				Fix: &SuggestedFix{
+					Description: fmt.Sprintf("Create it with: mkdir -p %s", wallpaper.Directory),
+				},
+			})
+		}
+	}
+
+	return errors
+}
+
+// checkMonitorWallpapers checks that per-output wallpapers exist
+func (d *Doctor) checkMonitorWallpapers(monitors MonitorsConfig) []ValidationError {
+	errors := make([]ValidationError, 0)
+
+	for _, name := range monitors.MonitorNames() {
+		wallpaper := monitors[name].Wallpaper
+		if wallpaper == "" {
+			continue
+		}
+		if _, err := d.probe.Stat(d.expandHome(wallpaper)); err != nil {
+			errors = append(errors, ValidationError{
+				Type:     EnvironmentErrorType,
+				Path:     "monitors." + name + ".wallpaper",
+				Message:  fmt.Sprintf("Wallpaper file not found: %s", wallpaper),
+				Severity: SeverityWarning,
+				Fix: &SuggestedFix{
+					Description: "Point it at an existing image",
+					Command:     fmt.Sprintf("heimdall-cli config set monitors.%s.wallpaper <file>", name),
+				},
+			})
+		}
+	}
+
+	return errors
+}
+
+// dataHome returns $XDG_DATA_HOME
+func (d *Doctor) dataHome() string {
+	if dir := d.probe.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir
+	}
+	return filepath.Join(d.probe.Getenv("HOME"), ".local", "share")
+}
+
+// dataDirs returns $XDG_DATA_DIRS
+func (d *Doctor) dataDirs() []string {
+	dirs := d.probe.Getenv("XDG_DATA_DIRS")
+	if dirs == "" {
+		dirs = "/usr/local/share:/usr/share"
+	}
+	return filepath.SplitList(dirs)
+}
+
+// expandHome expands a leading ~ using the probe's $HOME
+func (d *Doctor) expandHome(path string) string {
+	if path == "~" {
+		return d.probe.Getenv("HOME")
+	}
+	if strings.HasPrefix(path, "~/") {
+		return filepath.Join(d.probe.Getenv("HOME"), path[2:])
+	}
+	return path
+}
+
+// normalizeFontName lowercases a name and strips everything but letters and digits
+func normalizeFontName(name string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
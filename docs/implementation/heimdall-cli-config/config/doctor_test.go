@@ -0,0 +1,195 @@
+package config
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// fakeProbe is a SystemProbe over an in-memory tree, a fixed $PATH and
+// environment
+type fakeProbe struct {
+	files fstest.MapFS
+	path  map[string]bool
+	env   map[string]string
+}
+
+func (p fakeProbe) LookPath(name string) (string, error) {
+	if p.path[name] {
+		return "/usr/bin/" + name, nil
+	}
+	return "", fmt.Errorf("exec: %q: executable file not found in $PATH", name)
+}
+
+func (p fakeProbe) Stat(path string) (os.FileInfo, error) {
+	return fs.Stat(p.files, strings.TrimPrefix(path, "/"))
+}
+
+func (p fakeProbe) ReadDir(path string) ([]os.DirEntry, error) {
+	return fs.ReadDir(p.files, strings.TrimPrefix(path, "/"))
+}
+
+func (p fakeProbe) ReadFile(path string) ([]byte, error) {
+	return fs.ReadFile(p.files, strings.TrimPrefix(path, "/"))
+}
+
+func (p fakeProbe) Getenv(key string) string { return p.env[key] }
+
+func newFakeProbe(binaries []string, files ...string) fakeProbe {
+	probe := fakeProbe{
+		files: fstest.MapFS{},
+		path:  make(map[string]bool),
+		env:   map[string]string{"HOME": "/home/me"},
+	}
+	for _, binary := range binaries {
+		probe.path[binary] = true
+	}
+	for _, file := range files {
+		probe.files[strings.TrimPrefix(file, "/")] = &fstest.MapFile{}
+	}
+	return probe
+}
+
+func TestDoctorChecks(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(c *ShellConfig)
+		probe fakeProbe
+		// want maps each expected warning's path to a part of its message
+		want map[string]string
+		// fix is part of the suggested fix of the single expected warning
+		fix string
+	}{
+		{
+			name:  "tool found",
+			setup: func(c *ShellConfig) { c.System.Terminal = "kitty --single-instance" },
+			probe: newFakeProbe([]string{"kitty"}),
+		},
+		{
+			name:  "missing tool with an installed alternative",
+			setup: func(c *ShellConfig) { c.System.Terminal = "kitty" },
+			probe: newFakeProbe([]string{"foot"}),
+			want:  map[string]string{"system.terminal": "kitty not found in $PATH"},
+			fix:   "config set system.terminal foot",
+		},
+		{
+			name:  "missing binary without alternatives",
+			setup: func(c *ShellConfig) { c.System.ClipboardTool = "wl-clipboard" },
+			probe: newFakeProbe(nil),
+			want:  map[string]string{"system.clipboardTool": "wl-copy not found"},
+			fix:   "Install wl-copy",
+		},
+		{
+			name:  "tool by absolute path",
+			setup: func(c *ShellConfig) { c.System.Launcher = "~/bin/launch" },
+			probe: newFakeProbe(nil, "/home/me/bin/launch"),
+		},
+		{
+			name:  "relative polkit agent",
+			setup: func(c *ShellConfig) { c.System.PolkitAgent = "polkit-gnome" },
+			probe: newFakeProbe(nil),
+			want:  map[string]string{"system.polkitAgent": "should be an absolute path"},
+		},
+		{
+			name:  "missing polkit agent",
+			setup: func(c *ShellConfig) { c.System.PolkitAgent = "/usr/lib/agent" },
+			probe: newFakeProbe(nil),
+			want:  map[string]string{"system.polkitAgent": "not found"},
+		},
+		{
+			name: "themes found",
+			setup: func(c *ShellConfig) {
+				c.System.IconTheme = "Papirus"
+				c.System.CursorTheme = "Bibata"
+			},
+			probe: newFakeProbe(nil, "/usr/share/icons/Papirus/index.theme", "/home/me/.icons/Bibata/cursors/left_ptr"),
+		},
+		{
+			name:  "missing cursor theme lists installed ones",
+			setup: func(c *ShellConfig) { c.System.CursorTheme = "Bibata" },
+			probe: newFakeProbe(nil, "/usr/share/icons/Adwaita/cursors/left_ptr", "/usr/share/icons/Papirus/index.theme"),
+			want:  map[string]string{"system.cursorTheme": "Cursor theme not found: Bibata"},
+			fix:   "pick one of: Adwaita",
+		},
+		{
+			name:  "generic font family",
+			setup: func(c *ShellConfig) { c.System.Font.Family = "monospace" },
+			probe: newFakeProbe(nil),
+		},
+		{
+			name:  "font in a fonts.conf directory",
+			setup: func(c *ShellConfig) { c.System.Font.Family = "JetBrains Mono" },
+			probe: func() fakeProbe {
+				p := newFakeProbe(nil, "/opt/fonts/jb/JetBrainsMono-Regular.ttf")
+				p.files["etc/fonts/fonts.conf"] = &fstest.MapFile{Data: []byte("<fontconfig><dir>/opt/fonts</dir></fontconfig>")}
+				return p
+			}(),
+		},
+		{
+			name:  "unreadable fonts.conf falls back to the default directories",
+			setup: func(c *ShellConfig) { c.System.Font.Family = "Inter" },
+			probe: newFakeProbe(nil, "/home/me/.local/share/fonts/Inter-Regular.otf"),
+		},
+		{
+			name:  "missing font",
+			setup: func(c *ShellConfig) { c.System.Font.Family = "Inter" },
+			probe: newFakeProbe(nil, "/usr/share/fonts/DejaVuSans.ttf"),
+			want:  map[string]string{"system.font.family": "Font family not found"},
+		},
+		{
+			name: "wallpapers",
+			setup: func(c *ShellConfig) {
+				c.Wallpaper.Path = "~/Pictures/missing.png"
+				c.Wallpaper.Directory = "~/Pictures/file.png"
+				c.Monitors = MonitorsConfig{
+					"DP-1":     {Wallpaper: "~/Pictures/file.png"},
+					"HDMI-A-1": {Wallpaper: "/gone.jpg"},
+				}
+			},
+			probe: newFakeProbe(nil, "/home/me/Pictures/file.png"),
+			want: map[string]string{
+				"wallpaper.path":              "Wallpaper file not found",
+				"wallpaper.directory":         "Wallpaper directory not found",
+				"monitors.HDMI-A-1.wallpaper": "Wallpaper file not found: /gone.jpg",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &ShellConfig{}
+			tt.setup(config)
+			warnings := NewDoctor(tt.probe).Check(config)
+
+			if len(warnings) != len(tt.want) {
+				t.Fatalf("got %d warnings, want %d: %+v", len(warnings), len(tt.want), warnings)
+			}
+			for _, w := range warnings {
+				want, ok := tt.want[w.Path]
+				if !ok || !strings.Contains(w.Message, want) {
+					t.Errorf("unexpected warning %s: %s", w.Path, w.Message)
+				}
+				if w.Severity != SeverityWarning || w.Type != EnvironmentErrorType {
+					t.Errorf("%s: severity %v, type %v", w.Path, w.Severity, w.Type)
+				}
+				if tt.fix != "" && (w.Fix == nil || !strings.Contains(w.Fix.Description+" "+w.Fix.Command, tt.fix)) {
+					t.Errorf("%s: fix %+v, want one mentioning %q", w.Path, w.Fix, tt.fix)
+				}
+			}
+		})
+	}
+}
+
+func TestInstalledAlternatives(t *testing.T) {
+	doctor := NewDoctor(newFakeProbe([]string{"foot", "kitty", "nvim"}))
+	got := doctor.InstalledAlternatives("system.terminal")
+	if strings.Join(got, ",") != "kitty,foot" {
+		t.Errorf("InstalledAlternatives = %v, want [kitty foot] in list order", got)
+	}
+	if got := doctor.InstalledAlternatives("system.shell"); len(got) != 0 {
+		t.Errorf("InstalledAlternatives(system.shell) = %v, want none", got)
+	}
+}
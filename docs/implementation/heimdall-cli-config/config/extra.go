@@ -0,0 +1,84 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// shellConfigKeys holds the top-level JSON keys ShellConfig declares
+var shellConfigKeys = func() map[string]bool {
+	keys := make(map[string]bool)
+	t := reflect.TypeOf(ShellConfig{})
+	for i := 0; i < t.NumField(); i++ {
+		if name := jsonFieldName(t.Field(i)); name != "" {
+			keys[name] = true
+		}
+	}
+	return keys
+}()
+
+// UnmarshalJSON decodes the schema fields and keeps unknown top-level keys in Extra
+func (c *ShellConfig) UnmarshalJSON(data []byte) error {
+	type plain ShellConfig
+	if err := json.Unmarshal(data, (*plain)(c)); err != nil {
+		return err
+	}
+
+	raw := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	c.Extra = make(map[string]interface{})
+	for key, value := range raw {
+		if shellConfigKeys[key] {
+			continue
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(value, &decoded); err != nil {
+			return err
+		}
+		c.Extra[key] = decoded
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the schema fields followed by the Extra keys in name order
+func (c ShellConfig) MarshalJSON() ([]byte, error) {
+	type plain ShellConfig
+	data, err := json.Marshal(plain(c))
+	if err != nil || len(c.Extra) == 0 {
+		return data, err
+	}
+
+	keys := make([]string, 0, len(c.Extra))
+	for key := range c.Extra {
+		if !shellConfigKeys[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for _, key := range keys {
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(c.Extra[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteByte(',')
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
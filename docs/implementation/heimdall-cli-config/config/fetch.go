@@ -0,0 +1,173 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultImportLimit caps the size of imported files
+const DefaultImportLimit = 1 << 20
+
+// Fetcher reads import sources
+type Fetcher interface {
+	// Fetch returns the content of source, failing if it exceeds limit bytes
+	Fetch(source string, limit int64) ([]byte, error)
+}
+
+// SourceFetcher reads "-" (stdin), file:// and http(s):// URLs, and plain paths
+type SourceFetcher struct {
+	Client *http.Client
+	Stdin  io.Reader
+}
+
+// NewSourceFetcher creates a fetcher using stdin and a client with a timeout
+func NewSourceFetcher() *SourceFetcher {
+	return &SourceFetcher{
+		Client: &http.Client{Timeout: 30 * time.Second},
+		Stdin:  os.Stdin,
+	}
+}
+
+// Fetch reads source up to limit bytes
+func (f *SourceFetcher) Fetch(source string, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		limit = DefaultImportLimit
+	}
+
+	if source == "-" {
+		return readLimited(f.Stdin, limit, "stdin")
+	}
+
+	u, err := url.Parse(source)
+	if err != nil || u.Scheme == "" || len(u.Scheme) == 1 {
+		// Plain path (a single-letter scheme is a Windows drive)
+		return readFileLimited(source, limit)
+	}
+
+	switch u.Scheme {
+	case "file":
+		if u.Host != "" && u.Host != "localhost" {
+			return nil, fmt.Errorf("unsupported file URL host: %s", u.Host)
+		}
+		return readFileLimited(u.Path, limit)
+	case "http", "https":
+		resp, err := f.Client.Get(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", source, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to fetch %s: %s", source, resp.Status)
+		}
+		if resp.ContentLength > limit {
+			return nil, fmt.Errorf("%s is larger than the %d byte limit", source, limit)
+		}
+		return readLimited(resp.Body, limit, source)
+	default:
+		return nil, fmt.Errorf("unsupported import source scheme: %s", u.Scheme)
+	}
+}
+
+// SourceFormat detects the format of an import source from its extension
+func SourceFormat(source string) string {
+	if u, err := url.Parse(source); err == nil && u.Scheme != "" && len(u.Scheme) > 1 {
+		return DetectFormat(u.Path)
+	}
+	return DetectFormat(source)
+}
+
+// VerifySHA256 checks data against a hex SHA-256 digest
+func VerifySHA256(data []byte, digest string) error {
+	want, err := hex.DecodeString(strings.TrimSpace(strings.ToLower(digest)))
+	if err != nil || len(want) != sha256.Size {
+		return fmt.Errorf("invalid SHA-256 digest: %s", digest)
+	}
+
+	sum := sha256.Sum256(data)
+	if subtle.ConstantTimeCompare(sum[:], want) != 1 {
+		return fmt.Errorf("SHA-256 mismatch: got %x", sum)
+	}
+	return nil
+}
+
+// VerifySignature checks a detached Ed25519 signature over data.
+// The signature may be raw (64 bytes) or base64. The public key may be a
+// PEM "PUBLIC KEY" block (as written by openssl pkey -pubout) or a base64
+// raw 32-byte key.
+func VerifySignature(data, signature, publicKey []byte) error {
+	key, err := parseEd25519PublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+
+	sig := signature
+	if len(sig) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+		if err != nil || len(decoded) != ed25519.SignatureSize {
+			return fmt.Errorf("invalid Ed25519 signature")
+		}
+		sig = decoded
+	}
+
+	if !ed25519.Verify(key, data, sig) {
+		return fmt.Errorf("signature verification failed")
+	}
+	return nil
+}
+
+// parseEd25519PublicKey decodes a PEM or base64 Ed25519 public key
+func parseEd25519PublicKey(data []byte) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+		key, ok := parsed.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("public key is not Ed25519")
+		}
+		return key, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(decoded) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid Ed25519 public key")
+	}
+	return ed25519.PublicKey(decoded), nil
+}
+
+// readFileLimited reads a local file up to limit bytes
+func readFileLimited(path string, limit int64) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer file.Close()
+
+	return readLimited(file, limit, path)
+}
+
+// readLimited reads r, failing if it holds more than limit bytes
+func readLimited(r io.Reader, limit int64, name string) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%s is larger than the %d byte limit", name, limit)
+	}
+	return data, nil
+}
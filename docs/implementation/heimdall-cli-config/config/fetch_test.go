@@ -0,0 +1,147 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const fetchBody = `{"version": "1.1.0", "bar": {"height": 40}}`
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/shell.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(fetchBody))
+	})
+	mux.HandleFunc("/large.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "4096")
+		w.Write([]byte(strings.Repeat(" ", 4096)))
+	})
+	mux.HandleFunc("/stream.json", func(w http.ResponseWriter, r *http.Request) {
+		// Chunked, so the size is only known while reading
+		for i := 0; i < 8; i++ {
+			w.Write([]byte(strings.Repeat(" ", 512)))
+			w.(http.Flusher).Flush()
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSourceFetcherHTTP(t *testing.T) {
+	server := newTestServer(t)
+	fetcher := &SourceFetcher{Client: server.Client()}
+
+	data, err := fetcher.Fetch(server.URL+"/shell.json", 1024)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if string(data) != fetchBody {
+		t.Errorf("got %q, want %q", data, fetchBody)
+	}
+
+	tests := map[string]string{
+		"/missing.json": "404",
+		"/large.json":   "larger than the 1024 byte limit",
+		"/stream.json":  "larger than the 1024 byte limit",
+	}
+	for path, want := range tests {
+		if _, err := fetcher.Fetch(server.URL+path, 1024); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Fetch(%s): err = %v, want %q", path, err, want)
+		}
+	}
+}
+
+func TestSourceFetcherLocal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shell.json")
+	if err := os.WriteFile(path, []byte(fetchBody), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fetcher := &SourceFetcher{Stdin: strings.NewReader(fetchBody)}
+
+	for _, source := range []string{path, "file://" + path, "-"} {
+		data, err := fetcher.Fetch(source, 1024)
+		if err != nil {
+			t.Errorf("Fetch(%s): %v", source, err)
+			continue
+		}
+		if string(data) != fetchBody {
+			t.Errorf("Fetch(%s) = %q, want %q", source, data, fetchBody)
+		}
+	}
+
+	if _, err := fetcher.Fetch(path, 8); err == nil {
+		t.Error("expected a size limit error for a local file")
+	}
+	if _, err := fetcher.Fetch("ftp://example.com/shell.json", 1024); err == nil {
+		t.Error("expected an error for an unsupported scheme")
+	}
+}
+
+func TestSourceFormat(t *testing.T) {
+	tests := map[string]string{
+		"https://example.com/shell.yaml?raw=1": FormatYAML,
+		"file:///tmp/shell.toml":               FormatTOML,
+		"shell.json":                           FormatJSON,
+		"-":                                    FormatJSON,
+	}
+	for source, want := range tests {
+		if got := SourceFormat(source); got != want {
+			t.Errorf("SourceFormat(%q) = %s, want %s", source, got, want)
+		}
+	}
+}
+
+func TestVerifySHA256(t *testing.T) {
+	sum := sha256.Sum256([]byte(fetchBody))
+	digest := hex.EncodeToString(sum[:])
+
+	if err := VerifySHA256([]byte(fetchBody), strings.ToUpper(digest)); err != nil {
+		t.Errorf("matching digest: %v", err)
+	}
+	if err := VerifySHA256([]byte(fetchBody+" "), digest); err == nil {
+		t.Error("expected a mismatch for changed content")
+	}
+	if err := VerifySHA256([]byte(fetchBody), "abc"); err == nil {
+		t.Error("expected an error for a malformed digest")
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	rawKey := []byte(base64.StdEncoding.EncodeToString(public))
+
+	sig := ed25519.Sign(private, []byte(fetchBody))
+	for name, key := range map[string][]byte{"pem": pemKey, "base64": rawKey} {
+		if err := VerifySignature([]byte(fetchBody), sig, key); err != nil {
+			t.Errorf("%s key, raw signature: %v", name, err)
+		}
+		encoded := []byte(base64.StdEncoding.EncodeToString(sig) + "\n")
+		if err := VerifySignature([]byte(fetchBody), encoded, key); err != nil {
+			t.Errorf("%s key, base64 signature: %v", name, err)
+		}
+	}
+
+	if err := VerifySignature([]byte(fetchBody+" "), sig, pemKey); err == nil {
+		t.Error("expected a failure for changed content")
+	}
+}
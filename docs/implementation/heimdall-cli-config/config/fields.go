@@ -0,0 +1,332 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldInfo describes a single leaf in the ShellConfig schema
+type FieldInfo struct {
+	Path string
+	Kind reflect.Kind
+	Type reflect.Type
+	Enum []string
+}
+
+// FieldEnums lists the accepted values for enumerated fields
+var FieldEnums = map[string][]string{
+	"appearance.theme":                {"dark", "light"},
+	"appearance.animationSpeed":       {"slow", "normal", "fast"},
+	"appearance.contrast":             {ContrastAA, ContrastAAA, ContrastOff},
+	"appearance.schedule.mode":        ScheduleModes,
+	"bar.position":                    {"top", "bottom", "left", "right"},
+	"bar.layer":                       {"background", "bottom", "top", "overlay"},
+	"services.notifications.position": {"top-left", "top-center", "top-right", "bottom-left", "bottom-center", "bottom-right"},
+	"services.power.acAction":         {"performance", "balanced", "powersave"},
+	"services.power.batteryAction":    {"performance", "balanced", "powersave"},
+	"services.power.lidCloseAction":   {"suspend", "hibernate", "lock", "ignore"},
+	"wallpaper.mode":                  {"static", "slideshow", "video", "color"},
+	"wallpaper.fillMode":              {"fill", "contain", "cover", "scale-down", "none"},
+	"wallpaper.backend":               WallpaperBackends,
+	"monitors.*.transform":            MonitorTransforms,
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// SchemaFields returns every leaf path of the ShellConfig schema in declaration order
+func SchemaFields() []FieldInfo {
+	fields := make([]FieldInfo, 0)
+	collectFields("", reflect.TypeOf(ShellConfig{}), &fields)
+	return fields
+}
+
+// SchemaPaths returns every leaf path of the ShellConfig schema
+func SchemaPaths() []string {
+	fields := SchemaFields()
+	paths := make([]string, 0, len(fields))
+	for _, f := range fields {
+		paths = append(paths, f.Path)
+	}
+	return paths
+}
+
+// LookupField returns schema information for a dotted path. Paths into a
+// map of structs, like monitors.DP-1.scale, resolve against the map's
+// element type; their enums are listed under the key "*".
+func LookupField(path string) (FieldInfo, bool) {
+	for _, f := range SchemaFields() {
+		if f.Path == path {
+			return f, true
+		}
+		if f.Kind == reflect.Map && f.Type.Elem().Kind() == reflect.Struct && strings.HasPrefix(path, f.Path+".") {
+			rest := strings.SplitN(strings.TrimPrefix(path, f.Path+"."), ".", 2)
+			if len(rest) < 2 {
+				continue
+			}
+			elemFields := make([]FieldInfo, 0)
+			collectFields("", f.Type.Elem(), &elemFields)
+			for _, elem := range elemFields {
+				if elem.Path == rest[1] {
+					elem.Path = path
+					elem.Enum = FieldEnums[f.Path+".*."+rest[1]]
+					return elem, true
+				}
+			}
+		}
+	}
+	return FieldInfo{}, false
+}
+
+// SchemaSections returns the intermediate (non-leaf) paths of the schema
+func SchemaSections() []string {
+	seen := make(map[string]bool)
+	sections := make([]string, 0)
+	for _, f := range SchemaFields() {
+		parts := strings.Split(f.Path, ".")
+		for i := 1; i < len(parts); i++ {
+			prefix := strings.Join(parts[:i], ".")
+			if !seen[prefix] {
+				seen[prefix] = true
+				sections = append(sections, prefix)
+			}
+		}
+	}
+	sort.Strings(sections)
+	return sections
+}
+
+// collectFields walks a struct type and records its leaves by JSON name
+func collectFields(prefix string, t reflect.Type, fields *[]FieldInfo) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := jsonFieldName(sf)
+		if name == "" {
+			continue
+		}
+
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		if sf.Type.Kind() == reflect.Struct && sf.Type != timeType {
+			collectFields(path, sf.Type, fields)
+			continue
+		}
+		if sf.Type.Kind() == reflect.Ptr && sf.Type.Elem().Kind() == reflect.Struct {
+			collectFields(path, sf.Type.Elem(), fields)
+			continue
+		}
+
+		*fields = append(*fields, FieldInfo{
+			Path: path,
+			Kind: sf.Type.Kind(),
+			Type: sf.Type,
+			Enum: FieldEnums[path],
+		})
+	}
+}
+
+// jsonFieldName returns the JSON key of a struct field, or "" if it is skipped
+func jsonFieldName(sf reflect.StructField) string {
+	if sf.PkgPath != "" {
+		return ""
+	}
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	name := strings.Split(tag, ",")[0]
+	if name == "" {
+		name = sf.Name
+	}
+	return name
+}
+
+// ParseValue converts a raw string into the type the schema expects at path.
+// Unknown paths fall back to JSON decoding, then to a plain string.
+func ParseValue(path, raw string) (interface{}, error) {
+	field, ok := LookupField(path)
+	if !ok {
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return raw, nil
+		}
+		return value, nil
+	}
+
+	switch field.Kind {
+	case reflect.String:
+		return raw, nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s expects a boolean, got %q", path, raw)
+		}
+		return b, nil
+	case reflect.Int, reflect.Int64, reflect.Int32:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s expects an integer, got %q", path, raw)
+		}
+		return n, nil
+	case reflect.Float64, reflect.Float32:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s expects a number, got %q", path, raw)
+		}
+		return f, nil
+	case reflect.Slice:
+		if strings.HasPrefix(strings.TrimSpace(raw), "[") {
+			var list []interface{}
+			if err := json.Unmarshal([]byte(raw), &list); err != nil {
+				return nil, fmt.Errorf("%s expects a JSON list: %w", path, err)
+			}
+			return list, nil
+		}
+		list := make([]interface{}, 0)
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		return list, nil
+	default:
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return nil, fmt.Errorf("%s expects a JSON value: %w", path, err)
+		}
+		return value, nil
+	}
+}
+
+// FormatValue renders a value for display in a single line
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// ConfigToMap converts a configuration into its generic JSON map form
+func ConfigToMap(config *ShellConfig) (map[string]interface{}, error) {
+	return structToMap(config)
+}
+
+// MapToConfig builds a configuration from its generic JSON map form
+func MapToConfig(data map[string]interface{}) (*ShellConfig, error) {
+	config := &ShellConfig{}
+	if err := mapToStruct(data, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// GetPath returns the value stored at a dotted path
+func GetPath(config *ShellConfig, path string) (interface{}, error) {
+	configMap, err := structToMap(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert config to map: %w", err)
+	}
+
+	value, ok := LookupPath(configMap, path)
+	if !ok {
+		return nil, fmt.Errorf("path not found: %s", path)
+	}
+	return value, nil
+}
+
+// SetPath stores a value at a dotted path
+func SetPath(config *ShellConfig, path string, value interface{}) error {
+	configMap, err := structToMap(config)
+	if err != nil {
+		return fmt.Errorf("failed to convert config to map: %w", err)
+	}
+
+	SetMapPath(configMap, path, value)
+
+	updated, err := MapToConfig(configMap)
+	if err != nil {
+		return fmt.Errorf("failed to apply %s: %w", path, err)
+	}
+	updated.Extra = config.Extra
+	*config = *updated
+	return nil
+}
+
+// CloneConfig returns a deep copy of a configuration
+func CloneConfig(config *ShellConfig) (*ShellConfig, error) {
+	configMap, err := structToMap(config)
+	if err != nil {
+		return nil, err
+	}
+
+	clone, err := MapToConfig(configMap)
+	if err != nil {
+		return nil, err
+	}
+	clone.Extra = make(map[string]interface{}, len(config.Extra))
+	for k, v := range config.Extra {
+		clone.Extra[k] = v
+	}
+	return clone, nil
+}
+
+// LookupPath walks a nested map by dotted path
+func LookupPath(data map[string]interface{}, path string) (interface{}, bool) {
+	parts := strings.Split(path, ".")
+	current := data
+
+	for i, part := range parts {
+		value, exists := current[part]
+		if !exists {
+			return nil, false
+		}
+		if i == len(parts)-1 {
+			return value, true
+		}
+
+		next, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+
+	return nil, false
+}
+
+// SetMapPath writes a value into a nested map, creating intermediate objects
+func SetMapPath(data map[string]interface{}, path string, value interface{}) {
+	parts := strings.Split(path, ".")
+	current := data
+
+	for i := 0; i < len(parts)-1; i++ {
+		part := parts[i]
+
+		if next, ok := current[part].(map[string]interface{}); ok {
+			current = next
+		} else {
+			newMap := make(map[string]interface{})
+			current[part] = newMap
+			current = newMap
+		}
+	}
+
+	current[parts[len(parts)-1]] = value
+}
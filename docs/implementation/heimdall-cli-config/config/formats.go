@@ -0,0 +1,235 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// File formats for import and export
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+// Formats lists the supported file formats
+var Formats = []string{FormatJSON, FormatYAML, FormatTOML}
+
+// DetectFormat picks a format from a file extension, defaulting to JSON
+func DetectFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	default:
+		return FormatJSON
+	}
+}
+
+// ParseFormat validates a format name
+func ParseFormat(name string) (string, error) {
+	switch strings.ToLower(name) {
+	case FormatJSON:
+		return FormatJSON, nil
+	case FormatYAML, "yml":
+		return FormatYAML, nil
+	case FormatTOML:
+		return FormatTOML, nil
+	default:
+		return "", fmt.Errorf("unsupported format: %s (use %s)", name, strings.Join(Formats, ", "))
+	}
+}
+
+// MarshalConfig encodes a configuration. Every format goes through the JSON
+// representation, so keys follow the JSON tags and Extra fields and
+// timestamps survive a round trip.
+func MarshalConfig(config *ShellConfig, format string) ([]byte, error) {
+	if format == FormatJSON {
+		return json.MarshalIndent(config, "", "  ")
+	}
+
+	data, err := structToMap(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert config to map: %w", err)
+	}
+	return EncodeMap(data, format)
+}
+
+// UnmarshalConfig decodes a configuration in the given format
+func UnmarshalConfig(data []byte, format string) (*ShellConfig, error) {
+	values, err := DecodeMap(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &ShellConfig{}
+	if err := mapToStruct(values, config); err != nil {
+		return nil, fmt.Errorf("failed to decode configuration: %w", err)
+	}
+	return config, nil
+}
+
+// EncodeMap encodes a JSON-like map in the given format. JSON numbers
+// decode as float64, so YAML and TOML get integers back wherever the
+// schema has one.
+func EncodeMap(data map[string]interface{}, format string) ([]byte, error) {
+	if format != FormatJSON {
+		data = typedNumbers(data, "").(map[string]interface{})
+	}
+
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(data, "", "  ")
+	case FormatYAML:
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(data); err != nil {
+			return nil, fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		return buf.Bytes(), nil
+	case FormatTOML:
+		values, err := dropNulls(data, "")
+		if err != nil {
+			return nil, err
+		}
+		out, err := toml.Marshal(values)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode TOML: %w", err)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// DecodeMap decodes a document into a JSON-like map
+func DecodeMap(data []byte, format string) (map[string]interface{}, error) {
+	var values map[string]interface{}
+
+	switch format {
+	case FormatJSON:
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+	case FormatTOML:
+		if err := toml.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("failed to parse TOML: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+
+	// Normalize YAML/TOML types (timestamps, integer widths) through JSON
+	normalized, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to normalize %s: %w", format, err)
+	}
+	result := make(map[string]interface{})
+	if err := json.Unmarshal(normalized, &result); err != nil {
+		return nil, fmt.Errorf("failed to normalize %s: %w", format, err)
+	}
+	return result, nil
+}
+
+// typedNumbers turns whole float64 values into int64 unless the schema
+// field at their path is a float. Numbers outside the schema, such as in
+// Extra, become integers when whole, the way JSON printed them.
+func typedNumbers(value interface{}, path string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			child := key
+			if path != "" {
+				child = path + "." + key
+			}
+			out[key] = typedNumbers(item, child)
+		}
+		return out
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = typedNumbers(item, path)
+		}
+		return items
+	case float64:
+		if field, ok := LookupField(path); ok {
+			kind := field.Kind
+			if kind == reflect.Slice || kind == reflect.Array {
+				kind = field.Type.Elem().Kind()
+			}
+			if kind == reflect.Float32 || kind == reflect.Float64 {
+				return v
+			}
+		}
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// dropNulls prepares a map for TOML, which has no null. A null member of
+// a table is left out: it decodes to the same zero value as a missing key,
+// so schema fields survive the round trip. A null inside an array cannot
+// be left out without shifting the other items, so it is an error.
+func dropNulls(data map[string]interface{}, prefix string) (map[string]interface{}, error) {
+	out := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		if value == nil {
+			continue
+		}
+		cleaned, err := dropNullValue(value, prefix+key)
+		if err != nil {
+			return nil, err
+		}
+		out[key] = cleaned
+	}
+	return out, nil
+}
+
+// dropNullValue applies dropNulls below a value at path
+func dropNullValue(value interface{}, path string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return dropNulls(v, path+".")
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			if item == nil {
+				return nil, fmt.Errorf("cannot encode TOML: %s[%d] is null (use JSON or YAML)", path, i)
+			}
+			cleaned, err := dropNullValue(item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = cleaned
+		}
+		return items, nil
+	default:
+		return value, nil
+	}
+}
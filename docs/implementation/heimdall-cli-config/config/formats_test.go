@@ -0,0 +1,153 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// roundTripConfig is a built-in profile with unknown sections and
+// sub-second timestamps, the parts most likely to be lost
+func roundTripConfig(profile string) *ShellConfig {
+	config := GetProfileConfig(profile)
+	config.Metadata.Created = time.Date(2024, 3, 9, 8, 15, 30, 123456789, time.UTC)
+	config.Metadata.LastModified = time.Date(2024, 3, 10, 22, 0, 0, 0, time.FixedZone("CET", 3600))
+	config.Extra = map[string]interface{}{
+		"plugins": map[string]interface{}{
+			"clock": map[string]interface{}{"format": "%H:%M", "seconds": false},
+		},
+		"recent": []interface{}{"a", 2.5, true},
+	}
+	return config
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	for _, profile := range BuiltinProfiles {
+		for _, format := range []string{FormatYAML, FormatTOML} {
+			t.Run(profile+"/"+format, func(t *testing.T) {
+				config := roundTripConfig(profile)
+				want, err := MarshalConfig(config, FormatJSON)
+				if err != nil {
+					t.Fatalf("MarshalConfig json: %v", err)
+				}
+
+				encoded, err := MarshalConfig(config, format)
+				if err != nil {
+					t.Fatalf("MarshalConfig %s: %v", format, err)
+				}
+				decoded, err := UnmarshalConfig(encoded, format)
+				if err != nil {
+					t.Fatalf("UnmarshalConfig %s: %v\n%s", format, err, encoded)
+				}
+				got, err := MarshalConfig(decoded, FormatJSON)
+				if err != nil {
+					t.Fatalf("MarshalConfig json: %v", err)
+				}
+
+				if !bytes.Equal(got, want) {
+					t.Errorf("%s round trip changed the configuration:\n got: %s\nwant: %s", format, got, want)
+				}
+			})
+		}
+	}
+}
+
+// Numbers keep their schema type in the text: JSON decoding alone would
+// write every integer as a float
+func TestFormatNumberLiterals(t *testing.T) {
+	config := roundTripConfig("default")
+	config.Extra["count"] = 3.0
+
+	tests := map[string][]string{
+		FormatTOML: {"borderWidth = 2\n", "height = 30\n", "refreshRate = 60\n", "scale = 1.0\n", "transparency = 0.8\n", "count = 3\n", "recent = ['a', 2.5, true]\n"},
+		FormatYAML: {"borderWidth: 2\n", "height: 30\n", "refreshRate: 60\n", "transparency: 0.8\n", "count: 3\n", "- 2.5\n"},
+	}
+
+	for format, literals := range tests {
+		encoded, err := MarshalConfig(config, format)
+		if err != nil {
+			t.Fatalf("MarshalConfig %s: %v", format, err)
+		}
+		for _, literal := range literals {
+			if !strings.Contains(string(encoded), literal) {
+				t.Errorf("%s output lacks %q:\n%s", format, literal, encoded)
+			}
+		}
+		if strings.Contains(string(encoded), "borderWidth = 2.0") {
+			t.Errorf("%s wrote an integer field as a float", format)
+		}
+
+		decoded, err := UnmarshalConfig(encoded, format)
+		if err != nil {
+			t.Fatalf("UnmarshalConfig %s: %v", format, err)
+		}
+		again, err := MarshalConfig(decoded, format)
+		if err != nil {
+			t.Fatalf("MarshalConfig %s: %v", format, err)
+		}
+		if !bytes.Equal(again, encoded) {
+			t.Errorf("%s text changed on a second round trip:\n got: %s\nwant: %s", format, again, encoded)
+		}
+	}
+}
+
+func TestFormatNulls(t *testing.T) {
+	data := map[string]interface{}{
+		"kept":  "value",
+		"unset": nil,
+		"table": map[string]interface{}{"inner": nil, "n": 1.0},
+		"list":  []interface{}{"a", nil, "b"},
+	}
+
+	// YAML has null and keeps every one
+	encoded, err := EncodeMap(data, FormatYAML)
+	if err != nil {
+		t.Fatalf("EncodeMap yaml: %v", err)
+	}
+	decoded, err := DecodeMap(encoded, FormatYAML)
+	if err != nil {
+		t.Fatalf("DecodeMap yaml: %v", err)
+	}
+	want, _ := json.Marshal(data)
+	got, _ := json.Marshal(decoded)
+	if !bytes.Equal(got, want) {
+		t.Errorf("YAML nulls: got %s, want %s", got, want)
+	}
+
+	// TOML refuses nulls inside arrays
+	if _, err := EncodeMap(data, FormatTOML); err == nil || !strings.Contains(err.Error(), "list[1]") {
+		t.Errorf("TOML with a null array item: err = %v, want one naming list[1]", err)
+	}
+
+	// and leaves null table members out
+	delete(data, "list")
+	encoded, err = EncodeMap(data, FormatTOML)
+	if err != nil {
+		t.Fatalf("EncodeMap toml: %v", err)
+	}
+	decoded, err = DecodeMap(encoded, FormatTOML)
+	if err != nil {
+		t.Fatalf("DecodeMap toml: %v", err)
+	}
+	got, _ = json.Marshal(decoded)
+	if want := `{"kept":"value","table":{"n":1}}`; string(got) != want {
+		t.Errorf("TOML nulls: got %s, want %s", got, want)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := map[string]string{
+		"shell.json": FormatJSON,
+		"shell.yaml": FormatYAML,
+		"shell.YML":  FormatYAML,
+		"shell.toml": FormatTOML,
+		"shell":      FormatJSON,
+	}
+	for path, want := range tests {
+		if got := DetectFormat(path); got != want {
+			t.Errorf("DetectFormat(%q) = %s, want %s", path, got, want)
+		}
+	}
+}
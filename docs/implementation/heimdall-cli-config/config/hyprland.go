@@ -0,0 +1,382 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HyprlandConfigPath is the generated fragment relative to the config home.
+// hyprland.conf picks it up with: source = ~/.config/hypr/heimdall.conf
+const HyprlandConfigPath = "hypr/heimdall.conf"
+
+// hyprlandAnimationSpeeds maps appearance.animationSpeed to Hyprland's
+// animation speed in deciseconds
+var hyprlandAnimationSpeeds = map[string]int{
+	"slow":   8,
+	"normal": 5,
+	"fast":   3,
+}
+
+// hyprlandModifiers maps accepted modifier names to Hyprland's spelling
+var hyprlandModifiers = map[string]string{
+	"super":   "SUPER",
+	"win":     "SUPER",
+	"logo":    "SUPER",
+	"mod4":    "SUPER",
+	"shift":   "SHIFT",
+	"ctrl":    "CTRL",
+	"control": "CTRL",
+	"alt":     "ALT",
+	"mod1":    "ALT",
+	"caps":    "CAPS",
+	"mod2":    "MOD2",
+	"mod3":    "MOD3",
+	"mod5":    "MOD5",
+}
+
+// hyprlandKeys lists the named XKB keysyms accepted in shortcuts, besides
+// single letters and digits, F1-F24, XF86 media keys, mouse:N and code:N
+var hyprlandKeys = []string{
+	"Return", "space", "Tab", "Escape", "BackSpace", "Delete", "Insert",
+	"Home", "End", "Prior", "Next", "Page_Up", "Page_Down",
+	"Left", "Right", "Up", "Down", "Print", "Pause", "Scroll_Lock",
+	"Menu", "Caps_Lock", "Num_Lock",
+	"comma", "period", "slash", "backslash", "minus", "equal", "plus",
+	"grave", "semicolon", "apostrophe", "bracketleft", "bracketright",
+	"mouse_up", "mouse_down", "mouse_left", "mouse_right",
+	"KP_Enter", "KP_Add", "KP_Subtract", "KP_Multiply", "KP_Divide",
+	"KP_0", "KP_1", "KP_2", "KP_3", "KP_4", "KP_5", "KP_6", "KP_7", "KP_8", "KP_9",
+}
+
+var (
+	functionKeyPattern = regexp.MustCompile(`^[Ff]([1-9]|1[0-9]|2[0-4])$`)
+	xf86KeyPattern     = regexp.MustCompile(`^XF86[A-Za-z0-9]+$`)
+	codeKeyPattern     = regexp.MustCompile(`^(mouse|code):[0-9]+$`)
+	safeArgPattern     = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./~-]+$`)
+)
+
+// Shortcut is a parsed key combination
+type Shortcut struct {
+	Modifiers []string
+	Key       string
+}
+
+// String renders the shortcut as Hyprland's "MODS, key" pair
+func (s Shortcut) String() string {
+	return strings.Join(s.Modifiers, " ") + ", " + s.Key
+}
+
+// ParseShortcut parses "Super+Shift+Return" style shortcuts. Modifier and
+// key names are matched case-insensitively and normalized; unknown names
+// are errors.
+func ParseShortcut(value string) (Shortcut, error) {
+	parts := strings.Split(value, "+")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	// "Super++" binds the plus key
+	if len(parts) > 2 && parts[len(parts)-1] == "" && parts[len(parts)-2] == "" {
+		parts = append(parts[:len(parts)-2], "plus")
+	}
+
+	keyName := parts[len(parts)-1]
+	if keyName == "" {
+		return Shortcut{}, fmt.Errorf("shortcut %q has no key", value)
+	}
+
+	shortcut := Shortcut{Modifiers: make([]string, 0, len(parts)-1)}
+	seen := make(map[string]bool)
+	for _, name := range parts[:len(parts)-1] {
+		mod, ok := hyprlandModifiers[strings.ToLower(name)]
+		if !ok {
+			return Shortcut{}, fmt.Errorf("unknown modifier %q in shortcut %q", name, value)
+		}
+		if !seen[mod] {
+			seen[mod] = true
+			shortcut.Modifiers = append(shortcut.Modifiers, mod)
+		}
+	}
+
+	key, ok := normalizeKeyName(keyName)
+	if !ok {
+		return Shortcut{}, fmt.Errorf("unknown key %q in shortcut %q", keyName, value)
+	}
+	shortcut.Key = key
+
+	// A stable modifier order makes equal shortcuts render identically
+	sort.Strings(shortcut.Modifiers)
+
+	return shortcut, nil
+}
+
+// normalizeKeyName returns the canonical keysym for a key name
+func normalizeKeyName(name string) (string, bool) {
+	if len(name) == 1 {
+		c := name[0]
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+			return name, true
+		case c >= 'A' && c <= 'Z':
+			return strings.ToLower(name), true
+		}
+		return "", false
+	}
+
+	if functionKeyPattern.MatchString(name) {
+		return strings.ToUpper(name), true
+	}
+	if xf86KeyPattern.MatchString(name) || codeKeyPattern.MatchString(name) {
+		return name, true
+	}
+
+	switch strings.ToLower(name) {
+	case "enter":
+		return "Return", true
+	case "esc":
+		return "Escape", true
+	case "del":
+		return "Delete", true
+	case "pageup":
+		return "Prior", true
+	case "pagedown":
+		return "Next", true
+	}
+	for _, key := range hyprlandKeys {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// GetHyprlandConfigPath returns the generated fragment location.
+// HEIMDALL_HYPRLAND_CONFIG overrides it.
+func GetHyprlandConfigPath() string {
+	if envPath := os.Getenv("HEIMDALL_HYPRLAND_CONFIG"); envPath != "" {
+		return envPath
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, _ := os.UserHomeDir()
+		configHome = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(configHome, HyprlandConfigPath)
+}
+
+// RenderHyprland renders the heimdall.conf fragment: general, decoration and
+// animations blocks from the appearance settings, a monitor line for every
+// output named in monitors, and a bind line for every custom command with a
+// shortcut. Invalid shortcuts, duplicate shortcuts and colors that are not
+// hex are errors.
+func RenderHyprland(config *ShellConfig) ([]byte, error) {
+	app := &config.Appearance
+	var b strings.Builder
+
+	b.WriteString("# Generated by heimdall-cli render hyprland from shell.json.\n")
+	b.WriteString("# Do not edit; change shell.json and render again.\n\n")
+
+	// General
+	b.WriteString("general {\n")
+	fmt.Fprintf(&b, "    border_size = %d\n", app.BorderWidth)
+	if app.AccentColor != "" {
+		color, err := hyprlandColor(app.AccentColor)
+		if err != nil {
+			return nil, fmt.Errorf("appearance.accentColor: %w", err)
+		}
+		fmt.Fprintf(&b, "    col.active_border = %s\n", color)
+	}
+	if app.Colors.Border != "" {
+		color, err := hyprlandColor(app.Colors.Border)
+		if err != nil {
+			return nil, fmt.Errorf("appearance.colors.border: %w", err)
+		}
+		fmt.Fprintf(&b, "    col.inactive_border = %s\n", color)
+	}
+	b.WriteString("}\n\n")
+
+	// Decoration
+	b.WriteString("decoration {\n")
+	fmt.Fprintf(&b, "    rounding = %d\n\n", app.BorderRadius)
+	b.WriteString("    blur {\n")
+	fmt.Fprintf(&b, "        enabled = %t\n", app.BlurRadius > 0)
+	if app.BlurRadius > 0 {
+		fmt.Fprintf(&b, "        size = %d\n", app.BlurRadius)
+	}
+	b.WriteString("    }\n\n")
+	b.WriteString("    shadow {\n")
+	fmt.Fprintf(&b, "        enabled = %t\n", app.Shadows)
+	b.WriteString("    }\n")
+	b.WriteString("}\n\n")
+
+	// Animations
+	speed, ok := hyprlandAnimationSpeeds[app.AnimationSpeed]
+	if !ok {
+		speed = hyprlandAnimationSpeeds["normal"]
+	}
+	b.WriteString("animations {\n")
+	fmt.Fprintf(&b, "    enabled = %t\n", app.Animations)
+	fmt.Fprintf(&b, "    animation = global, 1, %d, default\n", speed)
+	b.WriteString("}\n")
+
+	monitors, err := hyprlandMonitors(config)
+	if err != nil {
+		return nil, err
+	}
+	if len(monitors) > 0 {
+		b.WriteString("\n# Monitors\n")
+		for _, line := range monitors {
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+	}
+
+	binds, err := hyprlandBinds(config.Commands.Custom)
+	if err != nil {
+		return nil, err
+	}
+	if len(binds) > 0 {
+		b.WriteString("\n# Custom commands\n")
+		for _, bind := range binds {
+			b.WriteString(bind)
+			b.WriteString("\n")
+		}
+	}
+
+	return []byte(b.String()), nil
+}
+
+// hyprlandMonitors builds a monitor line for each named output, sorted by
+// name. The "*" entry only supplies defaults; unset values are left to
+// Hyprland (preferred mode, auto position and scale).
+func hyprlandMonitors(config *ShellConfig) ([]string, error) {
+	lines := make([]string, 0)
+	for _, name := range config.Monitors.MonitorNames() {
+		monitor := config.Monitor(name)
+		if strings.ContainsAny(name+monitor.Resolution, "\r\n") {
+			return nil, fmt.Errorf("monitor %q: line breaks are not allowed", name)
+		}
+
+		mode := "preferred"
+		if monitor.Resolution != "" && monitor.Resolution != "auto" {
+			mode = monitor.Resolution
+			if monitor.RefreshRate > 0 {
+				mode += fmt.Sprintf("@%d", monitor.RefreshRate)
+			}
+		}
+		position := "auto"
+		if monitor.Position != nil {
+			position = fmt.Sprintf("%dx%d", monitor.Position.X, monitor.Position.Y)
+		}
+		scale := "auto"
+		if monitor.Scale > 0 {
+			scale = strconv.FormatFloat(monitor.Scale, 'f', -1, 64)
+		}
+
+		line := fmt.Sprintf("monitor = %s, %s, %s, %s", name, mode, position, scale)
+		for i, transform := range MonitorTransforms {
+			if transform == monitor.Transform && i > 0 {
+				line += fmt.Sprintf(", transform, %d", i)
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// hyprlandBinds builds bind lines for commands with shortcuts, sorted by name
+func hyprlandBinds(commands map[string]CommandDef) ([]string, error) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	binds := make([]string, 0)
+	used := make(map[string]string)
+	for _, name := range names {
+		cmd := commands[name]
+		if cmd.Shortcut == "" {
+			continue
+		}
+
+		shortcut, err := ParseShortcut(cmd.Shortcut)
+		if err != nil {
+			return nil, fmt.Errorf("commands.custom.%s.shortcut: %w", name, err)
+		}
+		if other, ok := used[shortcut.String()]; ok {
+			return nil, fmt.Errorf("commands.custom.%s.shortcut: %s is already bound by %s", name, cmd.Shortcut, other)
+		}
+		used[shortcut.String()] = name
+
+		if cmd.Command == "" {
+			return nil, fmt.Errorf("commands.custom.%s.command: command cannot be empty", name)
+		}
+		// A line break would end the bind and start a line of its own
+		if path := commandLineBreak(name, cmd); path != "" {
+			return nil, fmt.Errorf("%s: line breaks are not allowed", path)
+		}
+
+		// "#" starts a comment in hyprland.conf unless doubled
+		exec := strings.ReplaceAll(shellJoin(cmd.Command, cmd.Args), "#", "##")
+		line := fmt.Sprintf("bind = %s, exec, %s", shortcut, exec)
+		if cmd.Description != "" {
+			line += "  # " + strings.ReplaceAll(cmd.Description, "\n", " ")
+		}
+		binds = append(binds, line)
+	}
+
+	return binds, nil
+}
+
+// commandLineBreak returns the path of the first command or argument of a
+// custom command that contains a line break, or ""
+func commandLineBreak(name string, cmd CommandDef) string {
+	if strings.ContainsAny(cmd.Command, "\r\n") {
+		return fmt.Sprintf("commands.custom.%s.command", name)
+	}
+	for i, arg := range cmd.Args {
+		if strings.ContainsAny(arg, "\r\n") {
+			return fmt.Sprintf("commands.custom.%s.args[%d]", name, i)
+		}
+	}
+	return ""
+}
+
+// hyprlandColor converts #RRGGBB to rgb(RRGGBB) and #RRGGBBAA to rgba(RRGGBBAA)
+func hyprlandColor(value string) (string, error) {
+	c, err := ParseColor(value)
+	if err != nil {
+		return "", err
+	}
+	hex := strings.TrimPrefix(formatHexColor(c), "#")
+	if len(hex) == 8 {
+		return "rgba(" + hex + ")", nil
+	}
+	return "rgb(" + hex + ")", nil
+}
+
+// shellJoin quotes a command and its arguments for sh, which Hyprland's exec
+// dispatcher runs them through. The command itself is kept as written so it
+// may contain its own shell syntax.
+func shellJoin(command string, args []string) string {
+	parts := []string{command}
+	for _, arg := range args {
+		parts = append(parts, shellQuote(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+// shellQuote single-quotes s unless it only holds safe characters
+func shellQuote(s string) string {
+	if safeArgPattern.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
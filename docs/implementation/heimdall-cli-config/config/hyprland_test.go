@@ -0,0 +1,84 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHyprlandBinds(t *testing.T) {
+	binds, err := hyprlandBinds(map[string]CommandDef{
+		"term":  {Command: "kitty", Args: []string{"--title", "my term"}, Shortcut: "super+return", Description: "Open\na terminal"},
+		"notes": {Command: "echo '#todo' >> notes", Shortcut: "Super+N"},
+		"plain": {Command: "true"},
+	})
+	if err != nil {
+		t.Fatalf("hyprlandBinds: %v", err)
+	}
+
+	want := []string{
+		"bind = SUPER, n, exec, echo '##todo' >> notes",
+		"bind = SUPER, Return, exec, kitty --title 'my term'  # Open a terminal",
+	}
+	if strings.Join(binds, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(binds, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestHyprlandBindsRejects(t *testing.T) {
+	tests := map[string]struct {
+		commands map[string]CommandDef
+		want     string
+	}{
+		"newline in command": {
+			map[string]CommandDef{"x": {Command: "true\nexec = rm -rf ~", Shortcut: "Super+X"}},
+			"commands.custom.x.command: line breaks",
+		},
+		"carriage return in argument": {
+			map[string]CommandDef{"x": {Command: "notify-send", Args: []string{"ok", "a\rb"}, Shortcut: "Super+X"}},
+			"commands.custom.x.args[1]: line breaks",
+		},
+		"duplicate shortcut": {
+			map[string]CommandDef{
+				"a": {Command: "a", Shortcut: "Super+A"},
+				"b": {Command: "b", Shortcut: "super+a"},
+			},
+			"already bound by a",
+		},
+		"unknown key": {
+			map[string]CommandDef{"x": {Command: "x", Shortcut: "Super+Nope"}},
+			"commands.custom.x.shortcut",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := hyprlandBinds(tt.commands); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateCommandLineBreaks(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Commands.Custom = map[string]CommandDef{
+		"x": {Command: "echo", Args: []string{"one\nexec = evil"}},
+	}
+
+	found := false
+	for _, verr := range NewSchemaValidator().Validate(config) {
+		if verr.Path == "commands.custom.x.args[0]" && verr.Severity == SeverityError {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected a validation error for a line break in an argument")
+	}
+}
+
+func TestRenderHyprlandMonitorNames(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Monitors["DP-1\nexec = evil"] = MonitorConfig{}
+	if _, err := RenderHyprland(config); err == nil {
+		t.Error("expected an error for a monitor name with a line break")
+	}
+}
@@ -22,6 +22,8 @@ const (
 	ReplaceIfDefault
 	// NeverReplace never replaces (user-locked)
 	NeverReplace
+	// Replace always overwrites the existing property
+	Replace
 )
 
 // PropertyInjector handles property injection
@@ -75,7 +77,7 @@ func (i *PropertyInjector) InjectDefaults(config *ShellConfig) error {
 	locked := i.getUserLocks(config)
 
 	// Apply injection rules
-	for path, value := range missing {
+	for path, value := range i.splitLockedSections(missing, locked) {
 		if i.isUserLocked(path, locked) {
 			if i.logger != nil {
 				i.logger.Debug("Skipping user-locked property",
@@ -153,6 +155,11 @@ func (i *PropertyInjector) RemoveUserLock(config *ShellConfig, path string) {
 	config.Metadata.UserLocked = filtered
 }
 
+// IsUserLocked reports whether a path is covered by one of the config's user locks
+func (i *PropertyInjector) IsUserLocked(config *ShellConfig, path string) bool {
+	return i.isUserLocked(path, i.getUserLocks(config))
+}
+
 // loadDefaults loads default configuration values
 func (i *PropertyInjector) loadDefaults() {
 	i.defaults = map[string]interface{}{
@@ -199,6 +206,7 @@ func (i *PropertyInjector) loadDefaults() {
 			"shadows":        true,
 			"animations":     true,
 			"animationSpeed": "normal",
+			"contrast":       "aa",
 			"colors": map[string]interface{}{
 				"background": "#1e1e2e",
 				"foreground": "#cdd6f4",
@@ -211,6 +219,19 @@ func (i *PropertyInjector) loadDefaults() {
 				"surface":    "#313244",
 				"border":     "#45475a",
 			},
+			"palettes": map[string]interface{}{
+				"light": map[string]interface{}{
+					"colorScheme": "catppuccin-latte",
+				},
+				"dark": map[string]interface{}{
+					"colorScheme": "catppuccin-mocha",
+				},
+			},
+			"schedule": map[string]interface{}{
+				"mode":  "off",
+				"light": "07:00",
+				"dark":  "19:00",
+			},
 		},
 		"bar": map[string]interface{}{
 			"position": "top",
@@ -310,9 +331,6 @@ func (i *PropertyInjector) loadDefaults() {
 				"autoBrightness": false,
 				"dpms":           true,
 				"dpmsTimeout":    600,
-				"resolution":     "auto",
-				"refreshRate":    60,
-				"scale":          1.0,
 			},
 		},
 		"commands": map[string]interface{}{
@@ -329,7 +347,14 @@ func (i *PropertyInjector) loadDefaults() {
 			"dim":          false,
 			"dimStrength":  0.3,
 			"fillMode":     "cover",
-			"monitors":     []string{},
+			"backend":      "auto",
+		},
+		"monitors": map[string]interface{}{
+			AllMonitors: map[string]interface{}{
+				"resolution":  "auto",
+				"refreshRate": 60,
+				"scale":       1.0,
+			},
 		},
 		"hotReload": map[string]interface{}{
 			"enabled": true,
@@ -345,6 +370,15 @@ func (i *PropertyInjector) loadDefaults() {
 			"maxRetries": 3,
 			"retryDelay": 1000,
 		},
+		"profiles": map[string]interface{}{
+			"auto": map[string]interface{}{
+				"interval": 60,
+				"rules":    []interface{}{},
+			},
+		},
+		"render": map[string]interface{}{
+			"targets": map[string]interface{}{},
+		},
 	}
 }
 
@@ -426,8 +460,24 @@ func (i *PropertyInjector) getUserLocks(config *ShellConfig) []string {
 	return config.Metadata.UserLocked
 }
 
-// isUserLocked checks if a path is user-locked
+// isUserLocked checks if a path is user-locked. A lock on a section covers
+// every path below it, and a write to a section is locked when any path
+// below it is.
 func (i *PropertyInjector) isUserLocked(path string, locked []string) bool {
+	if i.coversPath(path, locked) {
+		return true
+	}
+	for _, lock := range locked {
+		if strings.HasPrefix(strings.TrimSuffix(lock, "*"), path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// coversPath reports whether a lock applies to path itself or a section
+// containing it
+func (i *PropertyInjector) coversPath(path string, locked []string) bool {
 	for _, lock := range locked {
 		// Support wildcards
 		if strings.HasSuffix(lock, "*") {
@@ -435,13 +485,35 @@ func (i *PropertyInjector) isUserLocked(path string, locked []string) bool {
 			if strings.HasPrefix(path, prefix) {
 				return true
 			}
-		} else if lock == path {
+		} else if lock == path || strings.HasPrefix(path, lock+".") {
 			return true
 		}
 	}
 	return false
 }
 
+// splitLockedSections replaces each missing section that only has locked
+// paths below it with its children, so the rest of the section is still
+// injected
+func (i *PropertyInjector) splitLockedSections(missing map[string]interface{}, locked []string) map[string]interface{} {
+	split := make(map[string]interface{}, len(missing))
+	for path, value := range missing {
+		section, ok := value.(map[string]interface{})
+		if !ok || i.coversPath(path, locked) || !i.isUserLocked(path, locked) {
+			split[path] = value
+			continue
+		}
+		children := make(map[string]interface{}, len(section))
+		for key, child := range section {
+			children[path+"."+key] = child
+		}
+		for childPath, child := range i.splitLockedSections(children, locked) {
+			split[childPath] = child
+		}
+	}
+	return split
+}
+
 // findRule finds an injection rule for a path
 func (i *PropertyInjector) findRule(path string) *InjectionRule {
 	for _, rule := range i.rules {
@@ -511,6 +583,8 @@ func (i *PropertyInjector) injectProperty(config map[string]interface{}, path st
 		} else {
 			current[key] = value
 		}
+	case Replace:
+		current[key] = value
 	case NeverReplace:
 		// Do nothing
 	}
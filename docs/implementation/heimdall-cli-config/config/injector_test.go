@@ -0,0 +1,126 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestIsUserLocked(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		locked []string
+		want   bool
+	}{
+		{"exact", "bar.height", []string{"bar.height"}, true},
+		{"unrelated", "bar.height", []string{"bar.position"}, false},
+		{"leaf under locked section", "appearance.colors.primary", []string{"appearance.colors"}, true},
+		{"section with locked leaf", "appearance.colors", []string{"appearance.colors.primary"}, true},
+		{"shared name prefix", "appearance.colorScheme", []string{"appearance.colors"}, false},
+		{"wildcard", "appearance.colors.primary", []string{"appearance.*"}, true},
+		{"section with wildcard below", "appearance", []string{"appearance.colors.*"}, true},
+		{"no locks", "bar.height", nil, false},
+	}
+
+	injector := NewPropertyInjector()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := injector.isUserLocked(tt.path, tt.locked); got != tt.want {
+				t.Errorf("isUserLocked(%q, %q) = %v, want %v", tt.path, tt.locked, got, tt.want)
+			}
+		})
+	}
+}
+
+// Locks only keep InjectDefaults away from the locked paths: a lock on a
+// section skips every leaf below it, a lock on a leaf skips only that leaf
+func TestInjectDefaultsLocks(t *testing.T) {
+	tests := []struct {
+		name        string
+		locked      []string
+		light, dark string
+	}{
+		{"no locks", nil, "catppuccin-latte", "catppuccin-mocha"},
+		{"section locked", []string{"appearance.palettes"}, "", ""},
+		{"leaf locked", []string{"appearance.palettes.light.colorScheme"}, "", "catppuccin-mocha"},
+		{"sibling section locked", []string{"appearance.colors"}, "catppuccin-latte", "catppuccin-mocha"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.Appearance.Palettes = ThemePalettes{}
+			config.Metadata.UserLocked = tt.locked
+
+			if err := NewPropertyInjector().InjectDefaults(config); err != nil {
+				t.Fatalf("InjectDefaults: %v", err)
+			}
+			palettes := config.Appearance.Palettes
+			if palettes.Light.ColorScheme != tt.light || palettes.Dark.ColorScheme != tt.dark {
+				t.Errorf("palettes = %q/%q, want %q/%q", palettes.Light.ColorScheme, palettes.Dark.ColorScheme, tt.light, tt.dark)
+			}
+		})
+	}
+}
+
+func TestSplitLockedSections(t *testing.T) {
+	missing := map[string]interface{}{
+		"bar": map[string]interface{}{
+			"height": 30,
+			"margin": map[string]interface{}{"top": 5, "left": 5},
+		},
+		"system": map[string]interface{}{"shell": "bash"},
+	}
+
+	tests := []struct {
+		name   string
+		locked []string
+		want   []string
+	}{
+		{"no locks", nil, []string{"bar", "system"}},
+		{"section locked", []string{"bar"}, []string{"bar", "system"}},
+		{"leaf locked", []string{"bar.margin.top"}, []string{"bar.height", "bar.margin.left", "bar.margin.top", "system"}},
+		{"wildcard below", []string{"bar.margin.*"}, []string{"bar.height", "bar.margin.left", "bar.margin.top", "system"}},
+	}
+
+	injector := NewPropertyInjector()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			split := injector.splitLockedSections(missing, tt.locked)
+			got := make([]string, 0, len(split))
+			for path := range split {
+				got = append(got, path)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("split paths = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplySchemeKeepsLockedSection(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Metadata.UserLocked = []string{"appearance.colors"}
+	before := config.Appearance.Colors
+
+	scheme, ok := builtinScheme("catppuccin-macchiato")
+	if !ok {
+		t.Fatal("catppuccin-macchiato is not a built-in scheme")
+	}
+	_, locked, err := ApplyScheme(config, &scheme)
+	if err != nil {
+		t.Fatalf("ApplyScheme: %v", err)
+	}
+
+	if config.Appearance.Colors != before {
+		t.Errorf("locked colors changed: got %+v, want %+v", config.Appearance.Colors, before)
+	}
+	if len(locked) == 0 {
+		t.Error("expected the locked color paths to be reported")
+	}
+	if config.Appearance.ColorScheme != "catppuccin-macchiato" {
+		t.Errorf("colorScheme = %q, want catppuccin-macchiato", config.Appearance.ColorScheme)
+	}
+}
@@ -0,0 +1,344 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Layer kinds, from lowest to highest precedence
+const (
+	LayerBase     = "base"
+	LayerFragment = "fragment"
+	LayerHost     = "host"
+)
+
+const (
+	// FragmentsDirName holds shell.d/*.json fragments next to shell.json
+	FragmentsDirName = "shell.d"
+	// HostsDirName holds hosts/<hostname>.json overlays next to shell.json
+	HostsDirName = "hosts"
+)
+
+// ConfigLayer is one file contributing to the merged configuration.
+// Layers other than the base may be partial.
+type ConfigLayer struct {
+	Kind string
+	Name string
+	Path string
+	Data map[string]interface{}
+}
+
+// LayerValue is the value a layer sets at a path
+type LayerValue struct {
+	Layer ConfigLayer
+	Value interface{}
+}
+
+// GetHostname returns the host name used to select hosts/<hostname>.json.
+// HEIMDALL_HOSTNAME overrides the system host name.
+func GetHostname() string {
+	if name := os.Getenv("HEIMDALL_HOSTNAME"); name != "" {
+		return name
+	}
+	name, _ := os.Hostname()
+	return name
+}
+
+// Layers returns the configuration layers in precedence order:
+// shell.json, shell.d/*.json sorted by name, hosts/<hostname>.json, then
+// the read-only environment and --set overrides
+func (cm *ConfigManager) Layers() ([]ConfigLayer, error) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	return cm.loadLayers()
+}
+
+// Explain lists every layer that sets path, lowest precedence first.
+// The last entry is the effective one; an empty result means no layer sets
+// the path and the schema's zero value applies.
+func (cm *ConfigManager) Explain(path string) ([]LayerValue, error) {
+	layers, err := cm.Layers()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]LayerValue, 0)
+	for _, layer := range layers {
+		if value, ok := LookupPath(layer.Data, path); ok {
+			values = append(values, LayerValue{Layer: layer, Value: value})
+		}
+	}
+	return values, nil
+}
+
+// LayerPath returns the file for a layer kind. Fragments are named by
+// their file name inside shell.d.
+func (cm *ConfigManager) LayerPath(kind, fragment string) (string, error) {
+	dir := filepath.Dir(cm.configPath)
+
+	switch kind {
+	case LayerBase, "":
+		return cm.configPath, nil
+	case LayerHost:
+		host := GetHostname()
+		if host == "" {
+			return "", fmt.Errorf("cannot determine hostname (set HEIMDALL_HOSTNAME)")
+		}
+		return filepath.Join(dir, HostsDirName, host+".json"), nil
+	case LayerFragment:
+		if fragment == "" || strings.ContainsAny(fragment, `/\`) || strings.Contains(fragment, "..") {
+			return "", fmt.Errorf("invalid fragment name: %q", fragment)
+		}
+		if !strings.HasSuffix(fragment, ".json") {
+			fragment += ".json"
+		}
+		return filepath.Join(dir, FragmentsDirName, fragment), nil
+	default:
+		return "", fmt.Errorf("unknown layer: %s (use base, fragment or host)", kind)
+	}
+}
+
+// SetLayerValue writes a single value into an overlay layer file, creating it
+// if needed. The merged result is validated before anything is written.
+func (cm *ConfigManager) SetLayerValue(kind, fragment, path string, value interface{}) (string, error) {
+	if kind == LayerBase || kind == "" {
+		return "", fmt.Errorf("use Save to change the base layer")
+	}
+
+	file, err := cm.LayerPath(kind, fragment)
+	if err != nil {
+		return "", err
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	layers, err := cm.loadLayers()
+	if err != nil {
+		return "", err
+	}
+
+	// Find or add the target layer
+	var target *ConfigLayer
+	for i := range layers {
+		if layers[i].Path == file {
+			target = &layers[i]
+		}
+	}
+	if target == nil {
+		layers = append(layers, ConfigLayer{Kind: kind, Path: file, Data: make(map[string]interface{})})
+		target = &layers[len(layers)-1]
+	}
+	SetMapPath(target.Data, path, value)
+
+	// Validate the merged result
+	merged, err := mergeLayers(layers)
+	if err != nil {
+		return "", err
+	}
+	for _, issue := range cm.validator.Validate(merged) {
+		if issue.Severity == SeverityError || issue.Severity == SeverityCritical {
+			return "", fmt.Errorf("validation failed: %s: %s", issue.Path, issue.Message)
+		}
+	}
+
+	data, err := json.MarshalIndent(target.Data, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal layer: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return "", fmt.Errorf("failed to create layer directory: %w", err)
+	}
+	if err := writeFileAtomic(file, data); err != nil {
+		return "", err
+	}
+
+	// Invalidate cache
+	cm.cache.config = nil
+
+	return file, nil
+}
+
+// loadLayers reads every existing layer file (must be called with lock)
+func (cm *ConfigManager) loadLayers() ([]ConfigLayer, error) {
+	dir := filepath.Dir(cm.configPath)
+	layers := make([]ConfigLayer, 0)
+
+	base, err := readLayer(LayerBase, filepath.Base(cm.configPath), cm.configPath)
+	if err != nil {
+		return nil, err
+	}
+	if base != nil {
+		layers = append(layers, *base)
+	}
+
+	fragments, err := filepath.Glob(filepath.Join(dir, FragmentsDirName, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fragments: %w", err)
+	}
+	sort.Strings(fragments)
+	for _, file := range fragments {
+		layer, err := readLayer(LayerFragment, filepath.Join(FragmentsDirName, filepath.Base(file)), file)
+		if err != nil {
+			return nil, err
+		}
+		if layer != nil {
+			layers = append(layers, *layer)
+		}
+	}
+
+	if host := GetHostname(); host != "" {
+		file := filepath.Join(dir, HostsDirName, host+".json")
+		layer, err := readLayer(LayerHost, filepath.Join(HostsDirName, host+".json"), file)
+		if err != nil {
+			return nil, err
+		}
+		if layer != nil {
+			layers = append(layers, *layer)
+		}
+	}
+
+	// Environment and --set overrides sit on top and are never written
+	layers = append(layers, cm.overrideLayers(layers)...)
+
+	return layers, nil
+}
+
+// layerSources lists the files and directories whose changes invalidate the cache
+func (cm *ConfigManager) layerSources() []string {
+	dir := filepath.Dir(cm.configPath)
+	sources := []string{
+		filepath.Join(dir, FragmentsDirName),
+		filepath.Join(dir, HostsDirName),
+	}
+	if files, err := filepath.Glob(filepath.Join(dir, FragmentsDirName, "*.json")); err == nil {
+		sources = append(sources, files...)
+	}
+	if host := GetHostname(); host != "" {
+		sources = append(sources, filepath.Join(dir, HostsDirName, host+".json"))
+	}
+	return sources
+}
+
+// readLayer parses a layer file; a missing file yields nil
+func readLayer(kind, name, path string) (*ConfigLayer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	values := make(map[string]interface{})
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	return &ConfigLayer{Kind: kind, Name: name, Path: path, Data: values}, nil
+}
+
+// mergeLayers deep-merges layers in order into a configuration
+func mergeLayers(layers []ConfigLayer) (*ShellConfig, error) {
+	merged := make(map[string]interface{})
+	injector := NewPropertyInjector()
+	for _, layer := range layers {
+		copied, err := deepCopyMap(layer.Data)
+		if err != nil {
+			return nil, err
+		}
+		injector.mergeDeep(merged, copied)
+	}
+
+	config := &ShellConfig{}
+	if err := mapToStruct(merged, config); err != nil {
+		return nil, fmt.Errorf("failed to merge configuration layers: %w", err)
+	}
+	return config, nil
+}
+
+// layeredBase computes the base layer content that makes the merged view
+// equal config. Values still supplied unchanged by overlays stay out of the
+// base so shell.d and host files keep control of them.
+func layeredBase(layers []ConfigLayer, config *ShellConfig) (map[string]interface{}, error) {
+	merged, err := mergeLayers(layers)
+	if err != nil {
+		return nil, err
+	}
+	mergedMap, err := structToMap(merged)
+	if err != nil {
+		return nil, err
+	}
+	configMap, err := structToMap(config)
+	if err != nil {
+		return nil, err
+	}
+
+	base := make(map[string]interface{})
+	if len(layers) > 0 && layers[0].Kind == LayerBase {
+		if base, err = deepCopyMap(layers[0].Data); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, path := range SchemaPaths() {
+		newValue, _ := LookupPath(configMap, path)
+		oldValue, _ := LookupPath(mergedMap, path)
+		if !reflect.DeepEqual(newValue, oldValue) {
+			SetMapPath(base, path, newValue)
+		}
+	}
+
+	// Unknown top-level keys are compared the same way; one removed from
+	// config is dropped from the base
+	for key := range config.Extra {
+		if !reflect.DeepEqual(configMap[key], mergedMap[key]) {
+			base[key] = configMap[key]
+		}
+	}
+	for key := range merged.Extra {
+		if _, ok := config.Extra[key]; !ok {
+			delete(base, key)
+		}
+	}
+
+	// Version and metadata always live in the base
+	base["version"] = configMap["version"]
+	base["metadata"] = configMap["metadata"]
+
+	return base, nil
+}
+
+// deepCopyMap copies a JSON-like map
+func deepCopyMap(src map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return nil, err
+	}
+	dst := make(map[string]interface{})
+	if err := json.Unmarshal(data, &dst); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+// writeFileAtomic writes data through a temporary file and rename
+func writeFileAtomic(path string, data []byte) error {
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile) // Clean up temp file
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package config_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"heimdall-cli/config"
+)
+
+// writeJSON writes a layer file, creating its directory
+func writeJSON(t *testing.T, path string, value interface{}) {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readJSON(t *testing.T, path string) map[string]interface{} {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make(map[string]interface{})
+	if err := json.Unmarshal(data, &values); err != nil {
+		t.Fatal(err)
+	}
+	return values
+}
+
+func TestSaveWithOverlays(t *testing.T) {
+	manager, _ := newTestManager(t)
+	t.Setenv("HEIMDALL_HOSTNAME", "box")
+	dir := filepath.Dir(config.GetConfigPath())
+
+	base := config.GetDefaultConfig()
+	base.Extra = map[string]interface{}{"notes": "old", "dropped": true}
+	if err := manager.Save(base); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	writeJSON(t, filepath.Join(dir, config.FragmentsDirName, "10-bar.json"), map[string]interface{}{
+		"bar":     map[string]interface{}{"height": 40},
+		"plugins": map[string]interface{}{"clock": "on"},
+	})
+	writeJSON(t, filepath.Join(dir, config.HostsDirName, "box.json"), map[string]interface{}{
+		"bar": map[string]interface{}{"position": "bottom"},
+	})
+
+	cfg, err := manager.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Bar.Height != 40 || cfg.Bar.Position != "bottom" || cfg.Extra["plugins"] == nil {
+		t.Fatalf("overlays not merged: height %d, position %q, extra %v", cfg.Bar.Height, cfg.Bar.Position, cfg.Extra)
+	}
+
+	cfg.Bar.Spacing = 3
+	cfg.Extra["notes"] = "new"
+	cfg.Extra["added"] = []interface{}{"x"}
+	delete(cfg.Extra, "dropped")
+	if err := manager.Save(cfg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// Changes land in shell.json; values the overlays supply stay out of it
+	written := readJSON(t, config.GetConfigPath())
+	bar := written["bar"].(map[string]interface{})
+	if bar["spacing"] != float64(3) {
+		t.Errorf("bar.spacing = %v, want 3", bar["spacing"])
+	}
+	if bar["height"] == float64(40) || bar["position"] == "bottom" {
+		t.Errorf("overlay values copied into shell.json: %v", bar)
+	}
+	if written["notes"] != "new" || !reflect.DeepEqual(written["added"], []interface{}{"x"}) {
+		t.Errorf("extra keys not saved: notes %v, added %v", written["notes"], written["added"])
+	}
+	if _, ok := written["dropped"]; ok {
+		t.Error("removed extra key is still in shell.json")
+	}
+	if _, ok := written["plugins"]; ok {
+		t.Error("fragment's extra key copied into shell.json")
+	}
+
+	reloaded, err := manager.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if reloaded.Bar.Spacing != 3 || reloaded.Extra["notes"] != "new" || reloaded.Extra["plugins"] == nil {
+		t.Errorf("reloaded spacing %d, extra %v", reloaded.Bar.Spacing, reloaded.Extra)
+	}
+}
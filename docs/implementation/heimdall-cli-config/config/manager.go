@@ -3,9 +3,9 @@ package config
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 )
@@ -30,6 +30,8 @@ type ConfigManager struct {
 	mu            sync.RWMutex
 	cache         *ConfigCache
 	logger        Logger
+	envOverrides  []ConfigLayer
+	envOnce       sync.Once
 }
 
 // ConfigCache stores cached configuration data
@@ -67,11 +69,14 @@ func NewConfigManager(logger Logger) (*ConfigManager, error) {
 		return nil, fmt.Errorf("failed to create backup directory: %w", err)
 	}
 
+	validator := NewSchemaValidator()
+	validator.SetKnownProfiles(NewProfileStore(GetProfilesDir()).Names())
+
 	cm := &ConfigManager{
 		configPath:    configPath,
 		backupDir:     backupDir,
 		schemaVersion: CurrentSchemaVersion,
-		validator:     NewSchemaValidator(),
+		validator:     validator,
 		migrator:      NewVersionMigrator(backupDir, logger),
 		injector:      NewPropertyInjector(),
 		logger:        logger,
@@ -129,7 +134,7 @@ func (cm *ConfigManager) Load() (*ShellConfig, error) {
 	defer cm.mu.RUnlock()
 
 	// Check cache
-	if cm.cache.IsValid(cm.configPath) {
+	if cm.cache.IsValid(cm.configPath, cm.layerSources()...) {
 		cm.logger.Debug("Returning cached configuration")
 		return cm.cache.config, nil
 	}
@@ -284,53 +289,102 @@ func GetBackupDir() string {
 	return filepath.Join(configHome, BackupDirPath)
 }
 
-// loadInternal loads configuration from disk (must be called with lock)
-func (cm *ConfigManager) loadInternal() (*ShellConfig, error) {
-	file, err := os.Open(cm.configPath)
+// GetStateDir returns the directory for runtime state such as slideshow
+// positions. HEIMDALL_STATE_DIR overrides it.
+func GetStateDir() string {
+	if envPath := os.Getenv("HEIMDALL_STATE_DIR"); envPath != "" {
+		return envPath
+	}
+
+	// Use XDG_STATE_HOME if set
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		home, _ := os.UserHomeDir()
+		stateHome = filepath.Join(home, ".local", "state")
+	}
+
+	return filepath.Join(stateHome, "heimdall")
+}
+
+// GetCacheDir returns the directory for data that can be rebuilt, such as
+// the wallpaper index. HEIMDALL_CACHE_DIR overrides it.
+func GetCacheDir() string {
+	if envPath := os.Getenv("HEIMDALL_CACHE_DIR"); envPath != "" {
+		return envPath
+	}
+
+	// Use XDG_CACHE_HOME if set
+	cacheHome := os.Getenv("XDG_CACHE_HOME")
+	if cacheHome == "" {
+		home, _ := os.UserHomeDir()
+		cacheHome = filepath.Join(home, ".cache")
+	}
+
+	return filepath.Join(cacheHome, "heimdall")
+}
+
+// ExpandHome expands a leading ~ to the user's home directory
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
 	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// loadInternal loads configuration from disk (must be called with lock).
+// shell.json is merged with shell.d/*.json fragments and the host overlay.
+func (cm *ConfigManager) loadInternal() (*ShellConfig, error) {
+	if _, err := os.Stat(cm.configPath); err != nil {
 		return nil, fmt.Errorf("failed to open config file: %w", err)
 	}
-	defer file.Close()
 
-	// Read file content
-	data, err := io.ReadAll(file)
+	layers, err := cm.loadLayers()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, fmt.Errorf("failed to read config layers: %w", err)
 	}
 
-	// Parse JSON with comment preservation
-	config := &ShellConfig{}
-	if err := json.Unmarshal(data, config); err != nil {
+	config, err := mergeLayers(layers)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse config JSON: %w", err)
 	}
 
 	return config, nil
 }
 
-// saveInternal saves configuration to disk (must be called with lock)
+// saveInternal saves configuration to disk (must be called with lock).
+// When overlays exist only the values they do not supply go to shell.json.
 func (cm *ConfigManager) saveInternal(config *ShellConfig) error {
-	// Marshal to JSON with indentation
-	data, err := json.MarshalIndent(config, "", "  ")
+	layers, err := cm.loadLayers()
 	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
+		return fmt.Errorf("failed to read config layers: %w", err)
 	}
 
-	// Write to temporary file first (atomic operation)
-	tempFile := cm.configPath + ".tmp"
-	if err := os.WriteFile(tempFile, data, 0644); err != nil {
-		return fmt.Errorf("failed to write temp file: %w", err)
+	var value interface{} = config
+	if len(layers) > 1 || (len(layers) == 1 && layers[0].Kind != LayerBase) {
+		base, err := layeredBase(layers, config)
+		if err != nil {
+			return fmt.Errorf("failed to split config layers: %w", err)
+		}
+		value = base
 	}
 
-	// Atomic rename
-	if err := os.Rename(tempFile, cm.configPath); err != nil {
-		os.Remove(tempFile) // Clean up temp file
-		return fmt.Errorf("failed to rename temp file: %w", err)
+	// Marshal to JSON with indentation
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	return nil
+	// Write to temporary file first (atomic operation)
+	return writeFileAtomic(cm.configPath, data)
 }
 
-// createBackup creates a backup of the current configuration
+// createBackup creates a backup of the current configuration. Only
+// shell.json is copied: saving never rewrites shell.d fragments or host
+// files, which are left to the user's version control.
 func (cm *ConfigManager) createBackup() error {
 	// Check if config exists
 	if _, err := os.Stat(cm.configPath); os.IsNotExist(err) {
@@ -347,6 +401,14 @@ func (cm *ConfigManager) createBackup() error {
 	timestamp := time.Now().Format("20060102-150405")
 	backupFile := filepath.Join(cm.backupDir, fmt.Sprintf("shell-%s.json", timestamp))
 
+	// Never overwrite an earlier backup taken within the same second
+	for n := 1; ; n++ {
+		if _, err := os.Stat(backupFile); os.IsNotExist(err) {
+			break
+		}
+		backupFile = filepath.Join(cm.backupDir, fmt.Sprintf("shell-%s-%d.json", timestamp, n))
+	}
+
 	// Write backup
 	if err := os.WriteFile(backupFile, data, 0600); err != nil {
 		return fmt.Errorf("failed to write backup: %w", err)
@@ -414,8 +476,9 @@ func (cm *ConfigManager) migrateFromLegacyLocation() error {
 	return nil
 }
 
-// IsValid checks if the cache is still valid
-func (c *ConfigCache) IsValid(configPath string) bool {
+// IsValid checks if the cache is still valid. Extra paths (layer files and
+// directories) invalidate the cache when modified after loading.
+func (c *ConfigCache) IsValid(configPath string, extra ...string) bool {
 	if c.config == nil {
 		return false
 	}
@@ -430,6 +493,15 @@ func (c *ConfigCache) IsValid(configPath string) bool {
 	if err != nil {
 		return false
 	}
+	if info.ModTime().After(c.loadTime) {
+		return false
+	}
+
+	for _, path := range extra {
+		if info, err := os.Stat(path); err == nil && info.ModTime().After(c.loadTime) {
+			return false
+		}
+	}
 
-	return info.ModTime().Before(c.loadTime) || info.ModTime().Equal(c.loadTime)
+	return true
 }
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MergePlan describes the result of merging an incoming file into a config
+type MergePlan struct {
+	Config  *ShellConfig
+	Changes []PathChange
+	Locked  []string
+}
+
+// PlanMerge merges the incoming (possibly partial) map into a copy of config.
+// With MergeDeep incoming values win; with MergeShallow existing values are
+// kept and only keys the config lacks, at any depth, are added. Only the
+// given paths are taken from the incoming map; with no paths every section
+// it contains is used. Version and metadata are never imported, and changes
+// to locked paths are dropped.
+func (i *PropertyInjector) PlanMerge(config *ShellConfig, incoming map[string]interface{}, paths []string, strategy InjectionStrategy) (*MergePlan, error) {
+	if strategy != MergeDeep && strategy != MergeShallow {
+		return nil, fmt.Errorf("unsupported merge strategy")
+	}
+
+	if len(paths) == 0 {
+		for key := range incoming {
+			paths = append(paths, key)
+		}
+	}
+
+	configMap, err := i.structToMap(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert config to map: %w", err)
+	}
+
+	for _, path := range paths {
+		if path == "version" || path == "metadata" || strings.HasPrefix(path, "metadata.") {
+			continue
+		}
+
+		value, ok := LookupPath(incoming, path)
+		if !ok {
+			return nil, fmt.Errorf("import file has no value at %s", path)
+		}
+		if strategy == MergeShallow {
+			addition := make(map[string]interface{})
+			SetMapPath(addition, path, value)
+			addMissing(configMap, addition)
+			continue
+		}
+		if err := i.injectProperty(configMap, path, value, strategy); err != nil {
+			return nil, fmt.Errorf("failed to merge %s: %w", path, err)
+		}
+	}
+
+	merged := &ShellConfig{}
+	if err := i.mapToStruct(configMap, merged); err != nil {
+		return nil, fmt.Errorf("failed to convert map to config: %w", err)
+	}
+
+	changes, err := DiffConfigs(config, merged, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	locked := i.getUserLocks(config)
+	plan := &MergePlan{
+		Changes: make([]PathChange, 0, len(changes)),
+		Locked:  make([]string, 0),
+	}
+	for _, change := range changes {
+		if strings.HasPrefix(change.Path, "metadata.") {
+			continue
+		}
+		if i.isUserLocked(change.Path, locked) {
+			plan.Locked = append(plan.Locked, change.Path)
+			continue
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+
+	plan.Config, err = CloneConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy config: %w", err)
+	}
+	if err := i.ApplyChanges(plan.Config, plan.Changes); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// addMissing copies the keys of src that dst lacks or holds null, at every
+// depth, leaving every existing value alone
+func addMissing(dst, src map[string]interface{}) {
+	for key, value := range src {
+		existing, ok := dst[key]
+		if !ok || existing == nil {
+			dst[key] = value
+			continue
+		}
+		dstMap, dstOK := existing.(map[string]interface{})
+		srcMap, srcOK := value.(map[string]interface{})
+		if dstOK && srcOK {
+			addMissing(dstMap, srcMap)
+		}
+	}
+}
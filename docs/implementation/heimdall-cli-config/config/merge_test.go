@@ -0,0 +1,69 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlanMerge(t *testing.T) {
+	incoming := map[string]interface{}{
+		"bar": map[string]interface{}{"height": 99.0, "position": "bottom"},
+		"commands": map[string]interface{}{"custom": map[string]interface{}{
+			"shot": map[string]interface{}{"command": "flameshot", "icon": "camera"},
+			"lock": map[string]interface{}{"command": "hyprlock"},
+		}},
+		"metadata": map[string]interface{}{"profile": "gaming"},
+	}
+
+	tests := []struct {
+		name      string
+		strategy  InjectionStrategy
+		paths     []string
+		locked    []string
+		height    int
+		position  string
+		shot      CommandDef
+		lockAdded bool
+		lockedOut []string
+	}{
+		{"deep", MergeDeep, nil, nil, 99, "bottom", CommandDef{Command: "flameshot", Icon: "camera"}, true, []string{}},
+		{"deep with lock", MergeDeep, nil, []string{"bar.height"}, 30, "bottom", CommandDef{Command: "flameshot", Icon: "camera"}, true, []string{"bar.height"}},
+		{"deep with paths", MergeDeep, []string{"bar.position"}, nil, 30, "bottom", CommandDef{Command: "grim"}, false, []string{}},
+		// Shallow keeps every existing value and only adds what is absent
+		{"shallow", MergeShallow, nil, nil, 30, "top", CommandDef{Command: "grim"}, true, []string{}},
+		{"shallow with paths", MergeShallow, []string{"commands.custom.shot"}, nil, 30, "top", CommandDef{Command: "grim"}, false, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.Commands.Custom["shot"] = CommandDef{Command: "grim"}
+			config.Metadata.Profile = "default"
+			config.Metadata.UserLocked = tt.locked
+
+			plan, err := NewPropertyInjector().PlanMerge(config, incoming, tt.paths, tt.strategy)
+			if err != nil {
+				t.Fatalf("PlanMerge: %v", err)
+			}
+			merged := plan.Config
+			if merged.Bar.Height != tt.height || merged.Bar.Position != tt.position {
+				t.Errorf("bar = %d/%q, want %d/%q", merged.Bar.Height, merged.Bar.Position, tt.height, tt.position)
+			}
+			if got := merged.Commands.Custom["shot"]; !reflect.DeepEqual(got, tt.shot) {
+				t.Errorf("commands.custom.shot = %+v, want %+v", got, tt.shot)
+			}
+			if _, ok := merged.Commands.Custom["lock"]; ok != tt.lockAdded {
+				t.Errorf("commands.custom.lock added = %v, want %v", ok, tt.lockAdded)
+			}
+			if merged.Metadata.Profile != "default" {
+				t.Errorf("metadata.profile = %q, metadata must not be imported", merged.Metadata.Profile)
+			}
+			if !reflect.DeepEqual(plan.Locked, tt.lockedOut) {
+				t.Errorf("locked = %v, want %v", plan.Locked, tt.lockedOut)
+			}
+			if config.Bar.Height != 30 {
+				t.Error("PlanMerge changed the input config")
+			}
+		})
+	}
+}
@@ -195,6 +195,64 @@ func (m *VersionMigrator) MigrateToVersion(config *ShellConfig, targetVersion st
 	return migratedConfig, nil
 }
 
+// MigratePartial migrates a possibly partial configuration map, such as a
+// shared import file, to the current version. No backup is taken because the
+// map is not the live configuration. Sections a migration adds on its own
+// (rather than renames from the input) are dropped so they cannot overwrite
+// unrelated settings when the result is merged.
+func (m *VersionMigrator) MigratePartial(data map[string]interface{}) (map[string]interface{}, error) {
+	from, _ := data["version"].(string)
+	if from == "" || from == CurrentSchemaVersion {
+		return data, nil
+	}
+
+	path := m.findMigrationPath(from, CurrentSchemaVersion)
+	if len(path) == 0 {
+		return nil, fmt.Errorf("no migration path from %s to %s", from, CurrentSchemaVersion)
+	}
+
+	migrated, err := applyMigrations(path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find what the migrations add to an empty document
+	skeleton, err := applyMigrations(path, map[string]interface{}{
+		"version":  from,
+		"metadata": map[string]interface{}{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for key := range skeleton {
+		if _, existed := data[key]; !existed && key != "version" {
+			delete(migrated, key)
+		}
+	}
+
+	return migrated, nil
+}
+
+// applyMigrations runs migrations over a copy of a map
+func applyMigrations(path []Migration, data map[string]interface{}) (map[string]interface{}, error) {
+	configMap, err := structToMap(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy config: %w", err)
+	}
+
+	for _, migration := range path {
+		if err := migration.Validate(configMap); err != nil {
+			return nil, fmt.Errorf("migration validation failed: %w", err)
+		}
+		if err := migration.Migrate(configMap); err != nil {
+			return nil, fmt.Errorf("migration failed: %w", err)
+		}
+		configMap["version"] = migration.ToVersion()
+	}
+
+	return configMap, nil
+}
+
 // GetAvailableVersions returns all available versions
 func (m *VersionMigrator) GetAvailableVersions() []string {
 	versions := make(map[string]bool)
@@ -224,6 +282,9 @@ func (m *VersionMigrator) registerMigrations() {
 	// Register migration from 0.9.0 to 1.0.0
 	m.registerMigration(&Migration_0_9_0_to_1_0_0{})
 
+	// Register migration from 1.0.0 to 1.1.0
+	m.registerMigration(&Migration_1_0_0_to_1_1_0{})
+
 	// Add more migrations as needed
 }
 
@@ -493,3 +554,134 @@ func (m *Migration_0_9_0_to_1_0_0) Validate(config map[string]interface{}) error
 
 	return nil
 }
+
+// Migration_1_0_0_to_1_1_0 folds the flat display and wallpaper monitor
+// settings into the per-output monitors map
+type Migration_1_0_0_to_1_1_0 struct{}
+
+// legacyDisplayFields are the services.display keys that applied to every screen
+var legacyDisplayFields = []string{"resolution", "refreshRate", "scale"}
+
+func (m *Migration_1_0_0_to_1_1_0) FromVersion() string { return "1.0.0" }
+func (m *Migration_1_0_0_to_1_1_0) ToVersion() string   { return "1.1.0" }
+
+func (m *Migration_1_0_0_to_1_1_0) Migrate(config map[string]interface{}) error {
+	monitors, _ := config["monitors"].(map[string]interface{})
+	if monitors == nil {
+		monitors = make(map[string]interface{})
+	}
+
+	// The one resolution, refresh rate and scale become the "*" defaults
+	if services, ok := config["services"].(map[string]interface{}); ok {
+		if display, ok := services["display"].(map[string]interface{}); ok {
+			defaults, _ := monitors[AllMonitors].(map[string]interface{})
+			if defaults == nil {
+				defaults = make(map[string]interface{})
+			}
+			for _, key := range legacyDisplayFields {
+				if value, ok := display[key]; ok {
+					if value != nil && value != "" && value != 0.0 && value != 0 {
+						defaults[key] = value
+					}
+					delete(display, key)
+				}
+			}
+			if len(defaults) > 0 {
+				monitors[AllMonitors] = defaults
+			}
+		}
+	}
+
+	// Every slideshow output gets an entry
+	if wallpaper, ok := config["wallpaper"].(map[string]interface{}); ok {
+		var names []string
+		switch list := wallpaper["monitors"].(type) {
+		case []interface{}:
+			for _, item := range list {
+				if name, ok := item.(string); ok {
+					names = append(names, name)
+				}
+			}
+		case []string:
+			names = list
+		}
+		for _, name := range names {
+			if _, exists := monitors[name]; name != "" && !exists {
+				monitors[name] = map[string]interface{}{}
+			}
+		}
+		delete(wallpaper, "monitors")
+	}
+
+	// Left out when empty so partial documents do not gain the section
+	if len(monitors) > 0 {
+		config["monitors"] = monitors
+	}
+
+	// Update metadata
+	if metadata, ok := config["metadata"].(map[string]interface{}); ok {
+		metadata["migrated"] = time.Now().Format(time.RFC3339)
+		metadata["migrationVersion"] = "1.1.0"
+	}
+
+	return nil
+}
+
+// Rollback restores the flat fields from the "*" entry and the output
+// names. Positions, transforms and per-output overrides have no 1.0.0
+// equivalent and are dropped.
+func (m *Migration_1_0_0_to_1_1_0) Rollback(config map[string]interface{}) error {
+	monitors, _ := config["monitors"].(map[string]interface{})
+
+	if defaults, ok := monitors[AllMonitors].(map[string]interface{}); ok {
+		services, _ := config["services"].(map[string]interface{})
+		if services == nil {
+			services = make(map[string]interface{})
+			config["services"] = services
+		}
+		display, _ := services["display"].(map[string]interface{})
+		if display == nil {
+			display = make(map[string]interface{})
+			services["display"] = display
+		}
+		for _, key := range legacyDisplayFields {
+			if value, ok := defaults[key]; ok {
+				display[key] = value
+			}
+		}
+	}
+
+	names := make([]string, 0, len(monitors))
+	for name := range monitors {
+		if name != AllMonitors {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if wallpaper, ok := config["wallpaper"].(map[string]interface{}); ok {
+		wallpaper["monitors"] = names
+	}
+	delete(config, "monitors")
+
+	// Clean up metadata
+	if metadata, ok := config["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "migrated")
+		delete(metadata, "migrationVersion")
+	}
+
+	return nil
+}
+
+func (m *Migration_1_0_0_to_1_1_0) Validate(config map[string]interface{}) error {
+	// Validate that the config can be migrated
+	version, ok := config["version"].(string)
+	if !ok {
+		return fmt.Errorf("missing version field")
+	}
+
+	if !strings.HasPrefix(version, "1.0") {
+		return fmt.Errorf("invalid source version: %s", version)
+	}
+
+	return nil
+}
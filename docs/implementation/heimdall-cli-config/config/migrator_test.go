@@ -0,0 +1,71 @@
+package config_test
+
+import (
+	"testing"
+
+	"heimdall-cli/config"
+	"heimdall-cli/logging"
+)
+
+func TestMigrateFrom1_0_0(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.Version = "1.0.0"
+	cfg.Monitors = nil
+	cfg.Services.Display.Resolution = "2560x1440"
+	cfg.Services.Display.Scale = 1.5
+	cfg.Wallpaper.Monitors = []string{"DP-1", "HDMI-A-1"}
+
+	recorder := logging.NewRecorder()
+	migrator := config.NewVersionMigrator(t.TempDir(), recorder)
+	migrated, err := migrator.Migrate(cfg)
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	if migrated.Version != config.CurrentSchemaVersion {
+		t.Errorf("version = %s, want %s", migrated.Version, config.CurrentSchemaVersion)
+	}
+	defaults := migrated.Monitors[config.AllMonitors]
+	if defaults.Resolution != "2560x1440" || defaults.Scale != 1.5 {
+		t.Errorf(`monitors["*"] = %+v, want the old display resolution and scale`, defaults)
+	}
+	for _, name := range []string{"DP-1", "HDMI-A-1"} {
+		if _, ok := migrated.Monitors[name]; !ok {
+			t.Errorf("monitors.%s missing", name)
+		}
+	}
+	display := migrated.Services.Display
+	if display.Resolution != "" || display.Scale != 0 || len(migrated.Wallpaper.Monitors) != 0 {
+		t.Errorf("legacy fields left behind: display=%+v wallpaper.monitors=%v", display, migrated.Wallpaper.Monitors)
+	}
+
+	if !recorder.Has(logging.LevelInfo, "Applying migration") {
+		t.Error(`expected an "Applying migration" log entry`)
+	}
+	if !recorder.Has(logging.LevelInfo, "Migration completed successfully") {
+		t.Error(`expected a "Migration completed successfully" log entry`)
+	}
+}
+
+func TestMigratePartial(t *testing.T) {
+	// A shared 1.0.0 file with only colors gains no other sections
+	partial := map[string]interface{}{
+		"version":    "1.0.0",
+		"appearance": map[string]interface{}{"accentColor": "#ff0000"},
+	}
+	migrator := config.NewVersionMigrator(t.TempDir(), logging.NewRecorder())
+	migrated, err := migrator.MigratePartial(partial)
+	if err != nil {
+		t.Fatalf("MigratePartial: %v", err)
+	}
+	if migrated["version"] != config.CurrentSchemaVersion {
+		t.Errorf("version = %v, want %s", migrated["version"], config.CurrentSchemaVersion)
+	}
+	if len(migrated) != 2 {
+		t.Errorf("migration added sections: %v", migrated)
+	}
+
+	if _, err := migrator.MigratePartial(map[string]interface{}{"version": "9.0.0"}); err == nil {
+		t.Error("expected an error for a version with no migration path")
+	}
+}
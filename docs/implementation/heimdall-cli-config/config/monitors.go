@@ -0,0 +1,172 @@
+package config
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sort"
+)
+
+// MonitorTransforms lists the accepted monitors.<output>.transform values,
+// in the order of the Wayland output transform enum
+var MonitorTransforms = []string{
+	"normal", "90", "180", "270",
+	"flipped", "flipped-90", "flipped-180", "flipped-270",
+}
+
+// MonitorNames returns the configured output names, sorted, without the
+// "*" defaults entry
+func (m MonitorsConfig) MonitorNames() []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		if name != AllMonitors {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Monitor returns the effective settings of an output: its own entry over
+// the "*" entry. Configurations not yet migrated to 1.1.0 have no "*"
+// entry but the flat services.display fields, which then take its place.
+func (c *ShellConfig) Monitor(name string) MonitorConfig {
+	resolved, migrated := c.Monitors[AllMonitors]
+	resolved.Position = nil
+	resolved.Wallpaper = ""
+
+	// Once "*" exists the flat fields are ignored; set later, they would
+	// silently override every output
+	if display := &c.Services.Display; !migrated {
+		if display.Resolution != "" {
+			resolved.Resolution = display.Resolution
+		}
+		if display.RefreshRate != 0 {
+			resolved.RefreshRate = display.RefreshRate
+		}
+		if display.Scale != 0 {
+			resolved.Scale = display.Scale
+		}
+	}
+
+	if name == AllMonitors {
+		return resolved
+	}
+	own, ok := c.Monitors[name]
+	if !ok {
+		return resolved
+	}
+	if own.Resolution != "" {
+		resolved.Resolution = own.Resolution
+	}
+	if own.RefreshRate != 0 {
+		resolved.RefreshRate = own.RefreshRate
+	}
+	if own.Scale != 0 {
+		resolved.Scale = own.Scale
+	}
+	if own.Transform != "" {
+		resolved.Transform = own.Transform
+	}
+	if own.Bar != nil {
+		resolved.Bar = own.Bar
+	}
+	resolved.Position = own.Position
+	resolved.Wallpaper = own.Wallpaper
+	return resolved
+}
+
+// ShowsBar reports whether the bar is shown on the output; unset means yes
+func (m MonitorConfig) ShowsBar() bool {
+	return m.Bar == nil || *m.Bar
+}
+
+// Rotated reports whether the transform turns the output by 90 or 270 degrees
+func (m MonitorConfig) Rotated() bool {
+	switch m.Transform {
+	case "90", "270", "flipped-90", "flipped-270":
+		return true
+	}
+	return false
+}
+
+// PixelSize returns the output's size in physical pixels as it is laid
+// out, with width and height swapped for rotated outputs. ok is false
+// when the resolution is unset or auto.
+func (m MonitorConfig) PixelSize() (width, height int, ok bool) {
+	if m.Resolution == "" || m.Resolution == "auto" {
+		return 0, 0, false
+	}
+	width, height, err := ParseResolution(m.Resolution)
+	if err != nil {
+		return 0, 0, false
+	}
+	if m.Rotated() {
+		width, height = height, width
+	}
+	return width, height, true
+}
+
+// LogicalBounds returns the area the output covers in the layout: its
+// position and its pixel size divided by the scale. ok is false when the
+// position or the resolution is unknown.
+func (m MonitorConfig) LogicalBounds() (image.Rectangle, bool) {
+	width, height, ok := m.PixelSize()
+	if !ok || m.Position == nil {
+		return image.Rectangle{}, false
+	}
+	scale := m.Scale
+	if scale <= 0 {
+		scale = 1
+	}
+	min := image.Pt(m.Position.X, m.Position.Y)
+	size := image.Pt(int(math.Round(float64(width)/scale)), int(math.Round(float64(height)/scale)))
+	return image.Rectangle{Min: min, Max: min.Add(size)}, true
+}
+
+// MonitorOverlap is a pair of outputs whose layout areas intersect
+type MonitorOverlap struct {
+	First, Second string
+	FirstBounds   image.Rectangle
+	SecondBounds  image.Rectangle
+	Intersection  image.Rectangle
+}
+
+// MonitorOverlaps returns every pair of positioned outputs that overlap.
+// Outputs that only touch at an edge do not overlap.
+func (c *ShellConfig) MonitorOverlaps() []MonitorOverlap {
+	names := c.Monitors.MonitorNames()
+	bounds := make(map[string]image.Rectangle)
+	for _, name := range names {
+		if rect, ok := c.Monitor(name).LogicalBounds(); ok {
+			bounds[name] = rect
+		}
+	}
+
+	overlaps := make([]MonitorOverlap, 0)
+	for i, first := range names {
+		a, ok := bounds[first]
+		if !ok {
+			continue
+		}
+		for _, second := range names[i+1:] {
+			b, ok := bounds[second]
+			if !ok {
+				continue
+			}
+			if common := a.Intersect(b); !common.Empty() {
+				overlaps = append(overlaps, MonitorOverlap{
+					First: first, Second: second,
+					FirstBounds: a, SecondBounds: b,
+					Intersection: common,
+				})
+			}
+		}
+	}
+	return overlaps
+}
+
+// describeBounds formats a layout area as "WxH at X,Y"
+func describeBounds(r image.Rectangle) string {
+	return fmt.Sprintf("%dx%d at %d,%d", r.Dx(), r.Dy(), r.Min.X, r.Min.Y)
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"image"
+	"testing"
+)
+
+func TestMonitorResolution(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Monitors = MonitorsConfig{
+		AllMonitors: {Resolution: "1920x1080", RefreshRate: 60, Scale: 1},
+		"DP-1":      {RefreshRate: 144, Position: &MonitorPosition{X: 0, Y: 0}},
+	}
+
+	dp := config.Monitor("DP-1")
+	if dp.Resolution != "1920x1080" || dp.RefreshRate != 144 || dp.Scale != 1 || dp.Position == nil {
+		t.Errorf("DP-1 = %+v, want its own refresh rate over the defaults", dp)
+	}
+	if other := config.Monitor("HDMI-A-1"); other.RefreshRate != 60 || other.Position != nil {
+		t.Errorf("unconfigured output = %+v, want the defaults without a position", other)
+	}
+
+	// Flat fields set after migrating must not override every output
+	config.Services.Display.Scale = 2
+	config.Services.Display.Resolution = "800x600"
+	if got := config.Monitor("DP-1"); got.Scale != 1 || got.Resolution != "1920x1080" {
+		t.Errorf("DP-1 = %+v, want the legacy display fields ignored", got)
+	}
+
+	found := false
+	for _, verr := range NewSchemaValidator().Validate(config) {
+		if verr.Path == "services.display" && verr.Type == MigrationErrorType {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected a warning that the legacy display fields are ignored")
+	}
+
+	// Before migrating there is no "*" entry and the flat fields apply
+	delete(config.Monitors, AllMonitors)
+	if got := config.Monitor("DP-1"); got.Scale != 2 || got.Resolution != "800x600" || got.RefreshRate != 144 {
+		t.Errorf("unmigrated DP-1 = %+v, want the legacy display fields", got)
+	}
+}
+
+func TestMonitorOverlaps(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Monitors = MonitorsConfig{
+		AllMonitors: {Scale: 1},
+		// 2560x1440 at scale 1.25 covers 2048x1152
+		"DP-1": {Resolution: "2560x1440", Scale: 1.25, Position: &MonitorPosition{X: 0, Y: 0}},
+		// Touches DP-1's right edge
+		"DP-2": {Resolution: "1920x1080", Position: &MonitorPosition{X: 2048, Y: 0}},
+		// Rotated to 1080x1920, reaching into DP-2
+		"HDMI-A-1": {Resolution: "1920x1080", Transform: "90", Position: &MonitorPosition{X: 3000, Y: 500}},
+	}
+
+	if bounds, ok := config.Monitor("HDMI-A-1").LogicalBounds(); !ok || bounds != image.Rect(3000, 500, 4080, 2420) {
+		t.Errorf("HDMI-A-1 bounds = %v, %v", bounds, ok)
+	}
+
+	overlaps := config.MonitorOverlaps()
+	if len(overlaps) != 1 || overlaps[0].First != "DP-2" || overlaps[0].Second != "HDMI-A-1" {
+		t.Fatalf("overlaps = %+v, want only DP-2 and HDMI-A-1", overlaps)
+	}
+	if want := image.Rect(3000, 500, 3968, 1080); overlaps[0].Intersection != want {
+		t.Errorf("intersection = %v, want %v", overlaps[0].Intersection, want)
+	}
+}
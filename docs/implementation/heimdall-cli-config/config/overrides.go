@@ -0,0 +1,217 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Read-only override layers, above every file layer
+const (
+	LayerEnv  = "env"
+	LayerFlag = "flag"
+)
+
+// EnvOverridePrefix starts environment variables that override a leaf,
+// with "__" separating path segments: HEIMDALL__BAR__HEIGHT=40
+const EnvOverridePrefix = "HEIMDALL__"
+
+// flagOverrides holds the parsed --set values for this process
+var flagOverrides []ConfigLayer
+
+// SetFlagOverrides parses "path=value" pairs from --set flags. Values are
+// coerced to the schema type of the path. The overrides apply to every
+// ConfigManager load in this process and are never saved.
+func SetFlagOverrides(sets []string) error {
+	layers := make([]ConfigLayer, 0, len(sets))
+	for _, set := range sets {
+		path, raw, ok := strings.Cut(set, "=")
+		path = strings.TrimSpace(path)
+		if !ok || path == "" {
+			return fmt.Errorf("invalid --set %q (use path=value)", set)
+		}
+
+		layer, err := overrideLayer(LayerFlag, "--set "+path, path, raw)
+		if err != nil {
+			return err
+		}
+		layers = append(layers, *layer)
+	}
+
+	flagOverrides = layers
+	return nil
+}
+
+// EnvOverridePath maps an override variable name to a schema path.
+// Segments match case-insensitively and ignore underscores, so
+// HEIMDALL__SERVICES__POWER__AC_ACTION selects services.power.acAction.
+// Variable names cannot hold the keys of maps like commands.custom, so
+// those segments are matched against the entries the file layers define,
+// with "-" written as "_": HEIMDALL__COMMANDS__CUSTOM__SCREEN_SHOT__COMMAND
+// selects commands.custom.screen-shot.command once screen-shot is in a file.
+func EnvOverridePath(name string, files []ConfigLayer) (string, error) {
+	if !strings.HasPrefix(name, EnvOverridePrefix) {
+		return "", fmt.Errorf("%s does not start with %s", name, EnvOverridePrefix)
+	}
+
+	raw := strings.Split(strings.TrimPrefix(name, EnvOverridePrefix), "__")
+	segments := make([]string, len(raw))
+	for i, segment := range raw {
+		segments[i] = normalizeSegment(segment)
+	}
+	key := strings.Join(segments, ".")
+
+	for _, path := range SchemaPaths() {
+		if normalizeSegment(path) == key {
+			return path, nil
+		}
+	}
+
+	for _, f := range SchemaFields() {
+		prefix := normalizeSegment(f.Path) + "."
+		if f.Kind != reflect.Map || f.Type.Elem().Kind() != reflect.Struct || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		rest := strings.SplitN(strings.TrimPrefix(key, prefix), ".", 2)
+		if len(rest) < 2 {
+			continue
+		}
+
+		elemFields := make([]FieldInfo, 0)
+		collectFields("", f.Type.Elem(), &elemFields)
+		field := ""
+		for _, elem := range elemFields {
+			if normalizeSegment(elem.Path) == rest[1] {
+				field = elem.Path
+				break
+			}
+		}
+		if field == "" {
+			break
+		}
+
+		for _, entry := range layerMapKeys(files, f.Path) {
+			if normalizeSegment(strings.ReplaceAll(entry, "-", "")) == rest[0] {
+				return f.Path + "." + entry + "." + field, nil
+			}
+		}
+		return "", fmt.Errorf("no %s entry in the config files matches %s", f.Path, raw[strings.Count(f.Path, ".")+1])
+	}
+
+	return "", fmt.Errorf("unknown config path")
+}
+
+// layerMapKeys lists the keys the layers define for the map at path, sorted
+func layerMapKeys(layers []ConfigLayer, path string) []string {
+	seen := make(map[string]bool)
+	keys := make([]string, 0)
+	for _, layer := range layers {
+		value, _ := LookupPath(layer.Data, path)
+		entries, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key := range entries {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// LoadFiles returns the configuration merged from files only, without
+// environment and --set overrides
+func (cm *ConfigManager) LoadFiles() (*ShellConfig, error) {
+	layers, err := cm.Layers()
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]ConfigLayer, 0, len(layers))
+	for _, layer := range layers {
+		if !IsOverrideLayer(layer.Kind) {
+			files = append(files, layer)
+		}
+	}
+	return mergeLayers(files)
+}
+
+// IsOverrideLayer reports whether a layer kind is a read-only override
+func IsOverrideLayer(kind string) bool {
+	return kind == LayerEnv || kind == LayerFlag
+}
+
+// overrideLayers returns the environment overrides followed by --set
+// overrides. Unknown or invalid variables are logged once and skipped;
+// map entries are resolved against the file layers of the first load.
+func (cm *ConfigManager) overrideLayers(files []ConfigLayer) []ConfigLayer {
+	cm.envOnce.Do(func() {
+		cm.envOverrides = cm.readEnvOverrides(files)
+	})
+
+	layers := make([]ConfigLayer, 0, len(cm.envOverrides)+len(flagOverrides))
+	layers = append(layers, cm.envOverrides...)
+	return append(layers, flagOverrides...)
+}
+
+// readEnvOverrides parses HEIMDALL__* variables in name order
+func (cm *ConfigManager) readEnvOverrides(files []ConfigLayer) []ConfigLayer {
+	names := make([]string, 0)
+	values := make(map[string]string)
+	for _, entry := range os.Environ() {
+		name, value, _ := strings.Cut(entry, "=")
+		if strings.HasPrefix(name, EnvOverridePrefix) {
+			names = append(names, name)
+			values[name] = value
+		}
+	}
+	sort.Strings(names)
+
+	layers := make([]ConfigLayer, 0, len(names))
+	for _, name := range names {
+		path, err := EnvOverridePath(name, files)
+		if err != nil {
+			cm.logger.Warn("Ignoring override for unknown config path",
+				Field{"variable", name},
+				Field{"error", err.Error()})
+			continue
+		}
+
+		layer, err := overrideLayer(LayerEnv, name, path, values[name])
+		if err != nil {
+			cm.logger.Warn("Ignoring invalid config override",
+				Field{"variable", name},
+				Field{"error", err.Error()})
+			continue
+		}
+		layers = append(layers, *layer)
+	}
+
+	return layers
+}
+
+// overrideLayer builds a single-value layer, coercing raw to the schema type
+func overrideLayer(kind, name, path, raw string) (*ConfigLayer, error) {
+	if _, ok := LookupField(path); !ok {
+		return nil, fmt.Errorf("unknown config path: %s", path)
+	}
+
+	value, err := ParseValue(path, raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", path, err)
+	}
+
+	data := make(map[string]interface{})
+	SetMapPath(data, path, value)
+	return &ConfigLayer{Kind: kind, Name: name, Data: data}, nil
+}
+
+// normalizeSegment lowercases and drops underscores for name matching
+func normalizeSegment(s string) string {
+	return strings.ReplaceAll(strings.ToLower(s), "_", "")
+}
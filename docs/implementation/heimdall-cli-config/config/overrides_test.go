@@ -0,0 +1,54 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnvOverridePath(t *testing.T) {
+	files := []ConfigLayer{
+		{Kind: LayerBase, Data: map[string]interface{}{
+			"commands": map[string]interface{}{"custom": map[string]interface{}{"screen-shot": map[string]interface{}{}}},
+			"monitors": map[string]interface{}{"*": map[string]interface{}{}, "DP-1": map[string]interface{}{}},
+		}},
+		{Kind: LayerHost, Data: map[string]interface{}{
+			"commands": map[string]interface{}{"custom": map[string]interface{}{"lockScreen": map[string]interface{}{"command": "hyprlock"}}},
+			"monitors": map[string]interface{}{"eDP-1": map[string]interface{}{"scale": 1.5}},
+		}},
+	}
+
+	tests := []struct {
+		name string
+		want string
+		err  string
+	}{
+		{"HEIMDALL__BAR__HEIGHT", "bar.height", ""},
+		{"HEIMDALL__SERVICES__POWER__AC_ACTION", "services.power.acAction", ""},
+		{"HEIMDALL__bar__margin__TOP", "bar.margin.top", ""},
+		{"HEIMDALL__COMMANDS__CUSTOM__SCREEN_SHOT__COMMAND", "commands.custom.screen-shot.command", ""},
+		{"HEIMDALL__COMMANDS__CUSTOM__LOCK_SCREEN__ARGS", "commands.custom.lockScreen.args", ""},
+		{"HEIMDALL__COMMANDS__CUSTOM__NO_SUCH__COMMAND", "", "no commands.custom entry in the config files matches NO_SUCH"},
+		{"HEIMDALL__COMMANDS__CUSTOM__SCREEN_SHOT__NOPE", "", "unknown config path"},
+		{"HEIMDALL__MONITORS__DP_1__SCALE", "monitors.DP-1.scale", ""},
+		{"HEIMDALL__MONITORS__EDP_1__REFRESH_RATE", "monitors.eDP-1.refreshRate", ""},
+		{"HEIMDALL__MONITORS__EDP_1__POSITION__X", "monitors.eDP-1.position.x", ""},
+		{"HEIMDALL__MONITORS__HDMI_A_1__SCALE", "", "no monitors entry in the config files matches HDMI_A_1"},
+		{"HEIMDALL__BAR__NOPE", "", "unknown config path"},
+		{"HEIMDALL_BAR_HEIGHT", "", "does not start with"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EnvOverridePath(tt.name, files)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("EnvOverridePath = %q, %v, want error %q", got, err, tt.err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("EnvOverridePath = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,372 @@
+package config
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultPaletteSize is the number of dominant colors extracted by default
+const DefaultPaletteSize = 8
+
+// WallpaperSchemeName is the registry name of generated wallpaper schemes
+const WallpaperSchemeName = "wallpaper"
+
+// paletteSampleSize bounds the sampling grid, so large wallpapers cost
+// the same as small ones
+const paletteSampleSize = 256
+
+// paletteImageExts are the image types the palette generator decodes
+var paletteImageExts = []string{".png", ".jpg", ".jpeg"}
+
+// paletteLevels holds the OKLCH lightness and chroma of each role for a variant
+type paletteLevels struct {
+	Background      float64
+	Surface         float64
+	Border          float64
+	Foreground      float64
+	NeutralChroma   float64
+	Accent          float64
+	AccentMinChroma float64
+	AccentMaxChroma float64
+	Semantic        float64
+	SemanticChroma  float64
+}
+
+var paletteVariantLevels = map[string]paletteLevels{
+	"dark": {
+		Background:      0.23,
+		Surface:         0.30,
+		Border:          0.40,
+		Foreground:      0.92,
+		NeutralChroma:   0.02,
+		Accent:          0.76,
+		AccentMinChroma: 0.09,
+		AccentMaxChroma: 0.16,
+		Semantic:        0.75,
+		SemanticChroma:  0.14,
+	},
+	"light": {
+		Background:      0.97,
+		Surface:         0.92,
+		Border:          0.80,
+		Foreground:      0.28,
+		NeutralChroma:   0.015,
+		Accent:          0.52,
+		AccentMinChroma: 0.09,
+		AccentMaxChroma: 0.18,
+		Semantic:        0.55,
+		SemanticChroma:  0.15,
+	},
+}
+
+// achromaticHue tints the neutrals and accent of grey images
+const achromaticHue = 250
+
+// semanticHues are the fixed OKLCH hues of the status roles
+var semanticHues = map[string]float64{
+	"error":   25,
+	"warning": 80,
+	"success": 145,
+	"info":    220,
+}
+
+// Swatch is a dominant image color and the share of pixels it covers
+type Swatch struct {
+	Color      string  `json:"color"`
+	Population float64 `json:"population"`
+}
+
+// colorBox is a median-cut box of sampled pixels
+type colorBox struct {
+	pixels [][3]uint8
+}
+
+// CurrentWallpaper returns the image the wallpaper section shows: in
+// slideshow mode the image the slideshow state has on the first monitor,
+// otherwise the configured path, falling back to the first image of the
+// slideshow directory
+func CurrentWallpaper(config *ShellConfig) (string, error) {
+	wallpaper := &config.Wallpaper
+	if wallpaper.Mode == "slideshow" {
+		if state, err := LoadWallpaperState(GetWallpaperStatePath()); err == nil {
+			for _, monitor := range SlideshowMonitors(config) {
+				if current := state.Current(monitor); current != "" {
+					if _, err := os.Stat(current); err == nil {
+						return current, nil
+					}
+				}
+			}
+		}
+	}
+	if wallpaper.Path != "" {
+		return ExpandHome(wallpaper.Path), nil
+	}
+	if wallpaper.Mode != "slideshow" || wallpaper.Directory == "" {
+		return "", fmt.Errorf("no wallpaper configured (set wallpaper.path or pass an image)")
+	}
+
+	dir := ExpandHome(wallpaper.Directory)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read wallpaper directory: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && contains(paletteImageExts, strings.ToLower(filepath.Ext(entry.Name()))) {
+			return filepath.Join(dir, entry.Name()), nil
+		}
+	}
+	return "", fmt.Errorf("no PNG or JPEG images in %s", dir)
+}
+
+// LoadPaletteImage decodes a PNG or JPEG file
+func LoadPaletteImage(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image: %w", err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return img, nil
+}
+
+// ExtractSwatches finds up to n dominant colors with median cut, most
+// common first. Pixels are sampled on a fixed grid and ties are broken by
+// color, so the same image always gives the same swatches.
+func ExtractSwatches(img image.Image, n int) []Swatch {
+	if n < 1 {
+		n = 1
+	}
+
+	bounds := img.Bounds()
+	step := bounds.Dx()
+	if bounds.Dy() > step {
+		step = bounds.Dy()
+	}
+	step = step/paletteSampleSize + 1
+
+	pixels := make([][3]uint8, 0, (bounds.Dx()/step+1)*(bounds.Dy()/step+1))
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A < 128 {
+				continue
+			}
+			pixels = append(pixels, [3]uint8{c.R, c.G, c.B})
+		}
+	}
+	if len(pixels) == 0 {
+		return nil
+	}
+
+	boxes := []colorBox{{pixels: pixels}}
+	for len(boxes) < n {
+		// Split the box with the most pixels times its widest range
+		best, bestScore := -1, 0
+		for i, box := range boxes {
+			_, span := box.widestChannel()
+			if score := span * len(box.pixels); span > 0 && score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best < 0 {
+			break
+		}
+		a, b := boxes[best].split()
+		boxes = append(boxes[:best], append([]colorBox{a, b}, boxes[best+1:]...)...)
+	}
+
+	swatches := make([]Swatch, 0, len(boxes))
+	for _, box := range boxes {
+		swatches = append(swatches, Swatch{
+			Color:      box.average(),
+			Population: float64(len(box.pixels)) / float64(len(pixels)),
+		})
+	}
+	sort.SliceStable(swatches, func(i, j int) bool {
+		if swatches[i].Population != swatches[j].Population {
+			return swatches[i].Population > swatches[j].Population
+		}
+		return swatches[i].Color < swatches[j].Color
+	})
+	return swatches
+}
+
+// widestChannel returns the channel with the largest value range
+func (b colorBox) widestChannel() (channel, span int) {
+	for ch := 0; ch < 3; ch++ {
+		lo, hi := 255, 0
+		for _, p := range b.pixels {
+			v := int(p[ch])
+			if v < lo {
+				lo = v
+			}
+			if v > hi {
+				hi = v
+			}
+		}
+		if hi-lo > span {
+			channel, span = ch, hi-lo
+		}
+	}
+	return channel, span
+}
+
+// split cuts the box at the median of its widest channel
+func (b colorBox) split() (colorBox, colorBox) {
+	ch, _ := b.widestChannel()
+	pixels := b.pixels
+	sort.Slice(pixels, func(i, j int) bool {
+		if pixels[i][ch] != pixels[j][ch] {
+			return pixels[i][ch] < pixels[j][ch]
+		}
+		// Order by the full color so equal keys sort the same every time
+		if pixels[i][0] != pixels[j][0] {
+			return pixels[i][0] < pixels[j][0]
+		}
+		if pixels[i][1] != pixels[j][1] {
+			return pixels[i][1] < pixels[j][1]
+		}
+		return pixels[i][2] < pixels[j][2]
+	})
+
+	mid := len(pixels) / 2
+	// Keep runs of the same value together when possible
+	for mid < len(pixels)-1 && mid > 0 && pixels[mid][ch] == pixels[mid-1][ch] {
+		mid++
+	}
+	return colorBox{pixels: pixels[:mid]}, colorBox{pixels: pixels[mid:]}
+}
+
+// average returns the box's mean color
+func (b colorBox) average() string {
+	var sum [3]int
+	for _, p := range b.pixels {
+		sum[0] += int(p[0])
+		sum[1] += int(p[1])
+		sum[2] += int(p[2])
+	}
+	n := float64(len(b.pixels))
+	return fmt.Sprintf("#%02x%02x%02x",
+		uint8(math.Round(float64(sum[0])/n)),
+		uint8(math.Round(float64(sum[1])/n)),
+		uint8(math.Round(float64(sum[2])/n)))
+}
+
+// PaletteVariant returns the palette variant for the configuration at now:
+// the scheduled theme when appearance.schedule is on, otherwise light for a
+// light appearance.theme and dark for anything else (such as "auto")
+func PaletteVariant(config *ShellConfig, now time.Time) string {
+	if theme, _, err := ScheduledTheme(config.Appearance.Schedule, now); err == nil {
+		return theme
+	}
+	if config.Appearance.Theme == "light" {
+		return "light"
+	}
+	return "dark"
+}
+
+// SchemeFromSwatches assigns the color roles from dominant colors. The
+// neutrals (background, surface, border, foreground) take the most common
+// color's hue at fixed lightness levels for the variant; primary is the
+// most colorful common swatch and secondary the next one with a clearly
+// different hue. The status roles keep fixed hues at the accent's level.
+func SchemeFromSwatches(swatches []Swatch, variant string) (*ColorScheme, error) {
+	if len(swatches) == 0 {
+		return nil, fmt.Errorf("no colors to build a palette from")
+	}
+	levels, ok := paletteVariantLevels[variant]
+	if !ok {
+		return nil, fmt.Errorf("invalid variant: %s (use dark or light)", variant)
+	}
+
+	colors := make([]OKLCH, len(swatches))
+	for i, s := range swatches {
+		c, err := HexToOKLCH(s.Color)
+		if err != nil {
+			return nil, err
+		}
+		colors[i] = c
+	}
+
+	dominant := colors[0]
+	if dominant.C < 0.01 {
+		dominant.H = achromaticHue
+	}
+	neutral := func(lightness float64) string {
+		return OKLCH{L: lightness, C: math.Min(dominant.C, levels.NeutralChroma), H: dominant.H}.Hex()
+	}
+
+	// Score swatches by chroma weighted towards common colors
+	primary, secondary := -1, -1
+	score := func(i int) float64 {
+		return colors[i].C * (0.5 + math.Sqrt(swatches[i].Population))
+	}
+	for i := range colors {
+		if colors[i].C < 0.04 {
+			continue
+		}
+		if primary < 0 || score(i) > score(primary) {
+			primary = i
+		}
+	}
+
+	var primaryHue, primaryChroma float64
+	if primary >= 0 {
+		primaryHue, primaryChroma = colors[primary].H, colors[primary].C
+		for i := range colors {
+			if i == primary || colors[i].C < 0.04 || hueDistance(colors[i].H, primaryHue) < 40 {
+				continue
+			}
+			if secondary < 0 || score(i) > score(secondary) {
+				secondary = i
+			}
+		}
+	} else {
+		// A grey image: tint the accent with the neutrals' hue
+		primaryHue, primaryChroma = dominant.H, levels.AccentMinChroma
+	}
+
+	accent := func(hue, chroma float64) string {
+		chroma = math.Max(levels.AccentMinChroma, math.Min(levels.AccentMaxChroma, chroma))
+		return OKLCH{L: levels.Accent, C: chroma, H: hue}.Hex()
+	}
+	secondaryHue, secondaryChroma := math.Mod(primaryHue+60, 360), primaryChroma
+	if secondary >= 0 {
+		secondaryHue, secondaryChroma = colors[secondary].H, colors[secondary].C
+	}
+	semantic := func(role string) string {
+		return OKLCH{L: levels.Semantic, C: levels.SemanticChroma, H: semanticHues[role]}.Hex()
+	}
+
+	scheme := &ColorScheme{
+		Name:    WallpaperSchemeName,
+		Variant: variant,
+		Colors: ColorConfig{
+			Background: neutral(levels.Background),
+			Foreground: neutral(levels.Foreground),
+			Primary:    accent(primaryHue, primaryChroma),
+			Secondary:  accent(secondaryHue, secondaryChroma),
+			Success:    semantic("success"),
+			Warning:    semantic("warning"),
+			Error:      semantic("error"),
+			Info:       semantic("info"),
+			Surface:    neutral(levels.Surface),
+			Border:     neutral(levels.Border),
+		},
+	}
+	scheme.Accent = scheme.Colors.Primary
+	return scheme, nil
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestPaletteFixtures extracts the palette of each fixture image in
+// testdata/wallpapers and compares it with <name>.<variant>.json, in the
+// format of "scheme from-wallpaper --json"
+func TestPaletteFixtures(t *testing.T) {
+	var images []string
+	for _, pattern := range []string{"*.png", "*.jpg"} {
+		matches, err := filepath.Glob(filepath.Join("..", "testdata", "wallpapers", pattern))
+		if err != nil {
+			t.Fatal(err)
+		}
+		images = append(images, matches...)
+	}
+	if len(images) == 0 {
+		t.Fatal("no fixture images found")
+	}
+
+	for _, path := range images {
+		for _, variant := range SchemeVariants {
+			t.Run(filepath.Base(path)+"/"+variant, func(t *testing.T) {
+				img, err := LoadPaletteImage(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				swatches := ExtractSwatches(img, DefaultPaletteSize)
+				scheme, err := SchemeFromSwatches(swatches, variant)
+				if err != nil {
+					t.Fatalf("SchemeFromSwatches: %v", err)
+				}
+				scheme.Name = WallpaperSchemeName
+				scheme.Description = "Generated from " + filepath.Base(path)
+
+				got, err := json.MarshalIndent(map[string]interface{}{
+					"swatches": swatches,
+					"scheme":   scheme,
+				}, "", "  ")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				expectedPath := strings.TrimSuffix(path, filepath.Ext(path)) + "." + variant + ".json"
+				expected, err := os.ReadFile(expectedPath)
+				if err != nil {
+					t.Fatalf("failed to read expected palette: %v", err)
+				}
+				if diff := DiffLines(string(expected), string(got)+"\n"); len(diff) > 0 {
+					t.Errorf("palette differs from %s:\n%s", filepath.Base(expectedPath), strings.Join(diff, "\n"))
+				}
+			})
+		}
+	}
+}
+
+func TestPaletteVariant(t *testing.T) {
+	noon := time.Date(2024, 6, 21, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		theme    string
+		schedule ThemeSchedule
+		want     string
+	}{
+		{"light theme", "light", ThemeSchedule{}, "light"},
+		{"dark theme", "dark", ThemeSchedule{}, "dark"},
+		{"auto theme", "auto", ThemeSchedule{}, "dark"},
+		{"custom theme", "nord", ThemeSchedule{}, "dark"},
+		{"scheduled", "dark", ThemeSchedule{Mode: ScheduleFixed, Light: "07:00", Dark: "19:00"}, "light"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.Appearance.Theme = tt.theme
+			config.Appearance.Schedule = tt.schedule
+			if got := PaletteVariant(config, noon); got != tt.want {
+				t.Errorf("PaletteVariant = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemeFromSwatchesErrors(t *testing.T) {
+	if _, err := SchemeFromSwatches(nil, "dark"); err == nil {
+		t.Error("expected an error without swatches")
+	}
+	if _, err := SchemeFromSwatches([]Swatch{{Color: "#336699", Population: 1}}, "auto"); err == nil {
+		t.Error("expected an error for an unknown variant")
+	}
+}
@@ -0,0 +1,343 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ProfilesDirPath is the user profile directory location
+const ProfilesDirPath = "heimdall/profiles"
+
+// maxProfileDepth bounds extends chains
+const maxProfileDepth = 16
+
+var profileNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// UserProfile is a partial configuration overlay stored on disk.
+// The overlay is deep-merged onto the resolved Extends profile.
+type UserProfile struct {
+	Name        string                 `json:"-"`
+	Extends     string                 `json:"extends"`
+	Description string                 `json:"description,omitempty"`
+	Overlay     map[string]interface{} `json:"-"`
+}
+
+// ProfileInfo summarizes a profile for listings
+type ProfileInfo struct {
+	Name        string
+	Extends     string
+	Description string
+	Builtin     bool
+}
+
+// ProfileStore manages built-in and user-defined profiles
+type ProfileStore struct {
+	dir string
+}
+
+// NewProfileStore creates a store for profiles under dir
+func NewProfileStore(dir string) *ProfileStore {
+	return &ProfileStore{dir: dir}
+}
+
+// GetProfilesDir returns the user profile directory path
+func GetProfilesDir() string {
+	// Check environment variable first
+	if envPath := os.Getenv("HEIMDALL_PROFILES_DIR"); envPath != "" {
+		return envPath
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, _ := os.UserHomeDir()
+		configHome = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(configHome, ProfilesDirPath)
+}
+
+// LoadProfile resolves a built-in or user profile from the default store
+func LoadProfile(name string) (*ShellConfig, error) {
+	return NewProfileStore(GetProfilesDir()).Resolve(name)
+}
+
+// IsBuiltinProfile reports whether name is one of the built-in profiles
+func IsBuiltinProfile(name string) bool {
+	return contains(BuiltinProfiles, name)
+}
+
+// Dir returns the directory holding user profiles
+func (s *ProfileStore) Dir() string {
+	return s.dir
+}
+
+// List returns the built-in profiles followed by user profiles
+func (s *ProfileStore) List() ([]ProfileInfo, error) {
+	profiles := make([]ProfileInfo, 0)
+	for _, name := range BuiltinProfiles {
+		profiles = append(profiles, ProfileInfo{Name: name, Builtin: true})
+	}
+
+	names, err := s.userProfileNames()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		profile, err := s.Load(name)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, ProfileInfo{
+			Name:        name,
+			Extends:     profile.Extends,
+			Description: profile.Description,
+		})
+	}
+
+	return profiles, nil
+}
+
+// Names returns every known profile name
+func (s *ProfileStore) Names() []string {
+	names := append([]string{}, BuiltinProfiles...)
+	if user, err := s.userProfileNames(); err == nil {
+		names = append(names, user...)
+	}
+	return names
+}
+
+// Exists reports whether a built-in or user profile has the given name
+func (s *ProfileStore) Exists(name string) bool {
+	if IsBuiltinProfile(name) {
+		return true
+	}
+	_, err := os.Stat(s.profilePath(name))
+	return err == nil
+}
+
+// Resolve builds the full configuration for a profile by walking its extends chain
+func (s *ProfileStore) Resolve(name string) (*ShellConfig, error) {
+	chain := make([]*UserProfile, 0)
+	seen := make(map[string]bool)
+
+	current := name
+	for !IsBuiltinProfile(current) {
+		if seen[current] {
+			return nil, fmt.Errorf("profile %q has a circular extends chain", name)
+		}
+		if len(chain) >= maxProfileDepth {
+			return nil, fmt.Errorf("profile %q extends chain is deeper than %d", name, maxProfileDepth)
+		}
+		seen[current] = true
+
+		profile, err := s.Load(current)
+		if err != nil {
+			if os.IsNotExist(err) {
+				if current == name {
+					return nil, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(s.Names(), ", "))
+				}
+				return nil, fmt.Errorf("profile %q extends unknown profile %q", chain[len(chain)-1].Name, current)
+			}
+			return nil, err
+		}
+
+		chain = append(chain, profile)
+		current = profile.Extends
+	}
+
+	base := GetProfileConfig(current)
+	if len(chain) == 0 {
+		return base, nil
+	}
+
+	baseMap, err := structToMap(base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert profile to map: %w", err)
+	}
+
+	// Apply overlays from the built-in outwards
+	injector := NewPropertyInjector()
+	for i := len(chain) - 1; i >= 0; i-- {
+		injector.mergeDeep(baseMap, chain[i].Overlay)
+	}
+
+	resolved := &ShellConfig{}
+	if err := mapToStruct(baseMap, resolved); err != nil {
+		return nil, fmt.Errorf("failed to apply profile %q: %w", name, err)
+	}
+	resolved.Metadata.Profile = name
+	resolved.Extra = make(map[string]interface{})
+
+	return resolved, nil
+}
+
+// Load reads a user profile overlay
+func (s *ProfileStore) Load(name string) (*UserProfile, error) {
+	if !profileNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid profile name: %q", name)
+	}
+
+	data, err := os.ReadFile(s.profilePath(name))
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make(map[string]interface{})
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse profile %q: %w", name, err)
+	}
+
+	profile := &UserProfile{Name: name}
+	if extends, ok := raw["extends"].(string); ok {
+		profile.Extends = extends
+	}
+	if description, ok := raw["description"].(string); ok {
+		profile.Description = description
+	}
+	delete(raw, "extends")
+	delete(raw, "description")
+	profile.Overlay = raw
+
+	if profile.Extends == "" {
+		profile.Extends = "default"
+	}
+
+	return profile, nil
+}
+
+// Save writes a user profile overlay
+func (s *ProfileStore) Save(profile *UserProfile) error {
+	if !profileNamePattern.MatchString(profile.Name) {
+		return fmt.Errorf("invalid profile name: %q (use letters, digits, - and _)", profile.Name)
+	}
+	if IsBuiltinProfile(profile.Name) {
+		return fmt.Errorf("cannot overwrite built-in profile %q", profile.Name)
+	}
+	if profile.Extends == profile.Name {
+		return fmt.Errorf("profile %q cannot extend itself", profile.Name)
+	}
+	if !s.Exists(profile.Extends) {
+		return fmt.Errorf("unknown base profile %q", profile.Extends)
+	}
+
+	out := make(map[string]interface{}, len(profile.Overlay)+2)
+	for k, v := range profile.Overlay {
+		out[k] = v
+	}
+	out["extends"] = profile.Extends
+	if profile.Description != "" {
+		out["description"] = profile.Description
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal profile: %w", err)
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create profile directory: %w", err)
+	}
+
+	// Write to temporary file first (atomic operation)
+	path := s.profilePath(profile.Name)
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write profile: %w", err)
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to save profile: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a user profile. Profiles that others extend are kept
+// unless force is set.
+func (s *ProfileStore) Delete(name string, force bool) error {
+	if IsBuiltinProfile(name) {
+		return fmt.Errorf("cannot delete built-in profile %q", name)
+	}
+	if _, err := s.Load(name); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("unknown profile %q", name)
+		}
+		return err
+	}
+
+	if !force {
+		dependents := make([]string, 0)
+		names, err := s.userProfileNames()
+		if err != nil {
+			return err
+		}
+		for _, other := range names {
+			if profile, err := s.Load(other); err == nil && profile.Extends == name {
+				dependents = append(dependents, other)
+			}
+		}
+		if len(dependents) > 0 {
+			return fmt.Errorf("profile %q is extended by %s", name, strings.Join(dependents, ", "))
+		}
+	}
+
+	if err := os.Remove(s.profilePath(name)); err != nil {
+		return fmt.Errorf("failed to delete profile: %w", err)
+	}
+	return nil
+}
+
+// OverlayFromConfig builds a minimal overlay holding the values of config
+// that differ from the resolved base profile
+func (s *ProfileStore) OverlayFromConfig(config *ShellConfig, base string) (map[string]interface{}, error) {
+	baseConfig, err := s.Resolve(base)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := DiffConfigs(baseConfig, config, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	overlay := make(map[string]interface{})
+	for _, change := range changes {
+		if change.Path == "version" || strings.HasPrefix(change.Path, "metadata.") {
+			continue
+		}
+		SetMapPath(overlay, change.Path, change.New)
+	}
+	return overlay, nil
+}
+
+// userProfileNames lists the profile files in the store directory
+func (s *ProfileStore) userProfileNames() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("failed to read profile directory: %w", err)
+	}
+
+	names := make([]string, 0)
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		if entry.IsDir() || name == entry.Name() || !profileNamePattern.MatchString(name) || IsBuiltinProfile(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// profilePath returns the file path for a user profile
+func (s *ProfileStore) profilePath(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
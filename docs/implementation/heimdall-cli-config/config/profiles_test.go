@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeProfiles stores raw profile files in a fresh store
+func writeProfiles(t *testing.T, profiles map[string]string) *ProfileStore {
+	t.Helper()
+	dir := t.TempDir()
+	for name, data := range profiles {
+		if err := os.WriteFile(filepath.Join(dir, name+".json"), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewProfileStore(dir)
+}
+
+func TestResolveExtendsChain(t *testing.T) {
+	store := writeProfiles(t, map[string]string{
+		"laptop": `{"extends": "productivity", "bar": {"height": 24, "position": "bottom"}}`,
+		"work":   `{"extends": "laptop", "bar": {"height": 28}, "system": {"terminal": "foot"}}`,
+	})
+
+	resolved, err := store.Resolve("work")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	base := GetProfileConfig("productivity")
+
+	// Each overlay wins over the profiles it extends, and untouched values
+	// come from the built-in at the root of the chain
+	if resolved.Bar.Height != 28 {
+		t.Errorf("bar.height = %d, want 28 from work", resolved.Bar.Height)
+	}
+	if resolved.Bar.Position != "bottom" {
+		t.Errorf("bar.position = %q, want bottom from laptop", resolved.Bar.Position)
+	}
+	if resolved.System.Terminal != "foot" {
+		t.Errorf("system.terminal = %q, want foot", resolved.System.Terminal)
+	}
+	if resolved.Appearance.Colors != base.Appearance.Colors {
+		t.Error("appearance.colors differ from the productivity base")
+	}
+	if resolved.Metadata.Profile != "work" {
+		t.Errorf("metadata.profile = %q, want work", resolved.Metadata.Profile)
+	}
+
+	// Without extends a profile builds on default
+	store = writeProfiles(t, map[string]string{"plain": `{"bar": {"height": 40}}`})
+	plain, err := store.Resolve("plain")
+	if err != nil {
+		t.Fatalf("Resolve(plain): %v", err)
+	}
+	if want := GetProfileConfig("default").Bar.Position; plain.Bar.Position != want || plain.Bar.Height != 40 {
+		t.Errorf("plain bar = %d/%q, want 40/%q", plain.Bar.Height, plain.Bar.Position, want)
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	deep := make(map[string]string)
+	for i := 0; i <= maxProfileDepth; i++ {
+		deep[fmt.Sprintf("p%d", i)] = fmt.Sprintf(`{"extends": "p%d"}`, i+1)
+	}
+	deep[fmt.Sprintf("p%d", maxProfileDepth+1)] = `{"extends": "default"}`
+
+	tests := []struct {
+		name     string
+		profiles map[string]string
+		resolve  string
+		want     string
+	}{
+		{"cycle", map[string]string{"a": `{"extends": "b"}`, "b": `{"extends": "a"}`}, "a", "circular extends chain"},
+		{"self reference", map[string]string{"a": `{"extends": "a"}`}, "a", "circular extends chain"},
+		{"too deep", deep, "p0", fmt.Sprintf("deeper than %d", maxProfileDepth)},
+		{"unknown base", map[string]string{"a": `{"extends": "gone"}`}, "a", `extends unknown profile "gone"`},
+		{"unknown profile", nil, "gone", `unknown profile "gone"`},
+		{"invalid name", nil, "../etc", "invalid profile name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := writeProfiles(t, tt.profiles).Resolve(tt.resolve)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Resolve(%q) error = %v, want %q", tt.resolve, err, tt.want)
+			}
+		})
+	}
+
+	// A chain exactly at the limit still resolves
+	limit := make(map[string]string)
+	for i := 0; i < maxProfileDepth; i++ {
+		limit[fmt.Sprintf("p%d", i)] = fmt.Sprintf(`{"extends": "p%d"}`, i+1)
+	}
+	limit[fmt.Sprintf("p%d", maxProfileDepth-1)] = `{"extends": "default"}`
+	if _, err := writeProfiles(t, limit).Resolve("p0"); err != nil {
+		t.Errorf("Resolve at depth %d: %v", maxProfileDepth, err)
+	}
+}
+
+func TestSaveRejectsBadExtends(t *testing.T) {
+	store := writeProfiles(t, nil)
+	for _, profile := range []*UserProfile{
+		{Name: "loop", Extends: "loop"},
+		{Name: "orphan", Extends: "gone"},
+	} {
+		if err := store.Save(profile); err == nil {
+			t.Errorf("Save(%s extends %s) succeeded, want an error", profile.Name, profile.Extends)
+		}
+	}
+}
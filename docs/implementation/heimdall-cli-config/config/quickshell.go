@@ -0,0 +1,196 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// QuickshellConfigPath is the Quickshell config location relative to the config home
+const QuickshellConfigPath = "quickshell/config/default.json"
+
+// Quickshell scale tables at scale 1, as defined by the Appearance QML singleton
+var (
+	quickshellDurations = map[string]float64{
+		"small":                    200,
+		"normal":                   400,
+		"large":                    600,
+		"extraLarge":               1000,
+		"expressiveFastSpatial":    350,
+		"expressiveDefaultSpatial": 500,
+		"expressiveEffects":        200,
+	}
+	quickshellFontSizes = map[string]float64{
+		"small":      11,
+		"smaller":    12,
+		"normal":     13,
+		"larger":     15,
+		"large":      18,
+		"extraLarge": 28,
+	}
+	quickshellRounding = map[string]float64{
+		"small":  12,
+		"normal": 17,
+		"large":  25,
+	}
+	quickshellPadding = map[string]float64{
+		"small":   5,
+		"smaller": 7,
+		"normal":  10,
+		"larger":  12,
+		"large":   15,
+	}
+	quickshellSpacing = map[string]float64{
+		"small":   7,
+		"smaller": 10,
+		"normal":  12,
+		"larger":  15,
+		"large":   20,
+	}
+)
+
+// animationScales maps appearance.animationSpeed to a duration multiplier
+var animationScales = map[string]float64{
+	"slow":   1.5,
+	"normal": 1,
+	"fast":   0.6,
+}
+
+// GetQuickshellConfigPath returns the Quickshell default.json location.
+// HEIMDALL_QUICKSHELL_CONFIG overrides it.
+func GetQuickshellConfigPath() string {
+	if envPath := os.Getenv("HEIMDALL_QUICKSHELL_CONFIG"); envPath != "" {
+		return envPath
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, _ := os.UserHomeDir()
+		configHome = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(configHome, QuickshellConfigPath)
+}
+
+// QuickshellDocument maps the configuration onto the sections of Quickshell's
+// default.json that heimdall manages. Each scale table is written pre-scaled
+// so that the "normal" entry equals the configured value:
+//
+//	appearance.animationSpeed  → appearance.anim.durations (0 when animations are off)
+//	system.font.family         → appearance.font.family.mono and .sans
+//	system.font.size           → appearance.font.size
+//	appearance.borderRadius    → appearance.rounding
+//	bar.padding (left/right)   → appearance.padding
+//	bar.spacing                → appearance.spacing
+//	appearance.transparency    → appearance.transparency
+//	bar.height                 → bar.sizes.innerHeight
+//	monitors.<output>.bar      → bar.excludedScreens (when outputs are configured)
+func QuickshellDocument(config *ShellConfig) map[string]interface{} {
+	app := &config.Appearance
+
+	speed, ok := animationScales[app.AnimationSpeed]
+	if !ok {
+		speed = 1
+	}
+	if !app.Animations {
+		speed = 0
+	}
+
+	// "full" is a pill radius and does not scale
+	rounding := scaleTable(quickshellRounding, ratio(app.BorderRadius, quickshellRounding["normal"]))
+	rounding["full"] = 1000
+
+	appearance := map[string]interface{}{
+		"anim": map[string]interface{}{
+			"durations": scaleTable(quickshellDurations, speed),
+		},
+		"rounding": rounding,
+		"padding": scaleTable(quickshellPadding,
+			ratio((config.Bar.Padding.Left+config.Bar.Padding.Right)/2, quickshellPadding["normal"])),
+		"spacing": scaleTable(quickshellSpacing, ratio(config.Bar.Spacing, quickshellSpacing["normal"])),
+		"transparency": map[string]interface{}{
+			"enabled": app.Transparency < 1,
+			"base":    app.Transparency,
+		},
+	}
+
+	font := map[string]interface{}{
+		"size": scaleTable(quickshellFontSizes, ratio(config.System.Font.Size, quickshellFontSizes["normal"])),
+	}
+	if family := config.System.Font.Family; family != "" {
+		font["family"] = map[string]interface{}{
+			"mono": family,
+			"sans": family,
+		}
+	}
+	appearance["font"] = font
+
+	doc := map[string]interface{}{
+		"appearance": appearance,
+	}
+	bar := make(map[string]interface{})
+	if config.Bar.Height > 0 {
+		bar["sizes"] = map[string]interface{}{
+			"innerHeight": config.Bar.Height,
+		}
+	}
+	if names := config.Monitors.MonitorNames(); len(names) > 0 {
+		// Listed even when empty so re-enabling the bar clears the old list
+		excluded := make([]interface{}, 0)
+		for _, name := range names {
+			if !config.Monitor(name).ShowsBar() {
+				excluded = append(excluded, name)
+			}
+		}
+		bar["excludedScreens"] = excluded
+	}
+	if len(bar) > 0 {
+		doc["bar"] = bar
+	}
+
+	return doc
+}
+
+// RenderQuickshell renders default.json for config. The managed sections are
+// deep-merged over existing (the current file, or nil) so settings heimdall
+// does not own are kept. Keys are sorted, matching Quickshell's own output.
+func RenderQuickshell(config *ShellConfig, existing []byte) ([]byte, error) {
+	doc := make(map[string]interface{})
+	if len(existing) > 0 {
+		if err := json.Unmarshal(existing, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse existing Quickshell config: %w", err)
+		}
+	}
+
+	managed, err := deepCopyMap(QuickshellDocument(config))
+	if err != nil {
+		return nil, err
+	}
+	NewPropertyInjector().mergeDeep(doc, managed)
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Quickshell config: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// scaleTable multiplies every entry of a scale table, rounding to whole units
+func scaleTable(table map[string]float64, scale float64) map[string]interface{} {
+	scaled := make(map[string]interface{}, len(table)+1)
+	for key, value := range table {
+		scaled[key] = int(math.Round(value * scale))
+	}
+	scaled["scale"] = scale
+	return scaled
+}
+
+// ratio returns value/normal rounded to two decimals, or 1 when value is unset
+func ratio(value int, normal float64) float64 {
+	if value <= 0 {
+		return 1
+	}
+	return math.Round(float64(value)/normal*100) / 100
+}
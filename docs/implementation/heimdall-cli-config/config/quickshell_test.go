@@ -0,0 +1,52 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRenderQuickshellGolden renders the built-in profiles from scratch and
+// compares them with testdata/quickshell. Regenerate intentional changes
+// with "heimdall-cli render quickshell --profile <name> --fresh -o <file>".
+func TestRenderQuickshellGolden(t *testing.T) {
+	for _, profile := range []string{"default", "minimal", "gaming"} {
+		t.Run(profile, func(t *testing.T) {
+			rendered, err := RenderQuickshell(GetProfileConfig(profile), nil)
+			if err != nil {
+				t.Fatalf("RenderQuickshell: %v", err)
+			}
+
+			golden, err := os.ReadFile(filepath.Join("..", "testdata", "quickshell", profile+".json"))
+			if err != nil {
+				t.Fatalf("failed to read golden file: %v", err)
+			}
+			if diff := DiffLines(string(golden), string(rendered)); len(diff) > 0 {
+				t.Errorf("output differs from the golden file:\n%s", strings.Join(diff, "\n"))
+			}
+		})
+	}
+}
+
+func TestRenderQuickshellKeepsUnmanagedKeys(t *testing.T) {
+	existing := []byte(`{"launcher": {"maxShown": 7}, "bar": {"sizes": {"innerHeight": 1}, "workspaces": {"shown": 5}}}`)
+	rendered, err := RenderQuickshell(GetDefaultConfig(), existing)
+	if err != nil {
+		t.Fatalf("RenderQuickshell: %v", err)
+	}
+
+	doc, err := DecodeMap(rendered, FormatJSON)
+	if err != nil {
+		t.Fatalf("DecodeMap: %v", err)
+	}
+	for path, want := range map[string]interface{}{
+		"launcher.maxShown":     7.0,
+		"bar.workspaces.shown":  5.0,
+		"bar.sizes.innerHeight": float64(GetDefaultConfig().Bar.Height),
+	} {
+		if got, _ := LookupPath(doc, path); got != want {
+			t.Errorf("%s = %v, want %v", path, got, want)
+		}
+	}
+}
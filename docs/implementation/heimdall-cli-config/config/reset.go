@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ResetPlan describes the changes a reset would make
+type ResetPlan struct {
+	Changes []PathChange
+	Locked  []string
+}
+
+// PlanReset computes the changes needed to restore paths to their values in
+// source. Locked paths are skipped unless force is set. Version and metadata
+// are managed by heimdall-cli and never reset.
+func (i *PropertyInjector) PlanReset(config, source *ShellConfig, paths []string, force bool) (*ResetPlan, error) {
+	configMap, err := i.structToMap(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert config to map: %w", err)
+	}
+	sourceMap, err := i.structToMap(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert profile to map: %w", err)
+	}
+
+	locked := i.getUserLocks(config)
+	plan := &ResetPlan{
+		Changes: make([]PathChange, 0),
+		Locked:  make([]string, 0),
+	}
+
+	for _, path := range paths {
+		if path == "version" || strings.HasPrefix(path, "metadata.") {
+			continue
+		}
+
+		current := i.getValueByPath(configMap, path)
+		target := i.getValueByPath(sourceMap, path)
+		if reflect.DeepEqual(current, target) {
+			continue
+		}
+
+		if !force && i.isUserLocked(path, locked) {
+			plan.Locked = append(plan.Locked, path)
+			continue
+		}
+
+		plan.Changes = append(plan.Changes, PathChange{Path: path, Old: current, New: target})
+	}
+
+	return plan, nil
+}
+
+// ApplyChanges writes each change's new value into the configuration
+func (i *PropertyInjector) ApplyChanges(config *ShellConfig, changes []PathChange) error {
+	configMap, err := i.structToMap(config)
+	if err != nil {
+		return fmt.Errorf("failed to convert config to map: %w", err)
+	}
+
+	for _, change := range changes {
+		if err := i.injectProperty(configMap, change.Path, change.New, Replace); err != nil {
+			return fmt.Errorf("failed to reset %s: %w", change.Path, err)
+		}
+	}
+
+	// Decode into a fresh struct so removed map entries do not linger
+	updated := &ShellConfig{}
+	if err := i.mapToStruct(configMap, updated); err != nil {
+		return fmt.Errorf("failed to convert map to config: %w", err)
+	}
+	updated.Extra = config.Extra
+	*config = *updated
+
+	return nil
+}
@@ -5,7 +5,7 @@ import (
 )
 
 // CurrentSchemaVersion defines the current configuration schema version
-const CurrentSchemaVersion = "1.0.0"
+const CurrentSchemaVersion = "1.1.0"
 
 // ShellConfig represents the complete shell configuration
 type ShellConfig struct {
@@ -18,7 +18,10 @@ type ShellConfig struct {
 	Services   ServicesConfig   `json:"services"`
 	Commands   CommandsConfig   `json:"commands"`
 	Wallpaper  WallpaperConfig  `json:"wallpaper"`
+	Monitors   MonitorsConfig   `json:"monitors"`
 	HotReload  HotReloadConfig  `json:"hotReload"`
+	Profiles   ProfilesConfig   `json:"profiles"`
+	Render     RenderConfig     `json:"render"`
 
 	// Preserve unknown fields for forward compatibility
 	Extra map[string]interface{} `json:"-"`
@@ -67,17 +70,44 @@ type FontConfig struct {
 
 // AppearanceConfig contains appearance settings
 type AppearanceConfig struct {
-	Theme          string      `json:"theme"`
-	ColorScheme    string      `json:"colorScheme"`
-	AccentColor    string      `json:"accentColor"`
-	Transparency   float64     `json:"transparency"`
-	BlurRadius     int         `json:"blurRadius"`
-	BorderRadius   int         `json:"borderRadius"`
-	BorderWidth    int         `json:"borderWidth"`
-	Shadows        bool        `json:"shadows"`
-	Animations     bool        `json:"animations"`
-	AnimationSpeed string      `json:"animationSpeed"`
-	Colors         ColorConfig `json:"colors"`
+	Theme          string        `json:"theme"`
+	ColorScheme    string        `json:"colorScheme"`
+	AccentColor    string        `json:"accentColor"`
+	Transparency   float64       `json:"transparency"`
+	BlurRadius     int           `json:"blurRadius"`
+	BorderRadius   int           `json:"borderRadius"`
+	BorderWidth    int           `json:"borderWidth"`
+	Shadows        bool          `json:"shadows"`
+	Animations     bool          `json:"animations"`
+	AnimationSpeed string        `json:"animationSpeed"`
+	Contrast       string        `json:"contrast"`
+	Colors         ColorConfig   `json:"colors"`
+	Palettes       ThemePalettes `json:"palettes"`
+	Schedule       ThemeSchedule `json:"schedule"`
+}
+
+// ThemePalettes holds the palette to switch to for each theme variant
+type ThemePalettes struct {
+	Light ThemePalette `json:"light"`
+	Dark  ThemePalette `json:"dark"`
+}
+
+// ThemePalette is applied when its variant becomes active: the color scheme
+// first, then any non-empty colors and accent on top
+type ThemePalette struct {
+	ColorScheme string      `json:"colorScheme,omitempty"`
+	AccentColor string      `json:"accentColor,omitempty"`
+	Colors      ColorConfig `json:"colors"`
+}
+
+// ThemeSchedule switches appearance.theme by time of day, at fixed times
+// or at sunrise and sunset for a location
+type ThemeSchedule struct {
+	Mode      string  `json:"mode"`
+	Light     string  `json:"light"`
+	Dark      string  `json:"dark"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
 }
 
 // ColorConfig defines color settings
@@ -200,15 +230,17 @@ type PowerConfig struct {
 
 // DisplayConfig defines display settings
 type DisplayConfig struct {
-	Brightness     int     `json:"brightness"`
-	NightLight     bool    `json:"nightLight"`
-	NightLightTemp int     `json:"nightLightTemp"`
-	AutoBrightness bool    `json:"autoBrightness"`
-	DPMS           bool    `json:"dpms"`
-	DPMSTimeout    int     `json:"dpmsTimeout"`
-	Resolution     string  `json:"resolution"`
-	RefreshRate    int     `json:"refreshRate"`
-	Scale          float64 `json:"scale"`
+	Brightness     int  `json:"brightness"`
+	NightLight     bool `json:"nightLight"`
+	NightLightTemp int  `json:"nightLightTemp"`
+	AutoBrightness bool `json:"autoBrightness"`
+	DPMS           bool `json:"dpms"`
+	DPMSTimeout    int  `json:"dpmsTimeout"`
+
+	// Deprecated: moved to monitors["*"] by the 1.1.0 migration
+	Resolution  string  `json:"resolution,omitempty"`
+	RefreshRate int     `json:"refreshRate,omitempty"`
+	Scale       float64 `json:"scale,omitempty"`
 }
 
 // CommandsConfig contains custom command definitions
@@ -228,17 +260,20 @@ type CommandDef struct {
 
 // WallpaperConfig contains wallpaper settings
 type WallpaperConfig struct {
-	Mode         string   `json:"mode"`
-	Path         string   `json:"path"`
-	Directory    string   `json:"directory"`
-	Interval     int      `json:"interval"`
-	Random       bool     `json:"random"`
-	Blur         bool     `json:"blur"`
-	BlurStrength int      `json:"blurStrength"`
-	Dim          bool     `json:"dim"`
-	DimStrength  float64  `json:"dimStrength"`
-	FillMode     string   `json:"fillMode"`
-	Monitors     []string `json:"monitors"`
+	Mode         string  `json:"mode"`
+	Path         string  `json:"path"`
+	Directory    string  `json:"directory"`
+	Interval     int     `json:"interval"`
+	Random       bool    `json:"random"`
+	Blur         bool    `json:"blur"`
+	BlurStrength int     `json:"blurStrength"`
+	Dim          bool    `json:"dim"`
+	DimStrength  float64 `json:"dimStrength"`
+	FillMode     string  `json:"fillMode"`
+	Backend      string  `json:"backend"`
+
+	// Deprecated: each output became a monitors entry in the 1.1.0 migration
+	Monitors []string `json:"monitors,omitempty"`
 }
 
 // HotReloadConfig contains hot reload settings
@@ -250,3 +285,71 @@ type HotReloadConfig struct {
 	MaxRetries     int      `json:"maxRetries"`
 	RetryDelay     int      `json:"retryDelay"`
 }
+
+// MonitorsConfig maps output names (DP-1, eDP-1) to their settings. The "*"
+// entry holds defaults for every output.
+type MonitorsConfig map[string]MonitorConfig
+
+// MonitorConfig configures one output. Unset fields fall back to the "*"
+// entry; position and wallpaper are per output only.
+type MonitorConfig struct {
+	Resolution  string           `json:"resolution,omitempty"`
+	RefreshRate int              `json:"refreshRate,omitempty"`
+	Scale       float64          `json:"scale,omitempty"`
+	Position    *MonitorPosition `json:"position,omitempty"`
+	Transform   string           `json:"transform,omitempty"`
+	Wallpaper   string           `json:"wallpaper,omitempty"`
+	Bar         *bool            `json:"bar,omitempty"`
+}
+
+// MonitorPosition is an output's top-left corner in the compositor's
+// layout, in logical (scaled) pixels
+type MonitorPosition struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// RenderConfig lists the theme files generated by heimdall-cli render
+type RenderConfig struct {
+	Targets map[string]RenderTarget `json:"targets"`
+}
+
+// RenderTarget configures one generated file. Built-in targets supply a
+// default template, output path and reload command.
+type RenderTarget struct {
+	Enabled  bool   `json:"enabled"`
+	Template string `json:"template,omitempty"`
+	Output   string `json:"output,omitempty"`
+	Reload   string `json:"reload,omitempty"`
+}
+
+// ProfilesConfig contains profile selection settings
+type ProfilesConfig struct {
+	Auto AutoProfileConfig `json:"auto"`
+}
+
+// AutoProfileConfig defines rules for selecting a profile from the environment.
+// Rules are evaluated in order and the first match wins.
+type AutoProfileConfig struct {
+	Interval int           `json:"interval"`
+	Fallback string        `json:"fallback"`
+	Rules    []ProfileRule `json:"rules"`
+}
+
+// ProfileRule selects a profile when all of its conditions hold
+type ProfileRule struct {
+	Name    string         `json:"name"`
+	Profile string         `json:"profile"`
+	When    RuleConditions `json:"when"`
+}
+
+// RuleConditions lists the conditions of a profile rule. Unset conditions
+// always match.
+type RuleConditions struct {
+	Hostname        []string          `json:"hostname,omitempty"`
+	Power           string            `json:"power,omitempty"`
+	Monitors        []string          `json:"monitors,omitempty"`
+	ExternalMonitor *bool             `json:"externalMonitor,omitempty"`
+	Time            string            `json:"time,omitempty"`
+	Env             map[string]string `json:"env,omitempty"`
+}
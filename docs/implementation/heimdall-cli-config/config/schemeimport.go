@@ -0,0 +1,328 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Scheme file formats accepted by ParseSchemeFile
+const (
+	SchemeFormatBase16    = "base16"
+	SchemeFormatKitty     = "kitty"
+	SchemeFormatAlacritty = "alacritty"
+)
+
+// SchemeFormats lists the importable scheme formats
+var SchemeFormats = []string{SchemeFormatBase16, SchemeFormatKitty, SchemeFormatAlacritty}
+
+// base16Roles maps ColorConfig roles to Base16 slots. Base24 files use the
+// same slots; their extra base10-base17 entries are ignored.
+var base16Roles = map[string]string{
+	"background": "base00",
+	"surface":    "base02",
+	"border":     "base03",
+	"foreground": "base05",
+	"error":      "base08",
+	"warning":    "base0A",
+	"success":    "base0B",
+	"info":       "base0C",
+	"primary":    "base0D",
+	"secondary":  "base0E",
+}
+
+// ansiRoles maps ColorConfig roles to the 16 ANSI colors of terminal
+// themes (0-7 normal, 8-15 bright); background and foreground come from the
+// theme's own entries. Light themes take the surface from color7 (white)
+// since their color0 is dark.
+var ansiRoles = map[string]int{
+	"surface":   0,
+	"error":     1,
+	"success":   2,
+	"warning":   3,
+	"primary":   4,
+	"secondary": 5,
+	"info":      6,
+	"border":    8,
+}
+
+// alacrittyColorNames are the keys of alacritty's normal and bright tables
+var alacrittyColorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+var schemeNameCleaner = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// DetectSchemeFormat picks a scheme format from a file name
+func DetectSchemeFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return SchemeFormatBase16, nil
+	case ".conf":
+		return SchemeFormatKitty, nil
+	case ".toml":
+		return SchemeFormatAlacritty, nil
+	default:
+		return "", fmt.Errorf("cannot detect scheme format of %s (use --format %s)", path, strings.Join(SchemeFormats, ", "))
+	}
+}
+
+// ParseSchemeFile parses a scheme in the given format. The name comes from
+// the file's metadata when it has any, otherwise from the file name; the
+// variant comes from the metadata or the background's luminance.
+func ParseSchemeFile(data []byte, format, filename string) (*ColorScheme, error) {
+	var scheme *ColorScheme
+	var err error
+
+	switch format {
+	case SchemeFormatBase16:
+		scheme, err = ParseBase16Scheme(data)
+	case SchemeFormatKitty:
+		scheme, err = ParseKittyScheme(data)
+	case SchemeFormatAlacritty:
+		scheme, err = ParseAlacrittyScheme(data)
+	default:
+		return nil, fmt.Errorf("unknown scheme format: %s (use %s)", format, strings.Join(SchemeFormats, ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if scheme.Name == "" {
+		base := filepath.Base(filename)
+		scheme.Name = SchemeSlug(strings.TrimSuffix(base, filepath.Ext(base)))
+	}
+	if scheme.Variant == "" {
+		scheme.Variant = "dark"
+		if luminance(scheme.Colors.Background) > 0.5 {
+			scheme.Variant = "light"
+		}
+	}
+	if err := ValidateScheme(scheme); err != nil {
+		return nil, err
+	}
+	return scheme, nil
+}
+
+// ParseBase16Scheme parses Base16 or Base24 YAML, either the classic flat
+// layout (scheme, author, base00...) or the tinted-theming layout (name,
+// variant, palette.base00...)
+func ParseBase16Scheme(data []byte) (*ColorScheme, error) {
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse Base16 YAML: %w", err)
+	}
+
+	palette := raw
+	if nested, ok := raw["palette"].(map[string]interface{}); ok {
+		palette = nested
+	}
+
+	slots := make(map[string]string)
+	for key, value := range palette {
+		if s, ok := value.(string); ok {
+			slots[strings.ToLower(key)] = s
+		}
+	}
+
+	roles := make([]string, 0, len(base16Roles))
+	for role := range base16Roles {
+		roles = append(roles, role)
+	}
+	// Report the first missing slot, not a random one
+	sort.Slice(roles, func(i, j int) bool { return base16Roles[roles[i]] < base16Roles[roles[j]] })
+
+	scheme := &ColorScheme{}
+	colors := make(map[string]string)
+	for _, role := range roles {
+		slot := base16Roles[role]
+		value, ok := slots[strings.ToLower(slot)]
+		if !ok {
+			return nil, fmt.Errorf("Base16 scheme is missing %s", slot)
+		}
+		color, err := importColor(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", slot, err)
+		}
+		colors[role] = color
+	}
+	scheme.Colors = colorConfigFromRoles(colors)
+	scheme.Accent = scheme.Colors.Primary
+
+	title := stringField(raw, "name")
+	if title == "" {
+		title = stringField(raw, "scheme")
+	}
+	scheme.Name = SchemeSlug(title)
+	scheme.Description = title
+	if author := stringField(raw, "author"); author != "" {
+		scheme.Description = strings.TrimSpace(title + " by " + author)
+	}
+	if variant := stringField(raw, "variant"); contains(SchemeVariants, variant) {
+		scheme.Variant = variant
+	}
+
+	return scheme, nil
+}
+
+// ParseKittyScheme parses a kitty color file ("key value" lines). The
+// accent is active_border_color, falling back to color4. The name and
+// description come from "## name:" and "## author:" comments.
+func ParseKittyScheme(data []byte) (*ColorScheme, error) {
+	values := make(map[string]string)
+	title, author := "", ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			meta := strings.TrimSpace(strings.TrimLeft(line, "#"))
+			if key, value, ok := strings.Cut(meta, ":"); ok {
+				switch strings.ToLower(strings.TrimSpace(key)) {
+				case "name":
+					title = strings.TrimSpace(value)
+				case "author":
+					author = strings.TrimSpace(value)
+				}
+			}
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) >= 2 {
+			values[fields[0]] = fields[1]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read kitty colors: %w", err)
+	}
+
+	ansi := make([]string, 16)
+	for i := range ansi {
+		ansi[i] = values[fmt.Sprintf("color%d", i)]
+	}
+
+	scheme, err := schemeFromANSI(values["background"], values["foreground"], ansi, "kitty")
+	if err != nil {
+		return nil, err
+	}
+
+	if border, ok := values["active_border_color"]; ok && border != "none" {
+		if accent, err := importColor(border); err == nil {
+			scheme.Accent = accent
+		}
+	}
+
+	scheme.Name = SchemeSlug(title)
+	scheme.Description = title
+	if author != "" {
+		scheme.Description = strings.TrimSpace(title + " by " + author)
+	}
+	return scheme, nil
+}
+
+// ParseAlacrittyScheme parses an alacritty TOML theme ([colors.primary],
+// [colors.normal] and [colors.bright])
+func ParseAlacrittyScheme(data []byte) (*ColorScheme, error) {
+	var raw struct {
+		Colors struct {
+			Primary map[string]string `toml:"primary"`
+			Normal  map[string]string `toml:"normal"`
+			Bright  map[string]string `toml:"bright"`
+		} `toml:"colors"`
+	}
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse alacritty TOML: %w", err)
+	}
+
+	ansi := make([]string, 16)
+	for i, name := range alacrittyColorNames {
+		ansi[i] = raw.Colors.Normal[name]
+		ansi[i+8] = raw.Colors.Bright[name]
+	}
+
+	return schemeFromANSI(raw.Colors.Primary["background"], raw.Colors.Primary["foreground"], ansi, "alacritty")
+}
+
+// schemeFromANSI maps a terminal palette onto ColorConfig roles
+func schemeFromANSI(background, foreground string, ansi []string, source string) (*ColorScheme, error) {
+	colors := make(map[string]string)
+
+	for role, value := range map[string]string{"background": background, "foreground": foreground} {
+		if value == "" {
+			return nil, fmt.Errorf("%s theme has no %s color", source, role)
+		}
+		color, err := importColor(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", role, err)
+		}
+		colors[role] = color
+	}
+
+	variant := "dark"
+	if luminance(colors["background"]) > 0.5 {
+		variant = "light"
+	}
+
+	for role, index := range ansiRoles {
+		if role == "surface" && variant == "light" {
+			index = 7
+		}
+		if ansi[index] == "" {
+			return nil, fmt.Errorf("%s theme has no color%d", source, index)
+		}
+		color, err := importColor(ansi[index])
+		if err != nil {
+			return nil, fmt.Errorf("color%d: %w", index, err)
+		}
+		colors[role] = color
+	}
+
+	scheme := &ColorScheme{Variant: variant, Colors: colorConfigFromRoles(colors)}
+	scheme.Accent = scheme.Colors.Primary
+	return scheme, nil
+}
+
+// colorConfigFromRoles builds a ColorConfig from role names
+func colorConfigFromRoles(roles map[string]string) ColorConfig {
+	return ColorConfig{
+		Background: roles["background"],
+		Foreground: roles["foreground"],
+		Primary:    roles["primary"],
+		Secondary:  roles["secondary"],
+		Success:    roles["success"],
+		Warning:    roles["warning"],
+		Error:      roles["error"],
+		Info:       roles["info"],
+		Surface:    roles["surface"],
+		Border:     roles["border"],
+	}
+}
+
+// importColor reads a theme file color, which may be hex without "#" or
+// written 0xrrggbb, as canonical hex
+func importColor(value string) (string, error) {
+	v := strings.Trim(strings.TrimSpace(value), `"'`)
+	if hex := strings.TrimPrefix(strings.ToLower(v), "0x"); isHexDigits(hex) {
+		v = "#" + hex
+	}
+	return NormalizeColor(v)
+}
+
+// SchemeSlug turns a display name into a scheme name: "Tokyo Night" → "tokyo-night"
+func SchemeSlug(title string) string {
+	slug := strings.Join(strings.Fields(strings.ToLower(title)), "-")
+	slug = schemeNameCleaner.ReplaceAllString(slug, "")
+	return strings.Trim(slug, "-_")
+}
+
+// stringField returns a string value from a decoded map
+func stringField(values map[string]interface{}, key string) string {
+	s, _ := values[key].(string)
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestParseSchemeFileSamples maps each sample in testdata/schemes and
+// compares the result with its .expected.json file
+func TestParseSchemeFileSamples(t *testing.T) {
+	var samples []string
+	for _, pattern := range []string{"*.yaml", "*.conf", "*.toml"} {
+		matches, err := filepath.Glob(filepath.Join("..", "testdata", "schemes", pattern))
+		if err != nil {
+			t.Fatal(err)
+		}
+		samples = append(samples, matches...)
+	}
+	if len(samples) == 0 {
+		t.Fatal("no sample schemes found")
+	}
+
+	for _, sample := range samples {
+		t.Run(filepath.Base(sample), func(t *testing.T) {
+			data, err := os.ReadFile(sample)
+			if err != nil {
+				t.Fatal(err)
+			}
+			format, err := DetectSchemeFormat(sample)
+			if err != nil {
+				t.Fatal(err)
+			}
+			scheme, err := ParseSchemeFile(data, format, sample)
+			if err != nil {
+				t.Fatalf("ParseSchemeFile: %v", err)
+			}
+			got, err := json.MarshalIndent(scheme, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected, err := os.ReadFile(strings.TrimSuffix(sample, filepath.Ext(sample)) + ".expected.json")
+			if err != nil {
+				t.Fatalf("failed to read expected mapping: %v", err)
+			}
+			if diff := DiffLines(string(expected), string(got)+"\n"); len(diff) > 0 {
+				t.Errorf("mapping differs from the expected file:\n%s", strings.Join(diff, "\n"))
+			}
+		})
+	}
+}
+
+func TestParseSchemeFileErrors(t *testing.T) {
+	tests := map[string]struct {
+		data   string
+		format string
+	}{
+		"base16 missing colors": {"scheme: Broken\nbase00: \"000000\"\n", SchemeFormatBase16},
+		"kitty bad color":       {"background #zzzzzz\nforeground #ffffff\n", SchemeFormatKitty},
+		"unknown format":        {"", "vim"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseSchemeFile([]byte(tt.data), tt.format, "broken"); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestSchemeSlug(t *testing.T) {
+	tests := map[string]string{
+		"Solarized Light": "solarized-light",
+		"One  Light!":     "one-light",
+		"gruvbox_dark":    "gruvbox_dark",
+	}
+	for title, want := range tests {
+		if got := SchemeSlug(title); got != want {
+			t.Errorf("SchemeSlug(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
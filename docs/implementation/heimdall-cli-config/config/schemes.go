@@ -0,0 +1,454 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SchemesDirPath is the user scheme directory location
+const SchemesDirPath = "heimdall/schemes"
+
+// ColorScheme is a named palette for appearance.colors
+type ColorScheme struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Variant     string      `json:"variant"`
+	Accent      string      `json:"accent"`
+	Colors      ColorConfig `json:"colors"`
+
+	Builtin bool   `json:"-"`
+	Path    string `json:"-"`
+}
+
+// BuiltinSchemes lists the color schemes shipped with heimdall-cli
+var BuiltinSchemes = map[string]ColorScheme{
+	"default": {
+		Description: "High-contrast neutral palette of the minimal profile",
+		Variant:     "dark",
+		Accent:      "#0078d4",
+		Colors: ColorConfig{
+			Background: "#000000", Foreground: "#ffffff",
+			Primary: "#0078d4", Secondary: "#00bcf2",
+			Success: "#107c10", Warning: "#ffb900", Error: "#d13438", Info: "#0078d4",
+			Surface: "#1f1f1f", Border: "#3f3f3f",
+		},
+	},
+	"catppuccin-mocha": {
+		Description: "Catppuccin Mocha",
+		Variant:     "dark",
+		Accent:      "#89b4fa",
+		Colors: ColorConfig{
+			Background: "#1e1e2e", Foreground: "#cdd6f4",
+			Primary: "#89b4fa", Secondary: "#f5c2e7",
+			Success: "#a6e3a1", Warning: "#f9e2af", Error: "#f38ba8", Info: "#89dceb",
+			Surface: "#313244", Border: "#45475a",
+		},
+	},
+	"catppuccin-macchiato": {
+		Description: "Catppuccin Macchiato",
+		Variant:     "dark",
+		Accent:      "#8aadf4",
+		Colors: ColorConfig{
+			Background: "#24273a", Foreground: "#cad3f5",
+			Primary: "#8aadf4", Secondary: "#f5bde6",
+			Success: "#a6da95", Warning: "#eed49f", Error: "#ed8796", Info: "#91d7e3",
+			Surface: "#363a4f", Border: "#494d64",
+		},
+	},
+	"catppuccin-frappe": {
+		Description: "Catppuccin Frappé",
+		Variant:     "dark",
+		Accent:      "#8caaee",
+		Colors: ColorConfig{
+			Background: "#303446", Foreground: "#c6d0f5",
+			Primary: "#8caaee", Secondary: "#f4b8e4",
+			Success: "#a6d189", Warning: "#e5c890", Error: "#e78284", Info: "#99d1db",
+			Surface: "#414559", Border: "#51576d",
+		},
+	},
+	"catppuccin-latte": {
+		Description: "Catppuccin Latte",
+		Variant:     "light",
+		Accent:      "#1e66f5",
+		Colors: ColorConfig{
+			Background: "#eff1f5", Foreground: "#4c4f69",
+			Primary: "#1e66f5", Secondary: "#ea76cb",
+			Success: "#40a02b", Warning: "#df8e1d", Error: "#d20f39", Info: "#04a5e5",
+			Surface: "#ccd0da", Border: "#bcc0cc",
+		},
+	},
+	"nord": {
+		Description: "Nord",
+		Variant:     "dark",
+		Accent:      "#88c0d0",
+		Colors: ColorConfig{
+			Background: "#2e3440", Foreground: "#d8dee9",
+			Primary: "#88c0d0", Secondary: "#b48ead",
+			Success: "#a3be8c", Warning: "#ebcb8b", Error: "#bf616a", Info: "#81a1c1",
+			Surface: "#3b4252", Border: "#4c566a",
+		},
+	},
+	"dracula": {
+		Description: "Dracula",
+		Variant:     "dark",
+		Accent:      "#bd93f9",
+		Colors: ColorConfig{
+			Background: "#282a36", Foreground: "#f8f8f2",
+			Primary: "#bd93f9", Secondary: "#ff79c6",
+			Success: "#50fa7b", Warning: "#f1fa8c", Error: "#ff5555", Info: "#8be9fd",
+			Surface: "#44475a", Border: "#6272a4",
+		},
+	},
+	"gruvbox": {
+		Description: "Gruvbox dark",
+		Variant:     "dark",
+		Accent:      "#83a598",
+		Colors: ColorConfig{
+			Background: "#282828", Foreground: "#ebdbb2",
+			Primary: "#83a598", Secondary: "#d3869b",
+			Success: "#b8bb26", Warning: "#fabd2f", Error: "#fb4934", Info: "#8ec07c",
+			Surface: "#3c3836", Border: "#504945",
+		},
+	},
+	"gruvbox-light": {
+		Description: "Gruvbox light",
+		Variant:     "light",
+		Accent:      "#076678",
+		Colors: ColorConfig{
+			Background: "#fbf1c7", Foreground: "#3c3836",
+			Primary: "#076678", Secondary: "#8f3f71",
+			Success: "#79740e", Warning: "#b57614", Error: "#9d0006", Info: "#427b58",
+			Surface: "#ebdbb2", Border: "#d5c4a1",
+		},
+	},
+	"tokyonight": {
+		Description: "Tokyo Night",
+		Variant:     "dark",
+		Accent:      "#7aa2f7",
+		Colors: ColorConfig{
+			Background: "#1a1b26", Foreground: "#c0caf5",
+			Primary: "#7aa2f7", Secondary: "#bb9af7",
+			Success: "#9ece6a", Warning: "#e0af68", Error: "#f7768e", Info: "#7dcfff",
+			Surface: "#24283b", Border: "#414868",
+		},
+	},
+	"tokyonight-storm": {
+		Description: "Tokyo Night Storm",
+		Variant:     "dark",
+		Accent:      "#7aa2f7",
+		Colors: ColorConfig{
+			Background: "#24283b", Foreground: "#c0caf5",
+			Primary: "#7aa2f7", Secondary: "#bb9af7",
+			Success: "#9ece6a", Warning: "#e0af68", Error: "#f7768e", Info: "#7dcfff",
+			Surface: "#292e42", Border: "#414868",
+		},
+	},
+	"tokyonight-day": {
+		Description: "Tokyo Night Day",
+		Variant:     "light",
+		Accent:      "#2e7de9",
+		Colors: ColorConfig{
+			Background: "#e1e2e7", Foreground: "#3760bf",
+			Primary: "#2e7de9", Secondary: "#9854f1",
+			Success: "#587539", Warning: "#8c6c3e", Error: "#f52a65", Info: "#007197",
+			Surface: "#d0d5e3", Border: "#a8aecb",
+		},
+	},
+}
+
+// SchemeVariants lists the accepted scheme variants
+var SchemeVariants = []string{"dark", "light"}
+
+// schemePaths are the config paths a scheme sets
+var schemePaths = []string{
+	"appearance.colorScheme",
+	"appearance.colors",
+	"appearance.accentColor",
+	"bar.background",
+	"bar.foreground",
+}
+
+// GetSchemesDir returns the user scheme directory.
+// HEIMDALL_SCHEMES_DIR overrides it.
+func GetSchemesDir() string {
+	if envPath := os.Getenv("HEIMDALL_SCHEMES_DIR"); envPath != "" {
+		return envPath
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, _ := os.UserHomeDir()
+		configHome = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(configHome, SchemesDirPath)
+}
+
+// SchemeRegistry finds built-in schemes and user scheme files
+// (<dir>/<name>.json). A user scheme named like a built-in replaces it.
+type SchemeRegistry struct {
+	dir string
+}
+
+// NewSchemeRegistry creates a registry reading user schemes from dir
+func NewSchemeRegistry(dir string) *SchemeRegistry {
+	return &SchemeRegistry{dir: dir}
+}
+
+// Dir returns the user scheme directory
+func (r *SchemeRegistry) Dir() string {
+	return r.dir
+}
+
+// List returns every scheme sorted by name
+func (r *SchemeRegistry) List() ([]ColorScheme, error) {
+	byName := make(map[string]ColorScheme)
+	for name := range BuiltinSchemes {
+		scheme, _ := builtinScheme(name)
+		byName[name] = scheme
+	}
+
+	files, err := filepath.Glob(filepath.Join(r.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schemes: %w", err)
+	}
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		scheme, err := r.load(name)
+		if err != nil {
+			return nil, err
+		}
+		byName[name] = *scheme
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	schemes := make([]ColorScheme, 0, len(names))
+	for _, name := range names {
+		schemes = append(schemes, byName[name])
+	}
+	return schemes, nil
+}
+
+// Names returns every scheme name sorted
+func (r *SchemeRegistry) Names() []string {
+	schemes, err := r.List()
+	if err != nil {
+		// Fall back to the built-in names when the user directory is unreadable
+		names := make([]string, 0, len(BuiltinSchemes))
+		for name := range BuiltinSchemes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	names := make([]string, 0, len(schemes))
+	for _, scheme := range schemes {
+		names = append(names, scheme.Name)
+	}
+	return names
+}
+
+// Exists reports whether a scheme is built-in or has a user file
+func (r *SchemeRegistry) Exists(name string) bool {
+	if _, ok := BuiltinSchemes[name]; ok {
+		return true
+	}
+	if !profileNamePattern.MatchString(name) {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(r.dir, name+".json"))
+	return err == nil
+}
+
+// Get returns a scheme by name, preferring user files
+func (r *SchemeRegistry) Get(name string) (*ColorScheme, error) {
+	if !profileNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid scheme name: %q", name)
+	}
+
+	scheme, err := r.load(name)
+	if err == nil {
+		return scheme, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if builtin, ok := builtinScheme(name); ok {
+		return &builtin, nil
+	}
+	return nil, fmt.Errorf("unknown color scheme: %s", name)
+}
+
+// Save writes a user scheme file
+func (r *SchemeRegistry) Save(scheme *ColorScheme) (string, error) {
+	if !profileNamePattern.MatchString(scheme.Name) {
+		return "", fmt.Errorf("invalid scheme name: %q (use letters, digits, - and _)", scheme.Name)
+	}
+	if err := ValidateScheme(scheme); err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(scheme, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal scheme: %w", err)
+	}
+
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create scheme directory: %w", err)
+	}
+	path := filepath.Join(r.dir, scheme.Name+".json")
+	if err := writeFileAtomic(path, append(data, '\n')); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// load reads a user scheme file; a missing file returns an os.IsNotExist error
+func (r *SchemeRegistry) load(name string) (*ColorScheme, error) {
+	path := filepath.Join(r.dir, name+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read scheme %s: %w", name, err)
+	}
+
+	scheme := &ColorScheme{}
+	if err := json.Unmarshal(data, scheme); err != nil {
+		return nil, fmt.Errorf("failed to parse scheme %s: %w", name, err)
+	}
+	scheme.Name = name
+	scheme.Path = path
+	if err := ValidateScheme(scheme); err != nil {
+		return nil, fmt.Errorf("scheme %s: %w", name, err)
+	}
+	return scheme, nil
+}
+
+// builtinScheme returns a built-in scheme with its name filled in
+func builtinScheme(name string) (ColorScheme, bool) {
+	scheme, ok := BuiltinSchemes[name]
+	if !ok {
+		return ColorScheme{}, false
+	}
+	scheme.Name = name
+	scheme.Builtin = true
+	return scheme, true
+}
+
+// ValidateScheme checks that every role is a literal color and the variant is known
+func ValidateScheme(scheme *ColorScheme) error {
+	if scheme.Variant != "" && !contains(SchemeVariants, scheme.Variant) {
+		return fmt.Errorf("invalid variant: %s (use dark or light)", scheme.Variant)
+	}
+
+	colors := map[string]string{"accent": scheme.Accent}
+	for role, value := range scheme.Colors.Roles() {
+		colors["colors."+role] = value
+	}
+	for role, value := range colors {
+		if _, err := ParseColor(value); err != nil {
+			return fmt.Errorf("%s: %w", role, err)
+		}
+	}
+	return nil
+}
+
+// Roles returns the palette keyed by JSON role name
+func (c ColorConfig) Roles() map[string]string {
+	return map[string]string{
+		"background": c.Background,
+		"foreground": c.Foreground,
+		"primary":    c.Primary,
+		"secondary":  c.Secondary,
+		"success":    c.Success,
+		"warning":    c.Warning,
+		"error":      c.Error,
+		"info":       c.Info,
+		"surface":    c.Surface,
+		"border":     c.Border,
+	}
+}
+
+// ApplyScheme sets the scheme name, palette, accent color and bar colors.
+// Paths covered by a user lock keep their value and are returned in locked.
+func ApplyScheme(config *ShellConfig, scheme *ColorScheme) (changes []PathChange, locked []string, err error) {
+	return applyValues(config, SchemeValues(scheme), schemePaths)
+}
+
+// applyValues writes path/value pairs into the config, skipping user-locked
+// paths, and returns the changes under the diff patterns
+func applyValues(config *ShellConfig, values map[string]interface{}, patterns []string) (changes []PathChange, locked []string, err error) {
+	before, err := CloneConfig(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	injector := NewPropertyInjector()
+
+	configMap, err := structToMap(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	for path, value := range values {
+		if injector.IsUserLocked(config, path) {
+			current, _ := LookupPath(configMap, path)
+			if !reflect.DeepEqual(current, value) {
+				locked = append(locked, path)
+			}
+			continue
+		}
+		SetMapPath(configMap, path, value)
+	}
+	if err := mapToStruct(configMap, config); err != nil {
+		return nil, nil, err
+	}
+	sort.Strings(locked)
+
+	paths, err := MatchPaths(patterns)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes, err = DiffConfigs(before, config, paths)
+	if err != nil {
+		return nil, nil, err
+	}
+	return changes, locked, nil
+}
+
+// SchemeValues returns the path/value pairs a scheme sets, for writing to
+// overlay layers
+func SchemeValues(scheme *ColorScheme) map[string]interface{} {
+	values := map[string]interface{}{
+		"appearance.colorScheme": scheme.Name,
+		"appearance.accentColor": scheme.Accent,
+		"bar.background":         scheme.Colors.Background,
+		"bar.foreground":         scheme.Colors.Foreground,
+	}
+	for role, value := range scheme.Colors.Roles() {
+		values["appearance.colors."+role] = value
+	}
+	return values
+}
+
+// useBuiltinScheme applies a built-in scheme while building profile defaults
+func useBuiltinScheme(config *ShellConfig, name string) {
+	scheme, _ := builtinScheme(name)
+	config.Appearance.ColorScheme = name
+	config.Appearance.AccentColor = scheme.Accent
+	config.Appearance.Colors = scheme.Colors
+	config.Bar.Background = scheme.Colors.Background
+	config.Bar.Foreground = scheme.Colors.Foreground
+}
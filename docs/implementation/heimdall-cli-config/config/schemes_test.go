@@ -0,0 +1,150 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// testScheme is a valid user scheme
+func testScheme(name string) *ColorScheme {
+	scheme, _ := builtinScheme("nord")
+	scheme.Name = name
+	scheme.Description = "test"
+	scheme.Accent = "#123456"
+	scheme.Builtin = false
+	return &scheme
+}
+
+func TestSchemeRegistryGet(t *testing.T) {
+	registry := NewSchemeRegistry(t.TempDir())
+	if _, err := registry.Save(testScheme("mine")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := registry.Save(testScheme("dracula")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(registry.Dir(), "broken.json"), []byte(`{"accent": "blue"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		builtin bool
+		accent  string
+		err     string
+	}{
+		{"nord", true, BuiltinSchemes["nord"].Accent, ""},
+		{"mine", false, "#123456", ""},
+		{"dracula", false, "#123456", ""}, // a user file replaces the built-in
+		{"missing", false, "", "unknown color scheme: missing"},
+		{"../nord", false, "", "invalid scheme name"},
+		{"broken", false, "", "scheme broken"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme, err := registry.Get(tt.name)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("Get(%q) error = %v, want %q", tt.name, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Get(%q): %v", tt.name, err)
+			}
+			if scheme.Name != tt.name || scheme.Builtin != tt.builtin || scheme.Accent != tt.accent {
+				t.Errorf("Get(%q) = %s builtin=%v accent=%s, want builtin=%v accent=%s",
+					tt.name, scheme.Name, scheme.Builtin, scheme.Accent, tt.builtin, tt.accent)
+			}
+			if !registry.Exists(tt.name) {
+				t.Errorf("Exists(%q) = false", tt.name)
+			}
+		})
+	}
+}
+
+func TestSchemeRegistryExists(t *testing.T) {
+	registry := NewSchemeRegistry(t.TempDir())
+	for name, want := range map[string]bool{"nord": true, "missing": false, "../nord": false} {
+		if got := registry.Exists(name); got != want {
+			t.Errorf("Exists(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestSchemeRegistryNames(t *testing.T) {
+	registry := NewSchemeRegistry(t.TempDir())
+	if _, err := registry.Save(testScheme("nord")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := registry.Save(testScheme("zz-mine")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	names := registry.Names()
+	if !sort.StringsAreSorted(names) || len(names) != len(BuiltinSchemes)+1 {
+		t.Errorf("Names() = %v, want the built-ins plus zz-mine, sorted", names)
+	}
+
+	bad := testScheme("bad")
+	bad.Colors.Primary = "blue-ish"
+	if _, err := registry.Save(bad); err == nil {
+		t.Error("saved a scheme with an invalid color")
+	}
+}
+
+func TestApplyScheme(t *testing.T) {
+	config := GetDefaultConfig()
+	scheme, err := NewSchemeRegistry(t.TempDir()).Get("dracula")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	changes, locked, err := ApplyScheme(config, scheme)
+	if err != nil {
+		t.Fatalf("ApplyScheme: %v", err)
+	}
+	if len(locked) != 0 {
+		t.Errorf("locked = %v, want none", locked)
+	}
+	if config.Appearance.ColorScheme != "dracula" || config.Appearance.Colors != scheme.Colors ||
+		config.Appearance.AccentColor != scheme.Accent {
+		t.Errorf("appearance = %s %s %+v, want dracula", config.Appearance.ColorScheme, config.Appearance.AccentColor, config.Appearance.Colors)
+	}
+	if config.Bar.Background != scheme.Colors.Background || config.Bar.Foreground != scheme.Colors.Foreground {
+		t.Errorf("bar colors = %s/%s, want the scheme's", config.Bar.Background, config.Bar.Foreground)
+	}
+
+	changed := make(map[string]bool)
+	for _, change := range changes {
+		changed[change.Path] = true
+	}
+	for _, path := range []string{"appearance.colorScheme", "appearance.colors.background", "bar.background"} {
+		if !changed[path] {
+			t.Errorf("changes lack %s: %v", path, changes)
+		}
+	}
+
+	if changes, _, _ := ApplyScheme(config, scheme); len(changes) != 0 {
+		t.Errorf("applying the same scheme again changed %v", changes)
+	}
+}
+
+// The productivity and development profiles take their whole palette from
+// the scheme they name, not just the name
+func TestBuiltinProfileSchemes(t *testing.T) {
+	for profile, name := range map[string]string{"productivity": "nord", "development": "dracula"} {
+		config := GetProfileConfig(profile)
+		scheme, _ := builtinScheme(name)
+		if config.Appearance.ColorScheme != name || config.Appearance.Colors != scheme.Colors || config.Appearance.AccentColor != scheme.Accent {
+			t.Errorf("%s profile colors are not %s: %+v", profile, name, config.Appearance.Colors)
+		}
+		if config.Bar.Background != scheme.Colors.Background {
+			t.Errorf("%s bar.background = %s, want %s", profile, config.Bar.Background, scheme.Colors.Background)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// WallpaperStateFile is the slideshow state's name in the state directory
+const WallpaperStateFile = "wallpaper.json"
+
+// AllMonitors keys the state of a wallpaper shown on every output, used
+// when no outputs are configured, and the monitors entry with defaults for
+// every output
+const AllMonitors = "*"
+
+// WallpaperState records what each monitor shows and where its slideshow is
+type WallpaperState struct {
+	Monitors map[string]*MonitorWallpaper `json:"monitors"`
+}
+
+// MonitorWallpaper is one monitor's playback position. Order is the
+// rotation sequence, shuffled when wallpaper.random is set, and Position
+// indexes it.
+type MonitorWallpaper struct {
+	Current  string    `json:"current"`
+	Order    []string  `json:"order,omitempty"`
+	Shuffled bool      `json:"shuffled,omitempty"`
+	Position int       `json:"position"`
+	Changed  time.Time `json:"changed"`
+}
+
+// Slideshow rotates a set of images per monitor
+type Slideshow struct {
+	Images []string
+	Random bool
+	// Shuffle reorders images in place; nil uses math/rand
+	Shuffle func(images []string)
+}
+
+// GetWallpaperStatePath returns the slideshow state file location
+func GetWallpaperStatePath() string {
+	return filepath.Join(GetStateDir(), WallpaperStateFile)
+}
+
+// LoadWallpaperState reads the state file; a missing file is an empty state
+func LoadWallpaperState(path string) (*WallpaperState, error) {
+	state := &WallpaperState{Monitors: make(map[string]*MonitorWallpaper)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallpaper state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse wallpaper state: %w", err)
+	}
+	if state.Monitors == nil {
+		state.Monitors = make(map[string]*MonitorWallpaper)
+	}
+	return state, nil
+}
+
+// Save writes the state to path
+func (s *WallpaperState) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal wallpaper state: %w", err)
+	}
+	return WriteRenderedFile(path, append(data, '\n'))
+}
+
+// Monitor returns a monitor's state, creating it when missing
+func (s *WallpaperState) Monitor(name string) *MonitorWallpaper {
+	monitor, ok := s.Monitors[name]
+	if !ok {
+		monitor = &MonitorWallpaper{Position: -1}
+		s.Monitors[name] = monitor
+	}
+	return monitor
+}
+
+// Current returns the image a monitor shows, or "" when it has no state
+func (s *WallpaperState) Current(name string) string {
+	if monitor, ok := s.Monitors[name]; ok {
+		return monitor.Current
+	}
+	return ""
+}
+
+// SlideshowMonitors returns the outputs the slideshow drives: the
+// configured outputs without a wallpaper of their own, or AllMonitors when
+// no outputs are configured. Unmigrated configurations list them in
+// wallpaper.monitors.
+func SlideshowMonitors(config *ShellConfig) []string {
+	names := config.Monitors.MonitorNames()
+	if len(names) == 0 {
+		names = config.Wallpaper.Monitors
+	}
+	if len(names) == 0 {
+		return []string{AllMonitors}
+	}
+
+	monitors := make([]string, 0, len(names))
+	for _, name := range names {
+		if config.Monitor(name).Wallpaper == "" {
+			monitors = append(monitors, name)
+		}
+	}
+	return monitors
+}
+
+// PinnedWallpapers maps the outputs with a monitors.<output>.wallpaper of
+// their own to that image
+func PinnedWallpapers(config *ShellConfig) map[string]string {
+	pinned := make(map[string]string)
+	for _, name := range config.Monitors.MonitorNames() {
+		if wallpaper := config.Monitors[name].Wallpaper; wallpaper != "" {
+			pinned[name] = ExpandHome(wallpaper)
+		}
+	}
+	return pinned
+}
+
+// Start returns the image a monitor should show when the slideshow starts:
+// its current image while that still exists, otherwise the image at offset,
+// so monitors started together do not all show the same one
+func (s *Slideshow) Start(monitor *MonitorWallpaper, offset int, now time.Time) (string, error) {
+	if len(s.Images) == 0 {
+		return "", fmt.Errorf("no images in the wallpaper directory")
+	}
+	s.syncOrder(monitor)
+	if monitor.Current != "" && monitor.Position >= 0 {
+		return monitor.Current, nil
+	}
+	monitor.Position = offset - 1
+	return s.Step(monitor, 1, now)
+}
+
+// Step moves a monitor delta images through its order and returns the new
+// image. Random slideshows reshuffle after each full pass.
+func (s *Slideshow) Step(monitor *MonitorWallpaper, delta int, now time.Time) (string, error) {
+	if len(s.Images) == 0 {
+		return "", fmt.Errorf("no images in the wallpaper directory")
+	}
+	s.syncOrder(monitor)
+
+	if monitor.Position < 0 && delta < 0 {
+		// Nothing shown from the rotation yet: going back starts at the end
+		monitor.Position = 0
+	}
+	next := monitor.Position + delta
+	if s.Random && next >= len(monitor.Order) && len(monitor.Order) > 1 {
+		previous := monitor.Current
+		monitor.Order = s.shuffled()
+		// Do not show the same image twice in a row across passes
+		if monitor.Order[0] == previous {
+			monitor.Order[0], monitor.Order[1] = monitor.Order[1], monitor.Order[0]
+		}
+		next = 0
+	}
+	next %= len(monitor.Order)
+	if next < 0 {
+		next += len(monitor.Order)
+	}
+
+	monitor.Position = next
+	monitor.Current = monitor.Order[next]
+	monitor.Changed = now
+	return monitor.Current, nil
+}
+
+// Show records that a monitor displays path, moving its position to the
+// image when it is part of the rotation
+func (s *Slideshow) Show(monitor *MonitorWallpaper, path string, now time.Time) {
+	s.syncOrder(monitor)
+	for i, image := range monitor.Order {
+		if image == path {
+			monitor.Position = i
+			break
+		}
+	}
+	monitor.Current = path
+	monitor.Changed = now
+}
+
+// syncOrder rebuilds a monitor's order when the image set or the random
+// setting has changed, keeping its position on the current image
+func (s *Slideshow) syncOrder(monitor *MonitorWallpaper) {
+	if monitor.Shuffled == s.Random && sameImages(monitor.Order, s.Images) {
+		return
+	}
+
+	order := append([]string(nil), s.Images...)
+	if s.Random {
+		order = s.shuffled()
+	}
+	monitor.Order = order
+	monitor.Shuffled = s.Random
+	monitor.Position = -1
+	for i, image := range order {
+		if image == monitor.Current {
+			monitor.Position = i
+			break
+		}
+	}
+}
+
+// shuffled returns the images in random order
+func (s *Slideshow) shuffled() []string {
+	order := append([]string(nil), s.Images...)
+	if s.Shuffle != nil {
+		s.Shuffle(order)
+		return order
+	}
+	rand.Shuffle(len(order), func(i, j int) {
+		order[i], order[j] = order[j], order[i]
+	})
+	return order
+}
+
+// sameImages reports whether a and b hold the same paths in any order
+func sameImages(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	x := append([]string(nil), a...)
+	y := append([]string(nil), b...)
+	sort.Strings(x)
+	sort.Strings(y)
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestSlideshowRotation drives two monitors through a FakeSetter the way
+// the daemon does: started together on different images, then stepped
+func TestSlideshowRotation(t *testing.T) {
+	slideshow := &Slideshow{Images: []string{"/w/a.png", "/w/b.png", "/w/c.png"}}
+	state := &WallpaperState{Monitors: make(map[string]*MonitorWallpaper)}
+	setter := &FakeSetter{}
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	monitors := []string{"DP-1", "HDMI-A-1"}
+	for i, m := range monitors {
+		path, err := slideshow.Start(state.Monitor(m), i, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		setter.Set(m, path, "cover")
+	}
+	for _, m := range monitors {
+		path, err := slideshow.Step(state.Monitor(m), 1, now.Add(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		setter.Set(m, path, "cover")
+	}
+	path, _ := slideshow.Step(state.Monitor("DP-1"), -1, now.Add(2*time.Minute))
+	setter.Set("DP-1", path, "cover")
+
+	want := []WallpaperCall{
+		{"DP-1", "/w/a.png", "cover"},
+		{"HDMI-A-1", "/w/b.png", "cover"},
+		{"DP-1", "/w/b.png", "cover"},
+		{"HDMI-A-1", "/w/c.png", "cover"},
+		{"DP-1", "/w/a.png", "cover"},
+	}
+	if !reflect.DeepEqual(setter.Calls, want) {
+		t.Errorf("calls = %v, want %v", setter.Calls, want)
+	}
+
+	// A restart resumes each monitor on its current image
+	restarted, err := slideshow.Start(state.Monitor("HDMI-A-1"), 1, now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restarted != "/w/c.png" {
+		t.Errorf("restart shows %s, want /w/c.png", restarted)
+	}
+}
+
+func TestSlideshowReshuffle(t *testing.T) {
+	// Reversing is a deterministic "shuffle" that would repeat the last image
+	slideshow := &Slideshow{
+		Images: []string{"/w/a.png", "/w/b.png", "/w/c.png"},
+		Random: true,
+		Shuffle: func(images []string) {
+			for i, j := 0, len(images)-1; i < j; i, j = i+1, j-1 {
+				images[i], images[j] = images[j], images[i]
+			}
+		},
+	}
+	monitor := &MonitorWallpaper{Position: -1}
+	now := time.Now()
+
+	var shown []string
+	for i := 0; i < 6; i++ {
+		path, err := slideshow.Step(monitor, 1, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(shown) > 0 && shown[len(shown)-1] == path {
+			t.Fatalf("%s shown twice in a row: %v", path, append(shown, path))
+		}
+		shown = append(shown, path)
+	}
+}
+
+func TestSlideshowMonitors(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Monitors = MonitorsConfig{
+		"DP-1":     {},
+		"HDMI-A-1": {Wallpaper: "~/pinned.png"},
+	}
+	if got := SlideshowMonitors(config); !reflect.DeepEqual(got, []string{"DP-1"}) {
+		t.Errorf("SlideshowMonitors = %v, want [DP-1]", got)
+	}
+	if pinned := PinnedWallpapers(config); pinned["HDMI-A-1"] != ExpandHome("~/pinned.png") || len(pinned) != 1 {
+		t.Errorf("PinnedWallpapers = %v", pinned)
+	}
+
+	config.Monitors = MonitorsConfig{}
+	config.Wallpaper.Monitors = nil
+	if got := SlideshowMonitors(config); !reflect.DeepEqual(got, []string{AllMonitors}) {
+		t.Errorf("SlideshowMonitors without outputs = %v, want [*]", got)
+	}
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"math"
+	"time"
+)
+
+// sunZenith is the solar zenith angle at sunrise and sunset in degrees: the
+// 90° horizon plus atmospheric refraction and the sun's apparent radius
+const sunZenith = 90.833
+
+// SunTimes holds a day's sunrise and sunset
+type SunTimes struct {
+	Sunrise time.Time
+	Sunset  time.Time
+	// PolarDay is set when the sun stays up all day and PolarNight when it
+	// never rises; Sunrise and Sunset are zero then
+	PolarDay   bool
+	PolarNight bool
+}
+
+// CalculateSunTimes returns sunrise and sunset at a location (degrees, north
+// and east positive) on the local day with date's calendar date, in date's
+// time zone. That day can start or end on another date in date's zone. It uses
+// NOAA's general solar position equations, which are good to a minute or
+// two outside the polar regions and need no network or ephemeris data.
+func CalculateSunTimes(date time.Time, latitude, longitude float64) SunTimes {
+	y, m, d := date.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	day := midnight.YearDay()
+
+	// Start from solar noon, then refine each event at its own time of day
+	noon := 720 - 4*longitude
+	ha, status := hourAngle(day, noon, latitude)
+	if status > 0 {
+		return SunTimes{PolarDay: true}
+	}
+	if status < 0 {
+		return SunTimes{PolarNight: true}
+	}
+
+	at := func(minutes float64) time.Time {
+		return midnight.Add(time.Duration(minutes * float64(time.Minute))).In(date.Location())
+	}
+	event := func(sign float64) time.Time {
+		estimate := noon - sign*4*ha - equationOfTime(day, noon)
+		if refined, status := hourAngle(day, estimate, latitude); status == 0 {
+			return at(noon - sign*4*refined - equationOfTime(day, estimate))
+		}
+		return at(estimate)
+	}
+
+	return SunTimes{
+		Sunrise: event(1),
+		Sunset:  event(-1),
+	}
+}
+
+// hourAngle returns the sunrise hour angle in degrees for the given minute
+// of the UTC day. status is 1 when the sun does not set and -1 when it does
+// not rise.
+func hourAngle(day int, minutes, latitude float64) (float64, int) {
+	decl := solarDeclination(day, minutes)
+	lat := latitude * math.Pi / 180
+
+	cos := math.Cos(sunZenith*math.Pi/180)/(math.Cos(lat)*math.Cos(decl)) - math.Tan(lat)*math.Tan(decl)
+	if cos < -1 {
+		return 0, 1
+	}
+	if cos > 1 {
+		return 0, -1
+	}
+	return math.Acos(cos) * 180 / math.Pi, 0
+}
+
+// fractionalYear returns the year angle γ in radians for a minute of the
+// UTC day
+func fractionalYear(day int, minutes float64) float64 {
+	return 2 * math.Pi / 365 * (float64(day-1) + (minutes/60-12)/24)
+}
+
+// equationOfTime returns how far solar time runs ahead of mean time, in minutes
+func equationOfTime(day int, minutes float64) float64 {
+	g := fractionalYear(day, minutes)
+	return 229.18 * (0.000075 + 0.001868*math.Cos(g) - 0.032077*math.Sin(g) -
+		0.014615*math.Cos(2*g) - 0.040849*math.Sin(2*g))
+}
+
+// solarDeclination returns the sun's declination in radians
+func solarDeclination(day int, minutes float64) float64 {
+	g := fractionalYear(day, minutes)
+	return 0.006918 - 0.399912*math.Cos(g) + 0.070257*math.Sin(g) -
+		0.006758*math.Cos(2*g) + 0.000907*math.Sin(2*g) -
+		0.002697*math.Cos(3*g) + 0.00148*math.Sin(3*g)
+}
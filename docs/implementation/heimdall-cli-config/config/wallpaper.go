@@ -0,0 +1,290 @@
+package config
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	// Registers the WebP decoder; the library indexes .webp images
+	_ "golang.org/x/image/webp"
+)
+
+// WallpaperIndexFile is the wallpaper index's name in the cache directory
+const WallpaperIndexFile = "wallpaper-index.json"
+
+// wallpaperImageExts are the image types the wallpaper library indexes
+var wallpaperImageExts = []string{".png", ".jpg", ".jpeg", ".gif", ".webp"}
+
+// commonAspects are named aspect ratios, matched within aspectTolerance
+var commonAspects = []struct {
+	Name  string
+	Ratio float64
+}{
+	{"1:1", 1},
+	{"5:4", 5.0 / 4},
+	{"4:3", 4.0 / 3},
+	{"3:2", 3.0 / 2},
+	{"16:10", 16.0 / 10},
+	{"16:9", 16.0 / 9},
+	{"21:9", 64.0 / 27},
+	{"32:9", 32.0 / 9},
+}
+
+const aspectTolerance = 0.02
+
+// WallpaperInfo describes one indexed image
+type WallpaperInfo struct {
+	Path    string    `json:"path"`
+	Format  string    `json:"format"`
+	Width   int       `json:"width"`
+	Height  int       `json:"height"`
+	Aspect  string    `json:"aspect"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+}
+
+// Orientation returns landscape, portrait or square
+func (w WallpaperInfo) Orientation() string {
+	switch {
+	case w.Width > w.Height:
+		return "landscape"
+	case w.Width < w.Height:
+		return "portrait"
+	}
+	return "square"
+}
+
+// WallpaperIndex is the scanned contents of the wallpaper directory
+type WallpaperIndex struct {
+	Directory string          `json:"directory"`
+	Scanned   time.Time       `json:"scanned"`
+	Images    []WallpaperInfo `json:"images"`
+	// Skipped maps files with an image extension that could not be read to the reason
+	Skipped map[string]string `json:"skipped,omitempty"`
+}
+
+// GetWallpaperIndexPath returns the wallpaper index location
+func GetWallpaperIndexPath() string {
+	return filepath.Join(GetCacheDir(), WallpaperIndexFile)
+}
+
+// IndexWallpapers scans dir (not its subdirectories) and reads each image's
+// format and dimensions from its header, without decoding the pixels
+func IndexWallpapers(dir string) (*WallpaperIndex, error) {
+	dir = ExpandHome(dir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallpaper directory: %w", err)
+	}
+
+	index := &WallpaperIndex{
+		Directory: dir,
+		Scanned:   time.Now(),
+		Images:    make([]WallpaperInfo, 0),
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !contains(wallpaperImageExts, strings.ToLower(filepath.Ext(entry.Name()))) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		info, err := ReadWallpaperInfo(path)
+		if err != nil {
+			if index.Skipped == nil {
+				index.Skipped = make(map[string]string)
+			}
+			index.Skipped[path] = err.Error()
+			continue
+		}
+		index.Images = append(index.Images, *info)
+	}
+	sort.Slice(index.Images, func(i, j int) bool {
+		return index.Images[i].Path < index.Images[j].Path
+	})
+	return index, nil
+}
+
+// ReadWallpaperInfo reads an image's format and dimensions from its header
+func ReadWallpaperInfo(path string) (*WallpaperInfo, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	format, width, height, err := readImageHeader(file)
+	if err != nil {
+		return nil, err
+	}
+	return &WallpaperInfo{
+		Path:    path,
+		Format:  format,
+		Width:   width,
+		Height:  height,
+		Aspect:  AspectRatio(width, height),
+		Size:    stat.Size(),
+		ModTime: stat.ModTime().UTC().Truncate(time.Second),
+	}, nil
+}
+
+// readImageHeader returns the format and size of a PNG, JPEG, GIF or WebP
+// image. The standard library has no WebP support, so its header is parsed
+// here; the others use image.DecodeConfig, which stops after the header.
+func readImageHeader(r io.ReadSeeker) (format string, width, height int, err error) {
+	head := make([]byte, 30)
+	n, _ := io.ReadFull(r, head)
+	head = head[:n]
+	if len(head) >= 12 && bytes.Equal(head[0:4], []byte("RIFF")) && bytes.Equal(head[8:12], []byte("WEBP")) {
+		width, height, err = webpSize(head)
+		return "webp", width, height, err
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", 0, 0, err
+	}
+	config, format, err := image.DecodeConfig(r)
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("unreadable image header: %w", err)
+	}
+	return format, config.Width, config.Height, nil
+}
+
+// webpSize reads the canvas size from the first chunk of a WebP file
+func webpSize(head []byte) (int, int, error) {
+	if len(head) < 30 {
+		return 0, 0, fmt.Errorf("truncated WebP header")
+	}
+	switch string(head[12:16]) {
+	case "VP8X":
+		// Extended format: 24-bit canvas width and height minus one
+		width := int(head[24]) | int(head[25])<<8 | int(head[26])<<16
+		height := int(head[27]) | int(head[28])<<8 | int(head[29])<<16
+		return width + 1, height + 1, nil
+	case "VP8L":
+		// Lossless: signature byte, then 14-bit width and height minus one
+		if head[20] != 0x2f {
+			return 0, 0, fmt.Errorf("invalid WebP lossless signature")
+		}
+		bits := binary.LittleEndian.Uint32(head[21:25])
+		return int(bits&0x3fff) + 1, int(bits>>14&0x3fff) + 1, nil
+	case "VP8 ":
+		// Lossy: frame tag, start code, then 14-bit width and height
+		if !bytes.Equal(head[23:26], []byte{0x9d, 0x01, 0x2a}) {
+			return 0, 0, fmt.Errorf("invalid WebP frame start code")
+		}
+		width := binary.LittleEndian.Uint16(head[26:28]) & 0x3fff
+		height := binary.LittleEndian.Uint16(head[28:30]) & 0x3fff
+		return int(width), int(height), nil
+	}
+	return 0, 0, fmt.Errorf("unknown WebP chunk %q", head[12:16])
+}
+
+// AspectRatio names width:height, using the common ratio within 2% when
+// there is one (1366×768 is 16:9) and the reduced fraction otherwise
+func AspectRatio(width, height int) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	ratio := float64(width) / float64(height)
+	portrait := ratio < 1
+	if portrait {
+		ratio = 1 / ratio
+	}
+	for _, common := range commonAspects {
+		if math.Abs(ratio-common.Ratio)/common.Ratio <= aspectTolerance {
+			if portrait {
+				parts := strings.SplitN(common.Name, ":", 2)
+				return parts[1] + ":" + parts[0]
+			}
+			return common.Name
+		}
+	}
+
+	a, b := width, height
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return fmt.Sprintf("%d:%d", width/a, height/a)
+}
+
+// LoadWallpaperIndex reads a saved index
+func LoadWallpaperIndex(path string) (*WallpaperIndex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var index WallpaperIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse wallpaper index: %w", err)
+	}
+	return &index, nil
+}
+
+// Save writes the index to path
+func (idx *WallpaperIndex) Save(path string) error {
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal wallpaper index: %w", err)
+	}
+	return WriteRenderedFile(path, append(data, '\n'))
+}
+
+// Stale reports whether dir is not the indexed directory or has changed
+// since the scan
+func (idx *WallpaperIndex) Stale(dir string) bool {
+	dir = ExpandHome(dir)
+	if idx.Directory != dir {
+		return true
+	}
+	info, err := os.Stat(dir)
+	return err != nil || info.ModTime().After(idx.Scanned)
+}
+
+// Find returns the indexed entry for path
+func (idx *WallpaperIndex) Find(path string) (*WallpaperInfo, bool) {
+	for i := range idx.Images {
+		if idx.Images[i].Path == path {
+			return &idx.Images[i], true
+		}
+	}
+	return nil, false
+}
+
+// Paths returns the indexed image paths in order
+func (idx *WallpaperIndex) Paths() []string {
+	paths := make([]string, len(idx.Images))
+	for i, image := range idx.Images {
+		paths[i] = image.Path
+	}
+	return paths
+}
+
+// WallpaperLibrary returns the index of dir, rescanning and saving it to
+// path when the saved one is missing or stale
+func WallpaperLibrary(dir, path string) (*WallpaperIndex, error) {
+	if index, err := LoadWallpaperIndex(path); err == nil && !index.Stale(dir) {
+		return index, nil
+	}
+	index, err := IndexWallpapers(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := index.Save(path); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// Wallpaper backends for wallpaper.backend
+const (
+	WallpaperBackendAuto      = "auto"
+	WallpaperBackendSwww      = "swww"
+	WallpaperBackendHyprpaper = "hyprpaper"
+)
+
+// WallpaperBackends lists the accepted backends, auto first
+var WallpaperBackends = []string{WallpaperBackendAuto, WallpaperBackendSwww, WallpaperBackendHyprpaper}
+
+// WallpaperSetter shows an image on an output. An output of AllMonitors
+// means every output.
+type WallpaperSetter interface {
+	Name() string
+	Set(output, path, fillMode string) error
+}
+
+// CommandRunner runs a program with arguments, without a shell
+type CommandRunner func(name string, args ...string) error
+
+// execCommand runs a program and includes its output in the error on failure
+func execCommand(name string, args ...string) error {
+	output, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return fmt.Errorf("%s: %w: %s", name, err, msg)
+		}
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// NewWallpaperSetter returns the setter for a backend name. auto picks swww
+// when it is installed and hyprpaper (through hyprctl) otherwise.
+func NewWallpaperSetter(backend string, probe SystemProbe) (WallpaperSetter, error) {
+	switch backend {
+	case WallpaperBackendSwww:
+		return &SwwwSetter{Run: execCommand}, nil
+	case WallpaperBackendHyprpaper:
+		return &HyprpaperSetter{Run: execCommand}, nil
+	case WallpaperBackendAuto, "":
+		if _, err := probe.LookPath("swww"); err == nil {
+			return &SwwwSetter{Run: execCommand}, nil
+		}
+		if _, err := probe.LookPath("hyprctl"); err == nil {
+			return &HyprpaperSetter{Run: execCommand}, nil
+		}
+		return nil, fmt.Errorf("no wallpaper backend found (install swww or hyprpaper)")
+	}
+	return nil, fmt.Errorf("unknown wallpaper backend: %s (use %s)", backend, strings.Join(WallpaperBackends, ", "))
+}
+
+// SwwwSetter sets wallpapers with 'swww img'; the swww daemon must be running
+type SwwwSetter struct {
+	Run CommandRunner
+}
+
+// swwwResize maps fill modes to swww's --resize values
+var swwwResize = map[string]string{
+	"cover":      "crop",
+	"contain":    "fit",
+	"scale-down": "fit",
+	"fill":       "stretch",
+	"none":       "no",
+}
+
+func (s *SwwwSetter) Name() string { return WallpaperBackendSwww }
+
+// Set runs swww img [--outputs <output>] --resize <mode> <path>
+func (s *SwwwSetter) Set(output, path, fillMode string) error {
+	args := []string{"img"}
+	if output != AllMonitors && output != "" {
+		args = append(args, "--outputs", output)
+	}
+	if resize, ok := swwwResize[fillMode]; ok {
+		args = append(args, "--resize", resize)
+	}
+	args = append(args, path)
+	return s.Run("swww", args...)
+}
+
+// HyprpaperSetter drives a running hyprpaper through hyprctl: the image is
+// preloaded, shown, and images no output uses any more are unloaded
+type HyprpaperSetter struct {
+	Run CommandRunner
+}
+
+func (h *HyprpaperSetter) Name() string { return WallpaperBackendHyprpaper }
+
+// Set shows path on output. hyprpaper covers the output by default and
+// fits the image with the contain: prefix; other fill modes fall back to cover.
+func (h *HyprpaperSetter) Set(output, path, fillMode string) error {
+	if output == AllMonitors {
+		output = ""
+	}
+	target := path
+	if fillMode == "contain" || fillMode == "scale-down" {
+		target = "contain:" + path
+	}
+
+	if err := h.Run("hyprctl", "hyprpaper", "preload", path); err != nil {
+		return err
+	}
+	if err := h.Run("hyprctl", "hyprpaper", "wallpaper", output+","+target); err != nil {
+		return err
+	}
+	return h.Run("hyprctl", "hyprpaper", "unload", "unused")
+}
+
+// WallpaperCall is one Set call recorded by FakeSetter
+type WallpaperCall struct {
+	Output   string
+	Path     string
+	FillMode string
+}
+
+// FakeSetter records Set calls instead of changing the wallpaper, for tests
+// and dry runs. With Out set each call is also printed.
+type FakeSetter struct {
+	Calls []WallpaperCall
+	Out   io.Writer
+	// Err is returned from every Set call when non-nil
+	Err error
+}
+
+func (f *FakeSetter) Name() string { return "fake" }
+
+// Set records the call
+func (f *FakeSetter) Set(output, path, fillMode string) error {
+	f.Calls = append(f.Calls, WallpaperCall{Output: output, Path: path, FillMode: fillMode})
+	if f.Out != nil {
+		if output == AllMonitors {
+			output = "all outputs"
+		}
+		fmt.Fprintf(f.Out, "Would set %s on %s (%s)\n", path, output, fillMode)
+	}
+	return f.Err
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// recordRunner returns a CommandRunner that records each command line
+func recordRunner(lines *[]string) CommandRunner {
+	return func(name string, args ...string) error {
+		*lines = append(*lines, strings.Join(append([]string{name}, args...), " "))
+		return nil
+	}
+}
+
+func TestWallpaperBackends(t *testing.T) {
+	tests := []struct {
+		name   string
+		setter func(run CommandRunner) WallpaperSetter
+		output string
+		fill   string
+		want   []string
+	}{
+		{
+			"swww on one output",
+			func(run CommandRunner) WallpaperSetter { return &SwwwSetter{Run: run} },
+			"DP-1", "contain",
+			[]string{"swww img --outputs DP-1 --resize fit /w/a.png"},
+		},
+		{
+			"swww everywhere",
+			func(run CommandRunner) WallpaperSetter { return &SwwwSetter{Run: run} },
+			AllMonitors, "cover",
+			[]string{"swww img --resize crop /w/a.png"},
+		},
+		{
+			"hyprpaper",
+			func(run CommandRunner) WallpaperSetter { return &HyprpaperSetter{Run: run} },
+			AllMonitors, "scale-down",
+			[]string{
+				"hyprctl hyprpaper preload /w/a.png",
+				"hyprctl hyprpaper wallpaper ,contain:/w/a.png",
+				"hyprctl hyprpaper unload unused",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lines []string
+			if err := tt.setter(recordRunner(&lines)).Set(tt.output, "/w/a.png", tt.fill); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(lines, tt.want) {
+				t.Errorf("ran %q, want %q", lines, tt.want)
+			}
+		})
+	}
+}
+
+func TestFakeSetter(t *testing.T) {
+	var out bytes.Buffer
+	setter := &FakeSetter{Out: &out}
+	if err := setter.Set(AllMonitors, "/w/a.png", "cover"); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "Would set /w/a.png on all outputs (cover)\n" {
+		t.Errorf("printed %q", got)
+	}
+
+	setter.Err = errors.New("backend down")
+	if err := setter.Set("DP-1", "/w/b.png", "fill"); err == nil {
+		t.Error("expected the configured error")
+	}
+	if len(setter.Calls) != 2 || setter.Calls[1] != (WallpaperCall{"DP-1", "/w/b.png", "fill"}) {
+		t.Errorf("calls = %v", setter.Calls)
+	}
+}
+
+func TestNewWallpaperSetterUnknown(t *testing.T) {
+	if _, err := NewWallpaperSetter("feh", OSProbe{}); err == nil {
+		t.Error("expected an error for an unknown backend")
+	}
+}
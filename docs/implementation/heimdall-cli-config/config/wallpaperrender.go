@@ -0,0 +1,481 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// wallpaperPipelineVersion is part of every cache key; bump it when the
+// pipeline's output changes so stale renders are not reused
+const wallpaperPipelineVersion = 1
+
+// WallpaperCacheDirName is the rendered wallpaper directory under the cache dir
+const WallpaperCacheDirName = "wallpapers"
+
+// WallpaperParams describes one processed rendering of a wallpaper
+type WallpaperParams struct {
+	Width    int
+	Height   int
+	FillMode string
+	// Blur is the Gaussian standard deviation in output pixels, 0 for none
+	Blur int
+	// Dim darkens the image by this fraction (0-1), 0 for none
+	Dim float64
+}
+
+// WallpaperParamsFromConfig returns the parameters the wallpaper section
+// asks for at a resolution
+func WallpaperParamsFromConfig(wallpaper *WallpaperConfig, width, height int) WallpaperParams {
+	params := WallpaperParams{Width: width, Height: height, FillMode: wallpaper.FillMode}
+	if wallpaper.Blur {
+		params.Blur = wallpaper.BlurStrength
+	}
+	if wallpaper.Dim {
+		params.Dim = wallpaper.DimStrength
+	}
+	return params
+}
+
+// NeedsProcessing reports whether the wallpaper section asks for blur or
+// dimming, which the backends cannot do themselves
+func (w *WallpaperConfig) NeedsProcessing() bool {
+	return (w.Blur && w.BlurStrength > 0) || (w.Dim && w.DimStrength > 0)
+}
+
+// String is the canonical form of the parameters used in cache keys
+func (p WallpaperParams) String() string {
+	fill := p.FillMode
+	if fill == "" {
+		fill = "cover"
+	}
+	return fmt.Sprintf("%dx%d fill=%s blur=%d dim=%s", p.Width, p.Height, fill, p.Blur,
+		strconv.FormatFloat(p.Dim, 'f', -1, 64))
+}
+
+// Validate checks the size and ranges
+func (p WallpaperParams) Validate() error {
+	if p.Width <= 0 || p.Height <= 0 {
+		return fmt.Errorf("invalid size %dx%d", p.Width, p.Height)
+	}
+	if p.FillMode != "" && !contains(FieldEnums["wallpaper.fillMode"], p.FillMode) {
+		return fmt.Errorf("invalid fill mode: %s (use %s)", p.FillMode, strings.Join(FieldEnums["wallpaper.fillMode"], ", "))
+	}
+	if p.Blur < 0 {
+		return fmt.Errorf("blur must be non-negative")
+	}
+	if p.Dim < 0 || p.Dim > 1 {
+		return fmt.Errorf("dim must be between 0 and 1")
+	}
+	return nil
+}
+
+// ParseResolution parses "WIDTHxHEIGHT"
+func ParseResolution(value string) (int, int, error) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(value)), "x")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid resolution %q (use WIDTHxHEIGHT)", value)
+	}
+	width, err1 := strconv.Atoi(parts[0])
+	height, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil || width <= 0 || height <= 0 {
+		return 0, 0, fmt.Errorf("invalid resolution %q (use WIDTHxHEIGHT)", value)
+	}
+	return width, height, nil
+}
+
+// ProcessWallpaper fits src into a Width×Height canvas with the fill mode,
+// then blurs and dims it. Uncovered areas (contain, scale-down, none) are black.
+//
+//	cover       scale to cover the canvas, cropping the overflow (default)
+//	contain     scale to fit inside the canvas
+//	fill        stretch to the canvas, ignoring the aspect ratio
+//	scale-down  like contain, but never enlarge
+//	none        keep the original size, centered
+func ProcessWallpaper(src image.Image, p WallpaperParams) (*image.RGBA, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	bounds := src.Bounds()
+	sw, sh := float64(bounds.Dx()), float64(bounds.Dy())
+	if sw == 0 || sh == 0 {
+		return nil, fmt.Errorf("empty source image")
+	}
+	dw, dh := float64(p.Width), float64(p.Height)
+
+	scaleX, scaleY := 1.0, 1.0
+	switch p.FillMode {
+	case "cover", "":
+		scaleX = math.Max(dw/sw, dh/sh)
+		scaleY = scaleX
+	case "contain":
+		scaleX = math.Min(dw/sw, dh/sh)
+		scaleY = scaleX
+	case "scale-down":
+		scaleX = math.Min(1, math.Min(dw/sw, dh/sh))
+		scaleY = scaleX
+	case "fill":
+		scaleX, scaleY = dw/sw, dh/sh
+	}
+
+	// Scaled size, rounded, and its offset on the canvas (negative crops)
+	w := int(math.Max(1, math.Round(sw*scaleX)))
+	h := int(math.Max(1, math.Round(sh*scaleY)))
+	scaled := toRGBA(src)
+	if w != bounds.Dx() || h != bounds.Dy() {
+		scaled = resizeRGBA(scaled, w, h)
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, p.Width, p.Height))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	offset := image.Pt((p.Width-w)/2, (p.Height-h)/2)
+	draw.Draw(canvas, scaled.Bounds().Add(offset), scaled, image.Point{}, draw.Over)
+
+	if p.Blur > 0 {
+		gaussianBlur(canvas, float64(p.Blur))
+	}
+	if p.Dim > 0 {
+		dimRGBA(canvas, p.Dim)
+	}
+	return canvas, nil
+}
+
+// toRGBA copies an image into an *image.RGBA with bounds at the origin
+func toRGBA(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+	return dst
+}
+
+// resizeRGBA resamples with a separable triangle filter. When shrinking the
+// filter widens to cover every source pixel, so downscaling averages
+// instead of skipping pixels.
+func resizeRGBA(src *image.RGBA, width, height int) *image.RGBA {
+	b := src.Bounds()
+	horizontal := resampleWeights(b.Dx(), width)
+	vertical := resampleWeights(b.Dy(), height)
+
+	// Horizontal pass into float rows, then vertical into the result
+	tmp := make([]float32, width*b.Dy()*4)
+	parallelRows(b.Dy(), func(y int) {
+		row := src.Pix[y*src.Stride:]
+		for x, taps := range horizontal {
+			var r, g, bl, a float32
+			for _, t := range taps {
+				i := t.index * 4
+				r += float32(row[i]) * t.weight
+				g += float32(row[i+1]) * t.weight
+				bl += float32(row[i+2]) * t.weight
+				a += float32(row[i+3]) * t.weight
+			}
+			j := (y*width + x) * 4
+			tmp[j], tmp[j+1], tmp[j+2], tmp[j+3] = r, g, bl, a
+		}
+	})
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	parallelRows(height, func(y int) {
+		taps := vertical[y]
+		for x := 0; x < width; x++ {
+			var px [4]float32
+			for _, t := range taps {
+				i := (t.index*width + x) * 4
+				px[0] += tmp[i] * t.weight
+				px[1] += tmp[i+1] * t.weight
+				px[2] += tmp[i+2] * t.weight
+				px[3] += tmp[i+3] * t.weight
+			}
+			j := y*dst.Stride + x*4
+			for c := 0; c < 4; c++ {
+				dst.Pix[j+c] = clampByte(px[c])
+			}
+		}
+	})
+	return dst
+}
+
+// filterTap is one source sample's weight in a resampled pixel
+type filterTap struct {
+	index  int
+	weight float32
+}
+
+// resampleWeights returns the normalized triangle-filter taps of every
+// destination pixel along one axis
+func resampleWeights(srcSize, dstSize int) [][]filterTap {
+	scale := float64(dstSize) / float64(srcSize)
+	support := 1.0
+	if scale < 1 {
+		support = 1 / scale
+	}
+
+	weights := make([][]filterTap, dstSize)
+	for i := range weights {
+		center := (float64(i)+0.5)/scale - 0.5
+		lo := int(math.Floor(center - support))
+		hi := int(math.Ceil(center + support))
+		taps := make([]filterTap, 0, hi-lo+1)
+		var sum float64
+		for j := lo; j <= hi; j++ {
+			w := 1 - math.Abs(float64(j)-center)/support
+			if w <= 0 {
+				continue
+			}
+			index := j
+			if index < 0 {
+				index = 0
+			} else if index >= srcSize {
+				index = srcSize - 1
+			}
+			taps = append(taps, filterTap{index: index, weight: float32(w)})
+			sum += w
+		}
+		for k := range taps {
+			taps[k].weight /= float32(sum)
+		}
+		weights[i] = taps
+	}
+	return weights
+}
+
+// gaussianBlur blurs img in place with a separable Gaussian kernel of
+// standard deviation sigma, clamping at the edges
+func gaussianBlur(img *image.RGBA, sigma float64) {
+	radius := int(math.Ceil(sigma * 3))
+	kernel := make([]float32, 2*radius+1)
+	var sum float64
+	for i := -radius; i <= radius; i++ {
+		w := math.Exp(-float64(i*i) / (2 * sigma * sigma))
+		kernel[i+radius] = float32(w)
+		sum += w
+	}
+	for i := range kernel {
+		kernel[i] /= float32(sum)
+	}
+
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	tmp := make([]float32, width*height*4)
+	clampIndex := func(i, n int) int {
+		if i < 0 {
+			return 0
+		}
+		if i >= n {
+			return n - 1
+		}
+		return i
+	}
+
+	parallelRows(height, func(y int) {
+		row := img.Pix[y*img.Stride:]
+		for x := 0; x < width; x++ {
+			var px [4]float32
+			for k, w := range kernel {
+				i := clampIndex(x+k-radius, width) * 4
+				px[0] += float32(row[i]) * w
+				px[1] += float32(row[i+1]) * w
+				px[2] += float32(row[i+2]) * w
+				px[3] += float32(row[i+3]) * w
+			}
+			copy(tmp[(y*width+x)*4:], px[:])
+		}
+	})
+	parallelRows(height, func(y int) {
+		for x := 0; x < width; x++ {
+			var px [4]float32
+			for k, w := range kernel {
+				i := (clampIndex(y+k-radius, height)*width + x) * 4
+				px[0] += tmp[i] * w
+				px[1] += tmp[i+1] * w
+				px[2] += tmp[i+2] * w
+				px[3] += tmp[i+3] * w
+			}
+			j := y*img.Stride + x*4
+			for c := 0; c < 4; c++ {
+				img.Pix[j+c] = clampByte(px[c])
+			}
+		}
+	})
+}
+
+// dimRGBA darkens img in place by amount (0-1), keeping alpha
+func dimRGBA(img *image.RGBA, amount float64) {
+	factor := float32(1 - amount)
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i] = clampByte(float32(img.Pix[i]) * factor)
+		img.Pix[i+1] = clampByte(float32(img.Pix[i+1]) * factor)
+		img.Pix[i+2] = clampByte(float32(img.Pix[i+2]) * factor)
+	}
+}
+
+// parallelRows calls fn for every row, spread over the CPUs. Rows are
+// independent, so the result does not depend on scheduling.
+func parallelRows(rows int, fn func(y int)) {
+	workers := runtime.NumCPU()
+	if workers > rows {
+		workers = rows
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(start int) {
+			defer wg.Done()
+			for y := start; y < rows; y += workers {
+				fn(y)
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// clampByte rounds a channel value to 0-255
+func clampByte(v float32) uint8 {
+	if v <= 0 {
+		return 0
+	}
+	if v >= 255 {
+		return 255
+	}
+	return uint8(v + 0.5)
+}
+
+// WallpaperCache stores processed wallpapers keyed by the source file's
+// content hash and the parameters, so renames and edits are handled and a
+// render is only ever computed once
+type WallpaperCache struct {
+	dir string
+}
+
+// NewWallpaperCache creates a cache in dir
+func NewWallpaperCache(dir string) *WallpaperCache {
+	return &WallpaperCache{dir: dir}
+}
+
+// GetWallpaperCacheDir returns the rendered wallpaper directory
+func GetWallpaperCacheDir() string {
+	return filepath.Join(GetCacheDir(), WallpaperCacheDirName)
+}
+
+// Dir returns the cache directory
+func (c *WallpaperCache) Dir() string {
+	return c.dir
+}
+
+// Path returns where the rendering of a source with the given content hash
+// is stored
+func (c *WallpaperCache) Path(sourceHash string, p WallpaperParams) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("v%d %s %s", wallpaperPipelineVersion, sourceHash, p)))
+	return filepath.Join(c.dir, fmt.Sprintf("%s-%dx%d.png", hex.EncodeToString(sum[:])[:32], p.Width, p.Height))
+}
+
+// Render returns the processed copy of source, rendering and storing it
+// unless the cache already holds it. cached reports a cache hit.
+func (c *WallpaperCache) Render(source string, p WallpaperParams) (path string, cached bool, err error) {
+	if err := p.Validate(); err != nil {
+		return "", false, err
+	}
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read wallpaper: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	path = c.Path(hex.EncodeToString(sum[:]), p)
+	if _, err := os.Stat(path); err == nil {
+		return path, true, nil
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to decode %s: %w", source, err)
+	}
+	out, err := ProcessWallpaper(src, p)
+	if err != nil {
+		return "", false, err
+	}
+	encoded, err := EncodePNG(out)
+	if err != nil {
+		return "", false, err
+	}
+	if err := WriteRenderedFile(path, encoded); err != nil {
+		return "", false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, false, nil
+}
+
+// EncodePNG encodes img as PNG with the default compression, which is
+// deterministic for the same pixels
+func EncodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ImageDrift compares a rendering with the PNG at path channel by channel.
+// Differences up to tolerance are ignored, since float rounding may differ
+// between CPU architectures. It returns the number of pixels that differ
+// more and the largest channel difference.
+func ImageDrift(path string, rendered *image.RGBA, tolerance int) (pixels, maxDelta int, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer file.Close()
+	golden, _, err := image.Decode(file)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	if golden.Bounds().Size() != rendered.Bounds().Size() {
+		return 0, 0, fmt.Errorf("%s is %dx%d, rendered %dx%d", path,
+			golden.Bounds().Dx(), golden.Bounds().Dy(), rendered.Bounds().Dx(), rendered.Bounds().Dy())
+	}
+
+	expected := toRGBA(golden)
+	for i := 0; i < len(expected.Pix); i += 4 {
+		differs := false
+		for c := 0; c < 4; c++ {
+			delta := int(expected.Pix[i+c]) - int(rendered.Pix[i+c])
+			if delta < 0 {
+				delta = -delta
+			}
+			if delta > maxDelta {
+				maxDelta = delta
+			}
+			if delta > tolerance {
+				differs = true
+			}
+		}
+		if differs {
+			pixels++
+		}
+	}
+	return pixels, maxDelta, nil
+}
+
+// ProcessWallpaperFile decodes an image file and processes it, bypassing
+// the cache
+func ProcessWallpaperFile(source string, p WallpaperParams) (*image.RGBA, error) {
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallpaper: %w", err)
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", source, err)
+	}
+	return ProcessWallpaper(src, p)
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestWallpaperRenderGolden renders each case in
+// testdata/wallpapers/render/cases.tsv and compares it with <name>.png,
+// allowing the same off-by-one rounding as 'wallpaper render --check'
+func TestWallpaperRenderGolden(t *testing.T) {
+	dir := filepath.Join("..", "testdata", "wallpapers")
+	file, err := os.Open(filepath.Join(dir, "render", "cases.tsv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	cases := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 6 {
+			t.Fatalf("malformed case: %q", line)
+		}
+		cases++
+
+		name := fields[0]
+		t.Run(name, func(t *testing.T) {
+			width, height, err := ParseResolution(fields[2])
+			if err != nil {
+				t.Fatal(err)
+			}
+			blur, err := strconv.Atoi(fields[4])
+			if err != nil {
+				t.Fatal(err)
+			}
+			dim, err := strconv.ParseFloat(fields[5], 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			params := WallpaperParams{Width: width, Height: height, FillMode: fields[3], Blur: blur, Dim: dim}
+
+			rendered, err := ProcessWallpaperFile(filepath.Join(dir, fields[1]), params)
+			if err != nil {
+				t.Fatalf("ProcessWallpaperFile: %v", err)
+			}
+			pixels, maxDelta, err := ImageDrift(filepath.Join(dir, "render", name+".png"), rendered, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pixels > 0 {
+				t.Errorf("%d pixels differ from the golden image (max channel delta %d)", pixels, maxDelta)
+			}
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if cases == 0 {
+		t.Fatal("no render cases found")
+	}
+}
+
+func TestWallpaperCache(t *testing.T) {
+	cache := NewWallpaperCache(t.TempDir())
+	source := filepath.Join("..", "testdata", "wallpapers", "forest.png")
+	params := WallpaperParams{Width: 64, Height: 36, FillMode: "cover", Dim: 0.25}
+
+	path, cached, err := cache.Render(source, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached {
+		t.Error("first render reported a cache hit")
+	}
+	again, cached, err := cache.Render(source, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cached || again != path {
+		t.Errorf("second render = %s (cached %v), want a hit on %s", again, cached, path)
+	}
+
+	params.Dim = 0.5
+	if other, _, _ := cache.Render(source, params); other == path {
+		t.Error("different parameters share a cache entry")
+	}
+}
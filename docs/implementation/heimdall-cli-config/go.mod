@@ -3,10 +3,15 @@ module heimdall-cli
 go 1.21
 
 require (
+	github.com/pelletier/go-toml/v2 v2.1.1
 	github.com/spf13/cobra v1.8.0
+	golang.org/x/image v0.18.0
+	golang.org/x/term v0.10.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
-)
\ No newline at end of file
+	golang.org/x/sys v0.10.0 // indirect
+)
@@ -0,0 +1,220 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"heimdall-cli/config"
+)
+
+// Level is a log severity
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String returns the lowercase level name
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+}
+
+// ParseLevel parses a level name
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info", "":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return LevelInfo, fmt.Errorf("unknown log level: %s (use debug, info, warn or error)", name)
+	}
+}
+
+// Format selects how entries are written
+type Format int
+
+const (
+	FormatText Format = iota
+	FormatJSON
+)
+
+// ParseFormat parses a format name
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "text", "":
+		return FormatText, nil
+	case "json":
+		return FormatJSON, nil
+	default:
+		return FormatText, fmt.Errorf("unknown log format: %s (use text or json)", name)
+	}
+}
+
+// Options configures a Logger
+type Options struct {
+	Level  Level
+	Format Format
+	// Output receives every entry; defaults to stderr
+	Output io.Writer
+	// File, when set, also receives every entry through a rotating writer
+	File string
+	// MaxSize is the file size in bytes that triggers rotation
+	MaxSize int64
+	// MaxBackups is the number of rotated files kept
+	MaxBackups int
+}
+
+// Logger is a leveled config.Logger writing text or JSON lines
+type Logger struct {
+	level  Level
+	format Format
+	out    io.Writer
+	file   *RotatingFile
+	now    func() time.Time
+	mu     sync.Mutex
+}
+
+// New creates a logger from options
+func New(opts Options) (*Logger, error) {
+	out := opts.Output
+	if out == nil {
+		out = os.Stderr
+	}
+
+	l := &Logger{
+		level:  opts.Level,
+		format: opts.Format,
+		out:    out,
+		now:    time.Now,
+	}
+
+	if opts.File != "" {
+		file, err := NewRotatingFile(opts.File, opts.MaxSize, opts.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		l.file = file
+		l.out = io.MultiWriter(out, file)
+	}
+
+	return l, nil
+}
+
+// Debug logs a debug message
+func (l *Logger) Debug(msg string, fields ...config.Field) {
+	l.log(LevelDebug, msg, fields)
+}
+
+// Info logs an informational message
+func (l *Logger) Info(msg string, fields ...config.Field) {
+	l.log(LevelInfo, msg, fields)
+}
+
+// Warn logs a warning
+func (l *Logger) Warn(msg string, fields ...config.Field) {
+	l.log(LevelWarn, msg, fields)
+}
+
+// Error logs an error
+func (l *Logger) Error(msg string, fields ...config.Field) {
+	l.log(LevelError, msg, fields)
+}
+
+// Close releases the log file, if any
+func (l *Logger) Close() error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// log formats and writes an entry at or above the configured level
+func (l *Logger) log(level Level, msg string, fields []config.Field) {
+	if level < l.level {
+		return
+	}
+
+	var line string
+	if l.format == FormatJSON {
+		line = l.formatJSON(level, msg, fields)
+	} else {
+		line = l.formatText(level, msg, fields)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	io.WriteString(l.out, line)
+}
+
+// formatText renders "time [LEVEL] msg key=value"
+func (l *Logger) formatText(level Level, msg string, fields []config.Field) string {
+	var sb strings.Builder
+	sb.WriteString(l.now().Format(time.RFC3339))
+	sb.WriteString(" [")
+	sb.WriteString(strings.ToUpper(level.String()))
+	sb.WriteString("] ")
+	sb.WriteString(msg)
+	for _, f := range fields {
+		fmt.Fprintf(&sb, " %s=%v", f.Key, f.Value)
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// formatJSON renders a single JSON object per line
+func (l *Logger) formatJSON(level Level, msg string, fields []config.Field) string {
+	entry := make(map[string]interface{}, len(fields)+3)
+	for _, f := range fields {
+		entry[f.Key] = jsonValue(f.Value)
+	}
+	entry["time"] = l.now().Format(time.RFC3339Nano)
+	entry["level"] = level.String()
+	entry["msg"] = msg
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		data, _ = json.Marshal(map[string]interface{}{
+			"time":  entry["time"],
+			"level": level.String(),
+			"msg":   msg,
+			"error": fmt.Sprintf("failed to encode fields: %v", err),
+		})
+	}
+	return string(data) + "\n"
+}
+
+// jsonValue makes errors and other non-marshalable values printable
+func jsonValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case error:
+		return value.Error()
+	case fmt.Stringer:
+		return value.String()
+	default:
+		return v
+	}
+}
@@ -0,0 +1,130 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"heimdall-cli/config"
+)
+
+// fixedTime is the clock of every test logger
+var fixedTime = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestLogger(t *testing.T, level Level, format Format) (*Logger, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	logger, err := New(Options{Level: level, Format: format, Output: &buf})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	logger.now = func() time.Time { return fixedTime }
+	return logger, &buf
+}
+
+func TestLoggerLevelFilter(t *testing.T) {
+	logger, buf := newTestLogger(t, LevelWarn, FormatText)
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], "[WARN] warn") || !strings.Contains(lines[1], "[ERROR] error") {
+		t.Errorf("unexpected lines:\n%s", buf.String())
+	}
+}
+
+func TestLoggerText(t *testing.T) {
+	logger, buf := newTestLogger(t, LevelDebug, FormatText)
+	logger.Info("Saved", config.Field{Key: "path", Value: "/tmp/shell.json"}, config.Field{Key: "size", Value: 12})
+
+	want := "2024-05-01T12:00:00Z [INFO] Saved path=/tmp/shell.json size=12\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestLoggerJSON(t *testing.T) {
+	logger, buf := newTestLogger(t, LevelDebug, FormatJSON)
+	logger.Error("Failed", config.Field{Key: "error", Value: errors.New("boom")})
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid JSON line %q: %v", buf.String(), err)
+	}
+	want := map[string]interface{}{
+		"time":  "2024-05-01T12:00:00Z",
+		"level": "error",
+		"msg":   "Failed",
+		"error": "boom",
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("%s = %v, want %v", key, entry[key], value)
+		}
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "heimdall.log")
+	file, err := NewRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	defer file.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	want := map[string]string{
+		path:        "fourth\n",
+		path + ".1": "third\n",
+		path + ".2": "second\n",
+	}
+	for name, content := range want {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+		if string(data) != content {
+			t.Errorf("%s = %q, want %q", filepath.Base(name), data, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected only 2 backups, found %s.3", filepath.Base(path))
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder()
+	var logger config.Logger = recorder
+	logger.Info("Applying migration", config.Field{Key: "to", Value: "1.1.0"})
+	logger.Warn("Skipped")
+
+	if !recorder.Has(LevelInfo, "Applying migration") {
+		t.Error("expected the info entry to be recorded")
+	}
+	if got := len(recorder.EntriesAt(LevelWarn)); got != 1 {
+		t.Errorf("got %d warn entries, want 1", got)
+	}
+	if to, ok := recorder.Entries()[0].Field("to"); !ok || to != "1.1.0" {
+		t.Errorf("field to = %v, %v", to, ok)
+	}
+
+	recorder.Reset()
+	if len(recorder.Entries()) != 0 {
+		t.Error("Reset kept entries")
+	}
+}
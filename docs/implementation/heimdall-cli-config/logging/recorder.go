@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"sync"
+	"time"
+
+	"heimdall-cli/config"
+)
+
+// Entry is a captured log entry
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Fields  []config.Field
+}
+
+// Field returns the value of a named field and whether it was present
+func (e Entry) Field(key string) (interface{}, bool) {
+	for _, f := range e.Fields {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Recorder is a config.Logger that captures entries for assertions in tests
+type Recorder struct {
+	entries []Entry
+	mu      sync.Mutex
+}
+
+// NewRecorder creates an empty recorder
+func NewRecorder() *Recorder {
+	return &Recorder{entries: make([]Entry, 0)}
+}
+
+func (r *Recorder) Debug(msg string, fields ...config.Field) { r.record(LevelDebug, msg, fields) }
+func (r *Recorder) Info(msg string, fields ...config.Field)  { r.record(LevelInfo, msg, fields) }
+func (r *Recorder) Warn(msg string, fields ...config.Field)  { r.record(LevelWarn, msg, fields) }
+func (r *Recorder) Error(msg string, fields ...config.Field) { r.record(LevelError, msg, fields) }
+
+// Entries returns a copy of every captured entry
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := make([]Entry, len(r.entries))
+	copy(entries, r.entries)
+	return entries
+}
+
+// EntriesAt returns the captured entries with the given level
+func (r *Recorder) EntriesAt(level Level) []Entry {
+	matched := make([]Entry, 0)
+	for _, e := range r.Entries() {
+		if e.Level == level {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// Has reports whether an entry with the given level and message was captured
+func (r *Recorder) Has(level Level, msg string) bool {
+	for _, e := range r.Entries() {
+		if e.Level == level && e.Message == msg {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset discards all captured entries
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = r.entries[:0]
+}
+
+// record stores an entry
+func (r *Recorder) record(level Level, msg string, fields []config.Field) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, Entry{
+		Time:    time.Now(),
+		Level:   level,
+		Message: msg,
+		Fields:  append([]config.Field(nil), fields...),
+	})
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/spf13/cobra"
 	"heimdall-cli/commands"
+	"heimdall-cli/config"
+	"heimdall-cli/logging"
 )
 
 var (
@@ -23,8 +25,24 @@ var rootCmd = &cobra.Command{
 It provides intelligent configuration management with automatic discovery, validation,
 migration, and property injection capabilities.
 
-Configuration is stored at ~/.config/heimdall/shell.json and consumed by Quickshell.`,
+Configuration is stored at ~/.config/heimdall/shell.json and consumed by Quickshell.
+Any value can be overridden for a single run with --set bar.height=40 or with
+environment variables such as HEIMDALL__BAR__HEIGHT=40.`,
 	Version: Version,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		level, _ := cmd.Flags().GetString("log-level")
+		format, _ := cmd.Flags().GetString("log-format")
+		file, _ := cmd.Flags().GetString("log-file")
+		if toFile, _ := cmd.Flags().GetBool("log-to-file"); toFile && file == "" {
+			file = logging.DefaultLogFile()
+		}
+		if err := commands.ConfigureLogging(level, format, file); err != nil {
+			return err
+		}
+
+		sets, _ := cmd.Flags().GetStringArray("set")
+		return config.SetFlagOverrides(sets)
+	},
 }
 
 // versionCmd shows version information
@@ -40,9 +58,30 @@ var versionCmd = &cobra.Command{
 }
 
 func init() {
+	// Logging flags
+	defaultLevel := os.Getenv("HEIMDALL_LOG_LEVEL")
+	if defaultLevel == "" {
+		defaultLevel = "info"
+	}
+	rootCmd.PersistentFlags().String("log-level", defaultLevel, "Log level: debug, info, warn or error")
+	rootCmd.PersistentFlags().String("log-format", "text", "Log format: text or json")
+	rootCmd.PersistentFlags().String("log-file", "", "Also write logs to this rotating file")
+	rootCmd.PersistentFlags().Bool("log-to-file", false, "Also write logs to $XDG_STATE_HOME/heimdall/heimdall.log")
+
+	// Temporary overrides, layered over every config file and never saved
+	rootCmd.PersistentFlags().StringArray("set", nil, "Override a config value for this run (path=value, repeatable)")
+
 	// Add commands
 	rootCmd.AddCommand(commands.ConfigCmd)
+	rootCmd.AddCommand(commands.RenderCmd)
+	rootCmd.AddCommand(commands.SchemeCmd)
+	rootCmd.AddCommand(commands.ThemeCmd)
+	rootCmd.AddCommand(commands.WallpaperCmd)
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(commands.CompletionCmd)
+
+	// Replace cobra's default completion command with ours
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
 	// Set version template
 	rootCmd.SetVersionTemplate(`{{.Version}}`)
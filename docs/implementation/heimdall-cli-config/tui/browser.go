@@ -0,0 +1,572 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"heimdall-cli/config"
+)
+
+// Store persists the edited configuration
+type Store interface {
+	Save(cfg *config.ShellConfig) error
+}
+
+// mode is the current interaction mode of the browser
+type mode int
+
+const (
+	modeBrowse mode = iota
+	modeEdit
+	modePick
+)
+
+// node is an entry of the schema tree
+type node struct {
+	name     string
+	path     string
+	depth    int
+	field    *config.FieldInfo
+	children []*node
+}
+
+// isLeaf reports whether the node holds a value
+func (n *node) isLeaf() bool {
+	return n.field != nil
+}
+
+// Browser is an interactive tree browser over the ShellConfig schema
+type Browser struct {
+	store     Store
+	validator *config.SchemaValidator
+	injector  *config.PropertyInjector
+	out       io.Writer
+
+	original map[string]interface{}
+	working  map[string]interface{}
+	defaults map[string]interface{}
+	extra    map[string]interface{}
+	issues   map[string][]config.ValidationError
+
+	root     *node
+	expanded map[string]bool
+	cursor   int
+	offset   int
+	height   int
+
+	mode        mode
+	input       []rune
+	pickOptions []string
+	pickIndex   int
+
+	status    string
+	dirty     bool
+	quitArmed bool
+}
+
+// NewBrowser creates a browser editing a copy of cfg
+func NewBrowser(cfg *config.ShellConfig, store Store, out io.Writer) (*Browser, error) {
+	original, err := config.ConfigToMap(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert config to map: %w", err)
+	}
+	working, err := config.ConfigToMap(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert config to map: %w", err)
+	}
+	profile, err := config.LoadProfile(cfg.Metadata.Profile)
+	if err != nil {
+		profile = config.GetDefaultConfig()
+	}
+	defaults, err := config.ConfigToMap(profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert defaults to map: %w", err)
+	}
+
+	// Profile names are read once; the browser validates on every key
+	validator := config.NewSchemaValidator()
+	validator.SetKnownProfiles(config.NewProfileStore(config.GetProfilesDir()).Names())
+
+	b := &Browser{
+		store:     store,
+		validator: validator,
+		injector:  config.NewPropertyInjector(),
+		out:       out,
+		original:  original,
+		working:   working,
+		defaults:  defaults,
+		extra:     cfg.Extra,
+		root:      buildTree(config.SchemaFields()),
+		expanded:  make(map[string]bool),
+		height:    24,
+	}
+	b.revalidate()
+
+	return b, nil
+}
+
+// SetHeight sets the number of terminal rows available to the browser
+func (b *Browser) SetHeight(rows int) {
+	if rows > 6 {
+		b.height = rows
+	}
+}
+
+// Run renders the browser and processes keys until quit or end of input
+func (b *Browser) Run(in InputSource) error {
+	b.render()
+
+	for {
+		key, err := in.ReadKey()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+
+		quit := b.HandleKey(key)
+		b.render()
+		if quit {
+			return nil
+		}
+	}
+}
+
+// Config returns the configuration currently being edited
+func (b *Browser) Config() (*config.ShellConfig, error) {
+	cfg, err := config.MapToConfig(b.working)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Extra = b.extra
+	return cfg, nil
+}
+
+// Dirty reports whether there are unsaved changes
+func (b *Browser) Dirty() bool {
+	return b.dirty
+}
+
+// Status returns the last status line message
+func (b *Browser) Status() string {
+	return b.status
+}
+
+// HandleKey applies a single key press and reports whether to quit
+func (b *Browser) HandleKey(key Key) bool {
+	switch b.mode {
+	case modeEdit:
+		b.handleEditKey(key)
+		return false
+	case modePick:
+		b.handlePickKey(key)
+		return false
+	}
+
+	rows := b.visibleRows()
+	if len(rows) == 0 {
+		return true
+	}
+	current := rows[b.cursor]
+
+	// q and Ctrl+C quit, asking first when there are unsaved changes; any
+	// other key disarms the confirmation
+	if key.Type == KeyInterrupt || (key.Type == KeyRune && key.Rune == 'q') {
+		if !b.dirty || b.quitArmed {
+			return true
+		}
+		b.quitArmed = true
+		b.status = "Unsaved changes: press q again to discard, s to save"
+		return false
+	}
+	b.quitArmed = false
+
+	switch key.Type {
+	case KeyUp:
+		b.moveCursor(-1)
+	case KeyDown, KeyTab:
+		b.moveCursor(1)
+	case KeyRight:
+		if current.isLeaf() {
+			b.cycleEnum(current, 1)
+		} else {
+			b.expanded[current.path] = true
+		}
+	case KeyLeft:
+		if current.isLeaf() && len(current.field.Enum) > 0 {
+			b.cycleEnum(current, -1)
+		} else if !current.isLeaf() && b.expanded[current.path] {
+			delete(b.expanded, current.path)
+		} else {
+			b.selectParent(current)
+		}
+	case KeyEnter:
+		b.activate(current)
+	case KeyRune:
+		switch key.Rune {
+		case 'k':
+			b.moveCursor(-1)
+		case 'j':
+			b.moveCursor(1)
+		case ' ':
+			b.activate(current)
+		case 's':
+			b.save()
+		case 'r':
+			b.revert(current)
+		case 'R':
+			b.revertAll()
+		case 'd':
+			b.resetToDefault(current)
+		}
+	}
+
+	return false
+}
+
+// activate expands a section or starts editing a leaf
+func (b *Browser) activate(n *node) {
+	if !n.isLeaf() {
+		b.expanded[n.path] = !b.expanded[n.path]
+		return
+	}
+	if b.isLocked(n.path) {
+		b.status = fmt.Sprintf("%s is locked; unlock it before editing", n.path)
+		return
+	}
+
+	switch {
+	case len(n.field.Enum) > 0:
+		b.mode = modePick
+		b.pickOptions = n.field.Enum
+		b.pickIndex = 0
+		current := config.FormatValue(b.value(n.path))
+		for i, option := range b.pickOptions {
+			if option == current {
+				b.pickIndex = i
+			}
+		}
+	case n.field.Kind == reflect.Bool:
+		value, _ := b.value(n.path).(bool)
+		b.apply(n.path, !value)
+	default:
+		b.mode = modeEdit
+		b.input = []rune(config.FormatValue(b.value(n.path)))
+	}
+}
+
+// handleEditKey processes keys while editing a value
+func (b *Browser) handleEditKey(key Key) {
+	switch key.Type {
+	case KeyEscape, KeyInterrupt:
+		b.mode = modeBrowse
+		b.input = nil
+		b.status = "Edit cancelled"
+	case KeyEnter:
+		n := b.visibleRows()[b.cursor]
+		value, err := config.ParseValue(n.path, string(b.input))
+		if err != nil {
+			b.status = err.Error()
+			return
+		}
+		b.mode = modeBrowse
+		b.input = nil
+		b.apply(n.path, value)
+	case KeyBackspace:
+		if len(b.input) > 0 {
+			b.input = b.input[:len(b.input)-1]
+		}
+	case KeyRune:
+		b.input = append(b.input, key.Rune)
+	}
+}
+
+// handlePickKey processes keys while choosing an enum value
+func (b *Browser) handlePickKey(key Key) {
+	switch key.Type {
+	case KeyEscape, KeyInterrupt:
+		b.mode = modeBrowse
+		b.status = "Selection cancelled"
+	case KeyUp:
+		if b.pickIndex > 0 {
+			b.pickIndex--
+		}
+	case KeyDown, KeyTab:
+		if b.pickIndex < len(b.pickOptions)-1 {
+			b.pickIndex++
+		}
+	case KeyEnter:
+		n := b.visibleRows()[b.cursor]
+		b.mode = modeBrowse
+		b.apply(n.path, b.pickOptions[b.pickIndex])
+	case KeyRune:
+		switch key.Rune {
+		case 'k':
+			b.handlePickKey(Key{Type: KeyUp})
+		case 'j':
+			b.handlePickKey(Key{Type: KeyDown})
+		case ' ':
+			b.handlePickKey(Key{Type: KeyEnter})
+		}
+	}
+}
+
+// cycleEnum steps an enum leaf to its next or previous member
+func (b *Browser) cycleEnum(n *node, step int) {
+	options := n.field.Enum
+	if len(options) == 0 {
+		return
+	}
+	if b.isLocked(n.path) {
+		b.status = fmt.Sprintf("%s is locked; unlock it before editing", n.path)
+		return
+	}
+
+	index := -1
+	current := config.FormatValue(b.value(n.path))
+	for i, option := range options {
+		if option == current {
+			index = i
+		}
+	}
+	index = (index + step + len(options)) % len(options)
+	b.apply(n.path, options[index])
+}
+
+// apply writes a value into the working config and revalidates it
+func (b *Browser) apply(path string, value interface{}) {
+	previous := b.value(path)
+	config.SetMapPath(b.working, path, value)
+
+	// Round-trip through the schema so the stored value has its canonical type
+	cfg, err := config.MapToConfig(b.working)
+	if err != nil {
+		config.SetMapPath(b.working, path, previous)
+		b.status = fmt.Sprintf("Rejected %s: %v", path, err)
+		return
+	}
+	if normalized, err := config.ConfigToMap(cfg); err == nil {
+		b.working = normalized
+	}
+
+	b.dirty = !reflect.DeepEqual(b.working, b.original)
+	b.revalidate()
+
+	if issues := b.issues[path]; len(issues) > 0 {
+		b.status = fmt.Sprintf("%s: %s", path, issues[0].Message)
+	} else {
+		b.status = fmt.Sprintf("Set %s = %s", path, config.FormatValue(b.value(path)))
+	}
+}
+
+// save validates and persists the working config
+func (b *Browser) save() {
+	cfg, err := b.Config()
+	if err != nil {
+		b.status = fmt.Sprintf("Save failed: %v", err)
+		return
+	}
+	if b.hasBlockingIssues() {
+		b.status = "Save refused: fix validation errors first"
+		return
+	}
+	if err := b.store.Save(cfg); err != nil {
+		b.status = fmt.Sprintf("Save failed: %v", err)
+		return
+	}
+
+	if saved, err := config.ConfigToMap(cfg); err == nil {
+		b.working = saved
+		b.original = deepCopy(saved).(map[string]interface{})
+	}
+	b.dirty = false
+	b.status = "Configuration saved"
+}
+
+// revert restores the loaded value for the selected path or section
+func (b *Browser) revert(n *node) {
+	value, ok := config.LookupPath(b.original, n.path)
+	if !ok {
+		b.status = fmt.Sprintf("%s has no saved value", n.path)
+		return
+	}
+	b.apply(n.path, deepCopy(value))
+	b.status = fmt.Sprintf("Reverted %s", n.path)
+}
+
+// revertAll discards every unsaved change
+func (b *Browser) revertAll() {
+	b.working = deepCopy(b.original).(map[string]interface{})
+	b.dirty = false
+	b.revalidate()
+	b.status = "Reverted all unsaved changes"
+}
+
+// resetToDefault restores the profile default for the selected path or section
+func (b *Browser) resetToDefault(n *node) {
+	if b.isLocked(n.path) {
+		b.status = fmt.Sprintf("%s is locked; unlock it before resetting", n.path)
+		return
+	}
+	value, ok := config.LookupPath(b.defaults, n.path)
+	if !ok {
+		b.status = fmt.Sprintf("%s has no default value", n.path)
+		return
+	}
+	b.apply(n.path, deepCopy(value))
+	b.status = fmt.Sprintf("Reset %s to default", n.path)
+}
+
+// revalidate runs the schema validator over the working config
+func (b *Browser) revalidate() {
+	b.issues = make(map[string][]config.ValidationError)
+
+	cfg, err := config.MapToConfig(b.working)
+	if err != nil {
+		return
+	}
+	for _, issue := range b.validator.Validate(cfg) {
+		b.issues[issue.Path] = append(b.issues[issue.Path], issue)
+	}
+}
+
+// hasBlockingIssues reports whether any validation error prevents saving
+func (b *Browser) hasBlockingIssues() bool {
+	for _, list := range b.issues {
+		for _, issue := range list {
+			if issue.Severity == config.SeverityError || issue.Severity == config.SeverityCritical {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isLocked reports whether the path is user-locked in the working config
+func (b *Browser) isLocked(path string) bool {
+	locked := make([]string, 0)
+	if value, ok := config.LookupPath(b.working, "metadata.userLocked"); ok {
+		if list, ok := value.([]interface{}); ok {
+			for _, item := range list {
+				if s, ok := item.(string); ok {
+					locked = append(locked, s)
+				}
+			}
+		}
+	}
+	cfg := &config.ShellConfig{Metadata: config.ConfigMetadata{UserLocked: locked}}
+	return b.injector.IsUserLocked(cfg, path)
+}
+
+// isDefault reports whether the path holds its profile default
+func (b *Browser) isDefault(path string) bool {
+	// Timestamps always differ from a freshly generated profile
+	if path == "metadata.created" || path == "metadata.lastModified" {
+		return true
+	}
+	current, _ := config.LookupPath(b.working, path)
+	def, _ := config.LookupPath(b.defaults, path)
+	return reflect.DeepEqual(current, def)
+}
+
+// value returns the working value at path
+func (b *Browser) value(path string) interface{} {
+	value, _ := config.LookupPath(b.working, path)
+	return value
+}
+
+// moveCursor moves the selection, keeping it on screen
+func (b *Browser) moveCursor(delta int) {
+	rows := b.visibleRows()
+	b.cursor += delta
+	if b.cursor < 0 {
+		b.cursor = 0
+	}
+	if b.cursor >= len(rows) {
+		b.cursor = len(rows) - 1
+	}
+}
+
+// selectParent moves the selection to the enclosing section
+func (b *Browser) selectParent(n *node) {
+	idx := strings.LastIndex(n.path, ".")
+	if idx < 0 {
+		return
+	}
+	parent := n.path[:idx]
+	for i, row := range b.visibleRows() {
+		if row.path == parent {
+			b.cursor = i
+			return
+		}
+	}
+}
+
+// visibleRows flattens the tree according to the expanded sections
+func (b *Browser) visibleRows() []*node {
+	rows := make([]*node, 0)
+	var walk func(children []*node)
+	walk = func(children []*node) {
+		for _, child := range children {
+			rows = append(rows, child)
+			if !child.isLeaf() && b.expanded[child.path] {
+				walk(child.children)
+			}
+		}
+	}
+	walk(b.root.children)
+	return rows
+}
+
+// buildTree arranges schema leaves into nested sections
+func buildTree(fields []config.FieldInfo) *node {
+	root := &node{}
+	for idx := range fields {
+		field := fields[idx]
+		parts := strings.Split(field.Path, ".")
+		current := root
+		for depth, part := range parts {
+			path := strings.Join(parts[:depth+1], ".")
+			var child *node
+			for _, c := range current.children {
+				if c.name == part {
+					child = c
+					break
+				}
+			}
+			if child == nil {
+				child = &node{name: part, path: path, depth: depth}
+				current.children = append(current.children, child)
+			}
+			if depth == len(parts)-1 {
+				child.field = &field
+			}
+			current = child
+		}
+	}
+	return root
+}
+
+// deepCopy copies a generic JSON value
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[k] = deepCopy(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = deepCopy(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,219 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"heimdall-cli/config"
+)
+
+// memoryStore keeps the last saved configuration
+type memoryStore struct {
+	saved *config.ShellConfig
+	count int
+}
+
+func (s *memoryStore) Save(cfg *config.ShellConfig) error {
+	s.saved = cfg
+	s.count++
+	return nil
+}
+
+func newTestBrowser(t *testing.T, cfg *config.ShellConfig) (*Browser, *memoryStore) {
+	t.Helper()
+	t.Setenv("HEIMDALL_PROFILES_DIR", t.TempDir())
+	store := &memoryStore{}
+	browser, err := NewBrowser(cfg, store, nil)
+	if err != nil {
+		t.Fatalf("NewBrowser: %v", err)
+	}
+	return browser, store
+}
+
+// play runs a key script through the browser
+func play(t *testing.T, b *Browser, script string) {
+	t.Helper()
+	input, err := ParseScript(script)
+	if err != nil {
+		t.Fatalf("ParseScript(%q): %v", script, err)
+	}
+	if err := b.Run(input); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+}
+
+// goTo moves the cursor onto path with scripted keys, expanding every
+// enclosing section on the way
+func goTo(t *testing.T, b *Browser, path string) {
+	t.Helper()
+	parts := strings.Split(path, ".")
+	for depth := range parts {
+		target := strings.Join(parts[:depth+1], ".")
+		index := -1
+		for i, row := range b.visibleRows() {
+			if row.path == target {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			t.Fatalf("%s is not visible", target)
+		}
+
+		keys := make([]string, 0)
+		for i := b.cursor; i < index; i++ {
+			keys = append(keys, "down")
+		}
+		for i := b.cursor; i > index; i-- {
+			keys = append(keys, "up")
+		}
+		if depth < len(parts)-1 {
+			keys = append(keys, "right")
+		}
+		play(t, b, strings.Join(keys, " "))
+	}
+}
+
+func TestBrowserPickAndSave(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.Bar.Position = "top"
+	b, store := newTestBrowser(t, cfg)
+
+	goTo(t, b, "bar.position")
+	play(t, b, "enter down enter")
+	if !b.Dirty() {
+		t.Fatal("expected unsaved changes after picking a value")
+	}
+
+	play(t, b, "s")
+	if store.saved == nil {
+		t.Fatalf("nothing saved, status %q", b.Status())
+	}
+	if store.saved.Bar.Position != "bottom" {
+		t.Errorf("saved bar.position = %q, want bottom", store.saved.Bar.Position)
+	}
+	if b.Dirty() {
+		t.Error("still dirty after saving")
+	}
+}
+
+func TestBrowserEditRefusesInvalidSave(t *testing.T) {
+	b, store := newTestBrowser(t, config.GetDefaultConfig())
+
+	goTo(t, b, "bar.height")
+	play(t, b, "enter backspace backspace backspace type:-5 enter s")
+	if store.count != 0 {
+		t.Error("saved a configuration with a negative bar height")
+	}
+	if !strings.Contains(b.Status(), "Save refused") {
+		t.Errorf("status = %q, want a refusal", b.Status())
+	}
+
+	play(t, b, "R s")
+	if store.count != 1 {
+		t.Errorf("saved %d times after reverting, want 1", store.count)
+	}
+}
+
+func TestBrowserLockedPath(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.Metadata.UserLocked = []string{"bar"}
+	b, _ := newTestBrowser(t, cfg)
+
+	goTo(t, b, "bar.height")
+	play(t, b, "enter")
+	if b.mode != modeBrowse {
+		t.Error("started editing a locked path")
+	}
+	if !strings.Contains(b.Status(), "locked") {
+		t.Errorf("status = %q, want a lock notice", b.Status())
+	}
+}
+
+func TestBrowserResetToDefault(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	want := cfg.Bar.Height
+	cfg.Bar.Height = want + 10
+	b, _ := newTestBrowser(t, cfg)
+
+	goTo(t, b, "bar.height")
+	play(t, b, "d")
+	edited, err := b.Config()
+	if err != nil {
+		t.Fatalf("Config: %v", err)
+	}
+	if edited.Bar.Height != want {
+		t.Errorf("bar.height = %d, want default %d", edited.Bar.Height, want)
+	}
+}
+
+func TestBrowserQuitConfirmation(t *testing.T) {
+	b, _ := newTestBrowser(t, config.GetDefaultConfig())
+	goTo(t, b, "bar.position")
+	play(t, b, "right")
+
+	if b.HandleKey(Key{Type: KeyRune, Rune: 'q'}) {
+		t.Fatal("quit with unsaved changes on the first q")
+	}
+	if !b.HandleKey(Key{Type: KeyRune, Rune: 'q'}) {
+		t.Error("did not quit on the second q")
+	}
+}
+
+func TestBrowserInterrupt(t *testing.T) {
+	b, _ := newTestBrowser(t, config.GetDefaultConfig())
+	goTo(t, b, "bar.height")
+
+	// Ctrl+C cancels an edit instead of typing into the value
+	play(t, b, "enter type:1 ctrl+c")
+	if b.mode != modeBrowse || b.Dirty() {
+		t.Fatalf("mode %v, dirty %v after Ctrl+C in an edit", b.mode, b.Dirty())
+	}
+
+	// With unsaved changes it asks like q does
+	play(t, b, "enter type:1 enter")
+	if b.HandleKey(Key{Type: KeyInterrupt}) {
+		t.Fatal("Ctrl+C quit with unsaved changes without asking")
+	}
+	if !b.HandleKey(Key{Type: KeyInterrupt}) {
+		t.Error("did not quit on the second Ctrl+C")
+	}
+}
+
+func TestTerminalInput(t *testing.T) {
+	input := NewTerminalInput(strings.NewReader("a\x03\x1b[A\r"))
+	want := []Key{{Type: KeyRune, Rune: 'a'}, {Type: KeyInterrupt}, {Type: KeyUp}, {Type: KeyEnter}}
+	for i, w := range want {
+		key, err := input.ReadKey()
+		if err != nil {
+			t.Fatalf("key %d: %v", i, err)
+		}
+		if key != w {
+			t.Errorf("key %d = %+v, want %+v", i, key, w)
+		}
+	}
+}
+
+func TestParseScript(t *testing.T) {
+	input, err := ParseScript("down # comment\nright type:ab space enter")
+	if err != nil {
+		t.Fatalf("ParseScript: %v", err)
+	}
+	want := []Key{
+		{Type: KeyDown}, {Type: KeyRight},
+		{Type: KeyRune, Rune: 'a'}, {Type: KeyRune, Rune: 'b'},
+		{Type: KeyRune, Rune: ' '}, {Type: KeyEnter},
+	}
+	if len(input.keys) != len(want) {
+		t.Fatalf("got %d keys, want %d: %+v", len(input.keys), len(want), input.keys)
+	}
+	for i := range want {
+		if input.keys[i] != want[i] {
+			t.Errorf("key %d = %+v, want %+v", i, input.keys[i], want[i])
+		}
+	}
+
+	if _, err := ParseScript("upp"); err == nil {
+		t.Error("expected an error for an unknown key name")
+	}
+}
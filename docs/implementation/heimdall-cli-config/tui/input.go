@@ -0,0 +1,172 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// KeyType identifies a key press
+type KeyType int
+
+const (
+	KeyRune KeyType = iota
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyEnter
+	KeyEscape
+	KeyBackspace
+	KeyTab
+	// KeyInterrupt is Ctrl+C: it cancels an edit, or quits like q
+	KeyInterrupt
+)
+
+// Key is a single key press delivered to the browser
+type Key struct {
+	Type KeyType
+	Rune rune
+}
+
+// InputSource delivers key presses to the browser.
+// ReadKey returns io.EOF when no more input is available.
+type InputSource interface {
+	ReadKey() (Key, error)
+}
+
+// TerminalInput decodes key presses from a raw-mode terminal
+type TerminalInput struct {
+	reader *bufio.Reader
+}
+
+// NewTerminalInput creates an input source reading raw bytes from r
+func NewTerminalInput(r io.Reader) *TerminalInput {
+	return &TerminalInput{reader: bufio.NewReader(r)}
+}
+
+// ReadKey reads and decodes the next key press
+func (t *TerminalInput) ReadKey() (Key, error) {
+	r, _, err := t.reader.ReadRune()
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch r {
+	case '\r', '\n':
+		return Key{Type: KeyEnter}, nil
+	case '\t':
+		return Key{Type: KeyTab}, nil
+	case 127, 8:
+		return Key{Type: KeyBackspace}, nil
+	case 3:
+		return Key{Type: KeyInterrupt}, nil
+	case 27:
+		// A lone escape has nothing buffered behind it
+		if t.reader.Buffered() == 0 {
+			return Key{Type: KeyEscape}, nil
+		}
+		next, _, err := t.reader.ReadRune()
+		if err != nil || (next != '[' && next != 'O') {
+			return Key{Type: KeyEscape}, nil
+		}
+		code, _, err := t.reader.ReadRune()
+		if err != nil {
+			return Key{Type: KeyEscape}, nil
+		}
+		switch code {
+		case 'A':
+			return Key{Type: KeyUp}, nil
+		case 'B':
+			return Key{Type: KeyDown}, nil
+		case 'C':
+			return Key{Type: KeyRight}, nil
+		case 'D':
+			return Key{Type: KeyLeft}, nil
+		}
+		return Key{Type: KeyEscape}, nil
+	}
+
+	return Key{Type: KeyRune, Rune: r}, nil
+}
+
+// ScriptedInput replays a fixed sequence of key presses, for headless use
+type ScriptedInput struct {
+	keys []Key
+	pos  int
+}
+
+// NewScriptedInput creates an input source from explicit keys
+func NewScriptedInput(keys ...Key) *ScriptedInput {
+	return &ScriptedInput{keys: keys}
+}
+
+// ParseScript builds a scripted input from a whitespace separated script.
+// Tokens are key names (up, down, left, right, enter, esc, backspace, tab,
+// space, ctrl+c), "type:<text>" to type literal text, or single characters. A
+// token starting with # comments out the rest of its line.
+func ParseScript(script string) (*ScriptedInput, error) {
+	keys := make([]Key, 0)
+
+	tokens := make([]string, 0)
+	for _, line := range strings.Split(script, "\n") {
+		for _, token := range strings.Fields(line) {
+			if strings.HasPrefix(token, "#") {
+				break
+			}
+			tokens = append(tokens, token)
+		}
+	}
+
+	for _, token := range tokens {
+		if text, ok := strings.CutPrefix(token, "type:"); ok {
+			for _, r := range text {
+				keys = append(keys, Key{Type: KeyRune, Rune: r})
+			}
+			continue
+		}
+
+		switch strings.ToLower(token) {
+		case "up":
+			keys = append(keys, Key{Type: KeyUp})
+		case "down":
+			keys = append(keys, Key{Type: KeyDown})
+		case "left":
+			keys = append(keys, Key{Type: KeyLeft})
+		case "right":
+			keys = append(keys, Key{Type: KeyRight})
+		case "enter":
+			keys = append(keys, Key{Type: KeyEnter})
+		case "esc", "escape":
+			keys = append(keys, Key{Type: KeyEscape})
+		case "backspace":
+			keys = append(keys, Key{Type: KeyBackspace})
+		case "tab":
+			keys = append(keys, Key{Type: KeyTab})
+		case "space":
+			keys = append(keys, Key{Type: KeyRune, Rune: ' '})
+		case "ctrl+c":
+			keys = append(keys, Key{Type: KeyInterrupt})
+		default:
+			if utf8.RuneCountInString(token) != 1 {
+				return nil, fmt.Errorf("unknown key in script: %s", token)
+			}
+			r, _ := utf8.DecodeRuneInString(token)
+			keys = append(keys, Key{Type: KeyRune, Rune: r})
+		}
+	}
+
+	return NewScriptedInput(keys...), nil
+}
+
+// ReadKey returns the next scripted key, or io.EOF once exhausted
+func (s *ScriptedInput) ReadKey() (Key, error) {
+	if s.pos >= len(s.keys) {
+		return Key{}, io.EOF
+	}
+	key := s.keys[s.pos]
+	s.pos++
+	return key, nil
+}
@@ -0,0 +1,185 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"heimdall-cli/config"
+)
+
+const (
+	ansiClear   = "\x1b[H\x1b[2J"
+	ansiReset   = "\x1b[0m"
+	ansiReverse = "\x1b[7m"
+	ansiDim     = "\x1b[2m"
+	ansiRed     = "\x1b[31m"
+	ansiYellow  = "\x1b[33m"
+	ansiCyan    = "\x1b[36m"
+)
+
+var hexColorPattern = regexp.MustCompile(`^#([A-Fa-f0-9]{6}|[A-Fa-f0-9]{8})$`)
+
+// headerRows and footerRows are the lines reserved around the tree
+const (
+	headerRows = 2
+	footerRows = 3
+)
+
+// render draws the full browser frame
+func (b *Browser) render() {
+	if b.out == nil {
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(ansiClear)
+
+	title := "Heimdall configuration"
+	if b.dirty {
+		title += " [modified]"
+	}
+	sb.WriteString(title + "\r\n")
+	sb.WriteString(ansiDim + "↑/↓ move  →/← expand/cycle  enter edit  d default  r revert  R revert all  s save  q quit" + ansiReset + "\r\n")
+
+	rows := b.visibleRows()
+	visible := b.height - headerRows - footerRows
+	if visible < 1 {
+		visible = 1
+	}
+	if b.cursor < b.offset {
+		b.offset = b.cursor
+	}
+	if b.cursor >= b.offset+visible {
+		b.offset = b.cursor - visible + 1
+	}
+
+	for i := b.offset; i < len(rows) && i < b.offset+visible; i++ {
+		sb.WriteString(b.renderRow(rows[i], i == b.cursor))
+		sb.WriteString("\r\n")
+	}
+
+	sb.WriteString("\r\n")
+	sb.WriteString(b.renderPrompt())
+	sb.WriteString("\r\n")
+	sb.WriteString(b.renderSummary())
+
+	fmt.Fprint(b.out, sb.String())
+}
+
+// renderRow draws one tree row
+func (b *Browser) renderRow(n *node, selected bool) string {
+	indent := strings.Repeat("  ", n.depth)
+
+	var line string
+	if !n.isLeaf() {
+		marker := "▸"
+		if b.expanded[n.path] {
+			marker = "▾"
+		}
+		line = fmt.Sprintf("%s%s %s", indent, marker, n.name)
+		if count := b.issueCountUnder(n.path); count > 0 {
+			line += fmt.Sprintf(" %s(%d issues)%s", ansiRed, count, ansiReset)
+		}
+	} else {
+		value := config.FormatValue(b.value(n.path))
+		flags := " "
+		if b.isLocked(n.path) {
+			flags = "L"
+		} else if !b.isDefault(n.path) {
+			flags = "*"
+		}
+		line = fmt.Sprintf("%s%s %s = %s", indent, flags, n.name, value)
+		if swatch := colorSwatch(n.path, value); swatch != "" {
+			line += " " + swatch
+		}
+		if len(n.field.Enum) > 0 {
+			line += ansiDim + " ‹" + strings.Join(n.field.Enum, "|") + "›" + ansiReset
+		}
+		for _, issue := range b.issues[n.path] {
+			color := ansiRed
+			if issue.Severity == config.SeverityWarning {
+				color = ansiYellow
+			}
+			line += fmt.Sprintf(" %s✗ %s%s", color, issue.Message, ansiReset)
+		}
+	}
+
+	if selected {
+		return ansiReverse + line + ansiReset
+	}
+	return line
+}
+
+// renderPrompt draws the edit line, the picker or the status message
+func (b *Browser) renderPrompt() string {
+	switch b.mode {
+	case modeEdit:
+		n := b.visibleRows()[b.cursor]
+		return fmt.Sprintf("%s%s:%s %s_", ansiCyan, n.path, ansiReset, string(b.input))
+	case modePick:
+		n := b.visibleRows()[b.cursor]
+		options := make([]string, len(b.pickOptions))
+		for i, option := range b.pickOptions {
+			if i == b.pickIndex {
+				options[i] = ansiReverse + option + ansiReset
+			} else {
+				options[i] = option
+			}
+		}
+		return fmt.Sprintf("%s%s:%s %s", ansiCyan, n.path, ansiReset, strings.Join(options, "  "))
+	}
+	return b.status
+}
+
+// renderSummary draws the validation totals
+func (b *Browser) renderSummary() string {
+	errorsCount, warnings := 0, 0
+	for _, list := range b.issues {
+		for _, issue := range list {
+			if issue.Severity == config.SeverityWarning {
+				warnings++
+			} else {
+				errorsCount++
+			}
+		}
+	}
+	if errorsCount == 0 && warnings == 0 {
+		return ansiDim + "✓ valid" + ansiReset
+	}
+	return fmt.Sprintf("%s%d errors%s, %s%d warnings%s", ansiRed, errorsCount, ansiReset, ansiYellow, warnings, ansiReset)
+}
+
+// issueCountUnder counts validation issues at or below a section
+func (b *Browser) issueCountUnder(path string) int {
+	count := 0
+	for issuePath, list := range b.issues {
+		if issuePath == path || strings.HasPrefix(issuePath, path+".") {
+			count += len(list)
+		}
+	}
+	return count
+}
+
+// colorSwatch renders a truecolor block for hex color values, and for the
+// other color syntaxes at color paths. References have no swatch.
+func colorSwatch(path, value string) string {
+	if !hexColorPattern.MatchString(value) && !isColorPath(path) {
+		return ""
+	}
+	c, err := config.ParseColor(value)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("\x1b[48;2;%d;%d;%dm    %s", c[0], c[1], c[2], ansiReset)
+}
+
+// isColorPath reports whether path holds a color
+func isColorPath(path string) bool {
+	for _, p := range config.ColorPaths {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}